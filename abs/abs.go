@@ -0,0 +1,107 @@
+package abs
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/aniagut/msc-bbs-plus-plus/models"
+	"github.com/aniagut/msc-bbs-plus-plus/predicate"
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+)
+
+// signatureDomain separates attribute-based document signatures from presentations.
+const signatureDomain = "BBS++-ATTRIBUTE-BASED-SIGNATURE-V1"
+
+// Policy states what a signer must hold: messages disclosed with required values, and predicates
+// over the hidden messages.
+type Policy struct {
+	Disclosed  map[int]string
+	Predicates []predicate.Predicate
+}
+
+// Signature is an attribute-based signature on a document: a signature of knowledge of a BBS++
+// signature whose messages satisfy the policy, with the document digest and the policy as the
+// message. It reveals only the disclosed values of the policy.
+type Signature struct {
+	Presentation predicate.Presentation
+}
+
+// Sign signs a document as "a holder of a credential satisfying the policy".
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - signature: The credential signature on the messages.
+//   - m: The full message vector.
+//   - policy: The policy the signature proves.
+//   - document: The document to be signed.
+//
+// Returns:
+//   - Signature: The attribute-based signature.
+//   - error: An error if the credential does not satisfy the policy (a *predicate.PredicateError for
+//     predicates) or the proof cannot be generated.
+func Sign(publicParams models.PublicParameters, signature models.Signature, m []string, policy Policy, document []byte) (Signature, error) {
+	disclosed := make([]int, 0, len(policy.Disclosed))
+	for i, value := range policy.Disclosed {
+		if i < 0 || i >= len(m) {
+			return Signature{}, errors.New("policy index out of range")
+		}
+		if m[i] != value {
+			return Signature{}, fmt.Errorf("message %d does not have the value required by the policy", i)
+		}
+		disclosed = append(disclosed, i)
+	}
+	sort.Ints(disclosed)
+
+	presentation, err := predicate.Present(publicParams, signature, m, disclosed, policy.Predicates, message(policy, document))
+	if err != nil {
+		return Signature{}, err
+	}
+	return Signature{Presentation: presentation}, nil
+}
+
+// Verify checks an attribute-based signature on a document against the issuer's key and the policy.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - verificationKey: The verification key of the issuer.
+//   - policy: The policy the signature must prove.
+//   - document: The signed document.
+//   - signature: The signature to be verified.
+//
+// Returns:
+//   - boolean: True if the signature is valid, false otherwise.
+//   - error: A *predicate.PredicateError naming a failing predicate, or another error if the
+//     signature is malformed.
+func Verify(publicParams models.PublicParameters, verificationKey models.VerificationKey, policy Policy, document []byte, signature Signature) (bool, error) {
+	return predicate.Verify(publicParams, verificationKey, signature.Presentation, policy.Disclosed, policy.Predicates, message(policy, document))
+}
+
+// message encodes the document digest and the policy as the message of the signature of knowledge.
+func message(policy Policy, document []byte) []byte {
+	digest := sha256.Sum256(document)
+	indexes := make([]int, 0, len(policy.Disclosed))
+	for i := range policy.Disclosed {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+
+	inputs := [][]byte{[]byte(signatureDomain), digest[:], uint64Bytes(len(indexes))}
+	for _, i := range indexes {
+		inputs = append(inputs, uint64Bytes(i), []byte(policy.Disclosed[i]))
+	}
+	inputs = append(inputs, uint64Bytes(len(policy.Predicates)))
+	for _, p := range policy.Predicates {
+		inputs = append(inputs, []byte(p.String()))
+	}
+	return utils.ScalarToBytes(utils.HashToScalar(inputs...))
+}
+
+// uint64Bytes encodes an integer in 8 big-endian bytes.
+func uint64Bytes(v int) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(v))
+	return buf[:]
+}
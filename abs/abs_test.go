@@ -0,0 +1,64 @@
+package abs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aniagut/msc-bbs-plus-plus/keygen"
+	"github.com/aniagut/msc-bbs-plus-plus/predicate"
+	"github.com/aniagut/msc-bbs-plus-plus/sign"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSignVerify tests signing a document under a policy and verifying it.
+func TestSignVerify(t *testing.T) {
+	result, err := keygen.KeyGen(4)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	publicParams := result.PublicParameters
+	messages := []string{"alice", "employee", "engineering", predicate.EncodeInt(40)}
+	signature, err := sign.Sign(publicParams, result.SigningKey, messages)
+	assert.NoError(t, err, "Sign should not return an error")
+	policy := Policy{
+		Disclosed:  map[int]string{1: "employee"},
+		Predicates: []predicate.Predicate{predicate.NotEqual(2, "sales"), predicate.MultipleOf(3, 8)},
+	}
+	document := []byte("quarterly report")
+
+	documentSignature, err := Sign(publicParams, signature, messages, policy, document)
+	assert.NoError(t, err, "Sign should not return an error")
+
+	isValid, err := Verify(publicParams, result.VerificationKey, policy, document, documentSignature)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.True(t, isValid, "Verify should accept a valid signature")
+
+	isValid, err = Verify(publicParams, result.VerificationKey, policy, []byte("other report"), documentSignature)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.False(t, isValid, "Verify should reject another document")
+
+	weaker := Policy{Disclosed: policy.Disclosed, Predicates: policy.Predicates[:1]}
+	_, err = Verify(publicParams, result.VerificationKey, weaker, document, documentSignature)
+	assert.Error(t, err, "Verify should reject a signature under another policy")
+
+	other, err := keygen.KeyGen(4)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	isValid, err = Verify(publicParams, other.VerificationKey, policy, document, documentSignature)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.False(t, isValid, "Verify should reject another issuer")
+}
+
+// TestPolicyNotSatisfied tests that a holder cannot sign under a policy the credential does not satisfy.
+func TestPolicyNotSatisfied(t *testing.T) {
+	result, err := keygen.KeyGen(3)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	publicParams := result.PublicParameters
+	messages := []string{"bob", "contractor", "sales"}
+	signature, err := sign.Sign(publicParams, result.SigningKey, messages)
+	assert.NoError(t, err, "Sign should not return an error")
+
+	_, err = Sign(publicParams, signature, messages, Policy{Disclosed: map[int]string{1: "employee"}}, []byte("doc"))
+	assert.Error(t, err, "Sign should reject a disclosed value outside the policy")
+
+	_, err = Sign(publicParams, signature, messages, Policy{Predicates: []predicate.Predicate{predicate.NotEqual(2, "sales")}}, []byte("doc"))
+	var predicateErr *predicate.PredicateError
+	assert.True(t, errors.As(err, &predicateErr), "Sign should report the unsatisfied predicate")
+}
@@ -0,0 +1,323 @@
+package backup
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aniagut/msc-bbs-plus-plus/models"
+	"github.com/aniagut/msc-bbs-plus-plus/shamir"
+	e "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+// Version is the current version of the share serialization format.
+const Version = 1
+
+// Serialization format (all integers big-endian):
+//
+//	magic "BBSK" | version (1) | threshold (2) | index (2) | share (32) |
+//	commitment count (2) | commitments (96 each, compressed G2) | checksum (4)
+//
+// The checksum is the first 4 bytes of SHA-256 over all preceding bytes.
+const (
+	magic        = "BBSK"
+	checksumSize = 4
+	headerSize   = len(magic) + 1 + 2 + 2 + e.ScalarSize + 2
+	g2Size       = 96
+	textPrefix   = "bbsk1-"
+)
+
+// textEncoding is used for the printable form of a share.
+var textEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Share is one share of a backed-up signing key. It carries the Feldman commitments
+// g₂^{a_k} of the sharing polynomial, so it can be checked on its own; the first
+// commitment equals the verification key X₂. All shares of a key carry the same commitments.
+type Share struct {
+	Version     byte
+	Threshold   int
+	Index       int
+	Value       *e.Scalar
+	Commitments []*e.G2
+}
+
+// CorruptShareError reports a share that does not match the Feldman commitments or the verification key.
+type CorruptShareError struct {
+	Index  int
+	Reason string
+}
+
+func (err *CorruptShareError) Error() string {
+	return fmt.Sprintf("share %d is corrupted: %s", err.Index, err.Reason)
+}
+
+// Split splits a signing key into n shares so that any t of them recover it.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - signingKey: The signing key to back up.
+//   - verificationKey: The matching verification key.
+//   - n: The number of shares.
+//   - t: The number of shares needed for recovery.
+//
+// Returns:
+//   - []Share: The shares, with indexes 1..n.
+//   - error: An error if the keys do not match or the parameters are invalid.
+func Split(publicParams models.PublicParameters, signingKey models.SigningKey, verificationKey models.VerificationKey, n, t int) ([]Share, error) {
+	if n > 0xffff {
+		return nil, errors.New("too many shares")
+	}
+	gx := new(e.G2)
+	gx.ScalarMult(signingKey.X, publicParams.G2)
+	if !gx.IsEqual(verificationKey.X2) {
+		return nil, errors.New("signing key does not match the verification key")
+	}
+
+	shares, f, err := shamir.Split(signingKey.X, n, t)
+	if err != nil {
+		return nil, err
+	}
+
+	// Feldman commitments A_k = g₂^{a_k}
+	commitments := make([]*e.G2, len(f))
+	for k, a := range f {
+		commitments[k] = new(e.G2)
+		commitments[k].ScalarMult(a, publicParams.G2)
+	}
+
+	result := make([]Share, n)
+	for i, share := range shares {
+		result[i] = Share{
+			Version:     Version,
+			Threshold:   t,
+			Index:       share.Index,
+			Value:       share.Value,
+			Commitments: commitments,
+		}
+	}
+	return result, nil
+}
+
+// VerifyShare checks a share against its Feldman commitments and the verification key:
+// g₂^{s} = ∏_k A_k^{i^k} and A_0 = X₂.
+func VerifyShare(publicParams models.PublicParameters, verificationKey models.VerificationKey, share Share) error {
+	if share.Value == nil || len(share.Commitments) != share.Threshold || share.Threshold < 1 {
+		return &CorruptShareError{Index: share.Index, Reason: "malformed share"}
+	}
+	if !share.Commitments[0].IsEqual(verificationKey.X2) {
+		return &CorruptShareError{Index: share.Index, Reason: "commitments do not match the verification key"}
+	}
+
+	index := new(e.Scalar)
+	index.SetUint64(uint64(share.Index))
+	power := new(e.Scalar)
+	power.SetOne()
+	expected := new(e.G2)
+	expected.SetIdentity()
+	term := new(e.G2)
+	for _, commitment := range share.Commitments {
+		term.ScalarMult(power, commitment)
+		expected.Add(expected, term)
+		power.Mul(power, index)
+	}
+	gs := new(e.G2)
+	gs.ScalarMult(share.Value, publicParams.G2)
+	if !gs.IsEqual(expected) {
+		return &CorruptShareError{Index: share.Index, Reason: "share does not match the commitments"}
+	}
+	return nil
+}
+
+// Recover verifies the given shares and reconstructs the signing key. All shares are checked
+// before reconstruction, so a corrupted share is reported instead of producing a wrong key.
+//
+// A share is only consistent with its own commitments, and a forger can choose commitments that
+// fit a forged value, so all shares must also carry the same commitments. Shares whose commitments
+// differ from those of most shares are reported as corrupted; without a majority, the commitments
+// of the first share are taken.
+//
+// Returns:
+//   - SigningKey: The recovered signing key.
+//   - error: A *CorruptShareError for the first corrupted share, or another error if recovery fails.
+func Recover(publicParams models.PublicParameters, verificationKey models.VerificationKey, shares []Share) (models.SigningKey, error) {
+	if len(shares) == 0 {
+		return models.SigningKey{}, errors.New("no shares to recover from")
+	}
+	t := shares[0].Threshold
+	seen := make(map[int]bool, len(shares))
+	selected := make([]shamir.Share, 0, len(shares))
+	for _, share := range shares {
+		if share.Version != Version {
+			return models.SigningKey{}, errors.New("unsupported share version")
+		}
+		if share.Threshold != t {
+			return models.SigningKey{}, &CorruptShareError{Index: share.Index, Reason: "threshold differs from the other shares"}
+		}
+		if seen[share.Index] {
+			return models.SigningKey{}, errors.New("duplicate share index")
+		}
+		seen[share.Index] = true
+		if err := VerifyShare(publicParams, verificationKey, share); err != nil {
+			return models.SigningKey{}, err
+		}
+		selected = append(selected, shamir.Share{Index: share.Index, Value: share.Value})
+	}
+	trusted := majorityCommitments(shares)
+	for _, share := range shares {
+		if !sameCommitments(share.Commitments, trusted) {
+			return models.SigningKey{}, &CorruptShareError{Index: share.Index, Reason: "commitments differ from the other shares"}
+		}
+	}
+	if len(selected) < t {
+		return models.SigningKey{}, fmt.Errorf("need %d shares, got %d", t, len(selected))
+	}
+
+	x, err := shamir.Combine(selected[:t])
+	if err != nil {
+		return models.SigningKey{}, err
+	}
+	gx := new(e.G2)
+	gx.ScalarMult(x, publicParams.G2)
+	if !gx.IsEqual(verificationKey.X2) {
+		return models.SigningKey{}, errors.New("recovered key does not match the verification key")
+	}
+	return models.SigningKey{X: x}, nil
+}
+
+// majorityCommitments returns the commitments carried by most shares, preferring earlier shares on ties.
+func majorityCommitments(shares []Share) []*e.G2 {
+	best, bestCount := 0, 0
+	for i := range shares {
+		count := 0
+		for j := range shares {
+			if sameCommitments(shares[i].Commitments, shares[j].Commitments) {
+				count++
+			}
+		}
+		if count > bestCount {
+			best, bestCount = i, count
+		}
+	}
+	return shares[best].Commitments
+}
+
+// sameCommitments reports whether two commitment vectors are equal.
+func sameCommitments(a, b []*e.G2) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k := range a {
+		if !a[k].IsEqual(b[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+// MarshalBinary serializes a share in the versioned binary format with a checksum.
+func (s Share) MarshalBinary() ([]byte, error) {
+	if s.Value == nil || len(s.Commitments) > 0xffff || s.Threshold > 0xffff || s.Index > 0xffff || s.Index < 0 {
+		return nil, errors.New("share cannot be serialized")
+	}
+	var buf bytes.Buffer
+	buf.WriteString(magic)
+	buf.WriteByte(s.Version)
+	var u16 [2]byte
+	binary.BigEndian.PutUint16(u16[:], uint16(s.Threshold))
+	buf.Write(u16[:])
+	binary.BigEndian.PutUint16(u16[:], uint16(s.Index))
+	buf.Write(u16[:])
+	value, err := s.Value.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	buf.Write(value)
+	binary.BigEndian.PutUint16(u16[:], uint16(len(s.Commitments)))
+	buf.Write(u16[:])
+	for _, commitment := range s.Commitments {
+		buf.Write(commitment.BytesCompressed())
+	}
+	checksum := sha256.Sum256(buf.Bytes())
+	buf.Write(checksum[:checksumSize])
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary parses a share and validates its version and checksum.
+func (s *Share) UnmarshalBinary(data []byte) error {
+	if len(data) < headerSize+checksumSize || string(data[:len(magic)]) != magic {
+		return errors.New("not a signing key share")
+	}
+	body, checksum := data[:len(data)-checksumSize], data[len(data)-checksumSize:]
+	expected := sha256.Sum256(body)
+	if !bytes.Equal(checksum, expected[:checksumSize]) {
+		return errors.New("share checksum mismatch")
+	}
+
+	offset := len(magic)
+	version := body[offset]
+	if version != Version {
+		return errors.New("unsupported share version")
+	}
+	offset++
+	threshold := int(binary.BigEndian.Uint16(body[offset:]))
+	index := int(binary.BigEndian.Uint16(body[offset+2:]))
+	offset += 4
+	value := new(e.Scalar)
+	if err := value.UnmarshalBinary(body[offset : offset+e.ScalarSize]); err != nil {
+		return errors.New("invalid share value")
+	}
+	offset += e.ScalarSize
+	count := int(binary.BigEndian.Uint16(body[offset:]))
+	offset += 2
+	if len(body) != offset+count*g2Size {
+		return errors.New("invalid share length")
+	}
+	commitments := make([]*e.G2, count)
+	for k := range commitments {
+		commitments[k] = new(e.G2)
+		if err := commitments[k].SetBytes(body[offset : offset+g2Size]); err != nil {
+			return errors.New("invalid share commitment")
+		}
+		offset += g2Size
+	}
+
+	*s = Share{
+		Version:     version,
+		Threshold:   threshold,
+		Index:       index,
+		Value:       value,
+		Commitments: commitments,
+	}
+	return nil
+}
+
+// Encode returns a printable form of the share (base32 of the binary format).
+func (s Share) Encode() (string, error) {
+	data, err := s.MarshalBinary()
+	if err != nil {
+		return "", err
+	}
+	return textPrefix + textEncoding.EncodeToString(data), nil
+}
+
+// Decode parses a share from its printable form. Whitespace is ignored, so shares
+// printed in groups can be typed back in as they appear.
+func Decode(text string) (Share, error) {
+	text = strings.Join(strings.Fields(text), "")
+	if !strings.HasPrefix(text, textPrefix) {
+		return Share{}, errors.New("not a signing key share")
+	}
+	data, err := textEncoding.DecodeString(strings.ToUpper(strings.TrimPrefix(text, textPrefix)))
+	if err != nil {
+		return Share{}, errors.New("share is not valid base32")
+	}
+	var share Share
+	if err := share.UnmarshalBinary(data); err != nil {
+		return Share{}, err
+	}
+	return share, nil
+}
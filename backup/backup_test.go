@@ -0,0 +1,146 @@
+package backup
+
+import (
+	"testing"
+
+	"github.com/aniagut/msc-bbs-plus-plus/keygen"
+	"github.com/aniagut/msc-bbs-plus-plus/models"
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	e "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/stretchr/testify/assert"
+)
+
+// setup generates a key and splits it into 5 shares with threshold 3.
+func setup(t *testing.T) (models.KeyGenResult, []Share) {
+	result, err := keygen.KeyGen(2)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	shares, err := Split(result.PublicParameters, result.SigningKey, result.VerificationKey, 5, 3)
+	assert.NoError(t, err, "Split should not return an error")
+	return result, shares
+}
+
+// TestSplitRecover tests that any t shares recover the signing key.
+func TestSplitRecover(t *testing.T) {
+	result, shares := setup(t)
+
+	for _, subset := range [][]Share{shares[:3], {shares[4], shares[1], shares[3]}, shares} {
+		key, err := Recover(result.PublicParameters, result.VerificationKey, subset)
+		assert.NoError(t, err, "Recover should not return an error")
+		assert.Equal(t, 1, key.X.IsEqual(result.SigningKey.X), "Recover should return the original key")
+	}
+}
+
+// TestRecoverTooFewShares tests that recovery fails with fewer than t shares.
+func TestRecoverTooFewShares(t *testing.T) {
+	result, shares := setup(t)
+	_, err := Recover(result.PublicParameters, result.VerificationKey, shares[:2])
+	assert.Error(t, err, "Recover should fail with too few shares")
+}
+
+// TestRecoverCorruptedShare tests that a corrupted share is detected before reconstruction.
+func TestRecoverCorruptedShare(t *testing.T) {
+	result, shares := setup(t)
+	shares[1].Value.SetUint64(12345)
+
+	_, err := Recover(result.PublicParameters, result.VerificationKey, shares[:3])
+	var corrupt *CorruptShareError
+	assert.ErrorAs(t, err, &corrupt, "Recover should report the corrupted share")
+	assert.Equal(t, 2, corrupt.Index, "Recover should identify the corrupted share")
+}
+
+// forgeShare returns a share with the given index and a random value, together with commitments
+// that are consistent with it and with the verification key: the last commitment is solved from
+// g₂^{s'} = ∏_k A_k^{i^k}.
+func forgeShare(t *testing.T, publicParams models.PublicParameters, honest Share, index int) Share {
+	value, err := utils.RandomScalar()
+	assert.NoError(t, err, "RandomScalar should not return an error")
+	last := len(honest.Commitments) - 1
+	commitments := append([]*e.G2(nil), honest.Commitments...)
+
+	i := new(e.Scalar)
+	i.SetUint64(uint64(index))
+	power := new(e.Scalar)
+	power.SetOne()
+	rest := new(e.G2)
+	rest.ScalarMult(&value, publicParams.G2)
+	term := new(e.G2)
+	for k := 0; k < last; k++ {
+		term.ScalarMult(utils.Neg(power), commitments[k])
+		rest.Add(rest, term)
+		power.Mul(power, i)
+	}
+	power.Inv(power)
+	commitments[last] = new(e.G2)
+	commitments[last].ScalarMult(power, rest)
+
+	return Share{Version: Version, Threshold: honest.Threshold, Index: index, Value: &value, Commitments: commitments}
+}
+
+// TestRecoverForgedShare tests that a forged share with self-consistent commitments is detected
+// before reconstruction.
+func TestRecoverForgedShare(t *testing.T) {
+	result, shares := setup(t)
+	forged := forgeShare(t, result.PublicParameters, shares[1], 2)
+	assert.NoError(t, VerifyShare(result.PublicParameters, result.VerificationKey, forged), "The forged share should be consistent with its own commitments")
+
+	_, err := Recover(result.PublicParameters, result.VerificationKey, []Share{forged, shares[0], shares[2], shares[3]})
+	var corrupt *CorruptShareError
+	assert.ErrorAs(t, err, &corrupt, "Recover should report the forged share")
+	assert.Equal(t, 2, corrupt.Index, "Recover should identify the forged share")
+}
+
+// TestRecoverWrongVerificationKey tests that shares of another key are rejected.
+func TestRecoverWrongVerificationKey(t *testing.T) {
+	result, shares := setup(t)
+	other, _ := keygen.KeyGen(2)
+
+	err := VerifyShare(result.PublicParameters, other.VerificationKey, shares[0])
+	assert.Error(t, err, "VerifyShare should reject shares of another key")
+}
+
+// TestEncodeDecode tests the printable serialization round trip.
+func TestEncodeDecode(t *testing.T) {
+	result, shares := setup(t)
+
+	decoded := make([]Share, 3)
+	for i, share := range shares[:3] {
+		text, err := share.Encode()
+		assert.NoError(t, err, "Encode should not return an error")
+		decoded[i], err = Decode(text)
+		assert.NoError(t, err, "Decode should not return an error")
+		assert.Equal(t, share.Index, decoded[i].Index)
+	}
+
+	key, err := Recover(result.PublicParameters, result.VerificationKey, decoded)
+	assert.NoError(t, err, "Recover should not return an error")
+	assert.Equal(t, 1, key.X.IsEqual(result.SigningKey.X), "Decoded shares should recover the key")
+}
+
+// TestDecodeChecksum tests that a transcription error is caught by the checksum.
+func TestDecodeChecksum(t *testing.T) {
+	_, shares := setup(t)
+	text, err := shares[0].Encode()
+	assert.NoError(t, err, "Encode should not return an error")
+
+	// Flip one character in the middle of the share
+	runes := []byte(text)
+	k := len(runes) / 2
+	if runes[k] == 'A' {
+		runes[k] = 'B'
+	} else {
+		runes[k] = 'A'
+	}
+	_, err = Decode(string(runes))
+	assert.Error(t, err, "Decode should reject a share with a wrong checksum")
+}
+
+// TestUnmarshalUnsupportedVersion tests that unknown versions are rejected.
+func TestUnmarshalUnsupportedVersion(t *testing.T) {
+	_, shares := setup(t)
+	shares[0].Version = 2
+	data, err := shares[0].MarshalBinary()
+	assert.NoError(t, err, "MarshalBinary should not return an error")
+
+	var share Share
+	assert.Error(t, share.UnmarshalBinary(data), "UnmarshalBinary should reject an unknown version")
+}
@@ -0,0 +1,310 @@
+package blocklist
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"runtime"
+	"sync"
+
+	"github.com/aniagut/msc-bbs-plus-plus/models"
+	"github.com/aniagut/msc-bbs-plus-plus/proof"
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	e "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+const (
+	// presentationDomain separates blocklistable presentation challenges from other hashes.
+	presentationDomain = "BBS++-BLOCKLIST-PRESENTATION-V1"
+	// tagBaseDomain is the domain separation tag used to derive fresh tag bases.
+	tagBaseDomain = "BBS++-BLOCKLIST-TAG-BASE-V1"
+)
+
+// ErrBlocklisted is returned to a holder whose credential matches an entry of the blocklist.
+var ErrBlocklisted = errors.New("credential is on the blocklist")
+
+// Tag is the blocklistable tag carried by a presentation: T = R^s for a fresh base R,
+// where s is the hidden holder secret signed in the credential.
+type Tag struct {
+	R *e.G1
+	T *e.G1
+}
+
+// NonMembershipProof proves that the holder secret s does not match one blocklist entry (R_i, T_i).
+// The prover publishes C = (R_i^s · T_i^{-1})^β ≠ 1 and proves knowledge of (α, β) with
+//   - C = R_i^α · T_i^{-β}
+//   - 1 = R^α · T^{-β}
+//
+// where (R, T) is the tag of the current presentation, which forces α = s·β.
+type NonMembershipProof struct {
+	C        *e.G1
+	AlphaHat *e.Scalar
+	BetaHat  *e.Scalar
+}
+
+// Presentation is a proof of knowledge of a signature together with a tag and a proof
+// that the tag's holder secret is not on the verifier's blocklist.
+type Presentation struct {
+	Proof         models.Proof
+	Tag           Tag
+	NonMembership []NonMembershipProof
+}
+
+// Blocklist is a verifier-local list of tags of presentations made by misbehaving users.
+// It is safe for concurrent use.
+type Blocklist struct {
+	mu      sync.RWMutex
+	entries []Tag
+}
+
+// Add blocks all future presentations of the credential that produced the given tag.
+func (b *Blocklist) Add(tag Tag) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.entries = append(b.entries, tag)
+}
+
+// Entries returns a snapshot of the blocklist that holders prove non-membership against.
+func (b *Blocklist) Entries() []Tag {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	entries := make([]Tag, len(b.entries))
+	copy(entries, b.entries)
+	return entries
+}
+
+// NewHolderSecret generates a random holder secret to be signed as a hidden message.
+func NewHolderSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", errors.New("failed to generate holder secret")
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// Present generates a blocklistable presentation of a credential.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - signature: The signature on the messages.
+//   - m: The full message vector.
+//   - disclosed: The indexes of the messages revealed to the verifier.
+//   - secretIndex: The index of the hidden holder secret in the message vector.
+//   - entries: The verifier's blocklist, as returned by Blocklist.Entries.
+//   - nonce: A verifier-supplied nonce bound to the presentation.
+//
+// Returns:
+//   - Presentation: The generated presentation.
+//   - error: ErrBlocklisted if the credential is blocked, or another error if the proof cannot be generated.
+func Present(publicParams models.PublicParameters, signature models.Signature, m []string, disclosed []int, secretIndex int, entries []Tag, nonce []byte) (Presentation, error) {
+	for _, i := range disclosed {
+		if i == secretIndex {
+			return Presentation{}, errors.New("holder secret must not be disclosed")
+		}
+	}
+
+	// Step 1: Commitment phase of the signature proof
+	prover, err := proof.NewProver(publicParams, signature, m, disclosed, nil)
+	if err != nil {
+		return Presentation{}, err
+	}
+	s := prover.Message(secretIndex)
+	if s == nil {
+		return Presentation{}, errors.New("holder secret index out of range")
+	}
+
+	// Step 2: Compute the tag T = R^s for a fresh base R and commit W = R^{s̃}
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return Presentation{}, errors.New("failed to generate tag base")
+	}
+	tag := Tag{R: utils.HashToG1(salt, tagBaseDomain), T: new(e.G1)}
+	tag.T.ScalarMult(s, tag.R)
+	w := new(e.G1)
+	w.ScalarMult(prover.Blinding(secretIndex), tag.R)
+
+	// Step 3: Commitment phase of the non-membership proofs, one per blocklist entry
+	type witness struct {
+		alpha, beta, alphaTilde, betaTilde *e.Scalar
+		c, u, v                            *e.G1
+		err                                error
+	}
+	witnesses := make([]witness, len(entries))
+	parallelFor(len(entries), func(i int) {
+		entry := entries[i]
+		random, err := utils.RandomScalars(3)
+		if err != nil {
+			witnesses[i].err = err
+			return
+		}
+		beta, alphaTilde, betaTilde := random[0], random[1], random[2]
+		alpha := new(e.Scalar)
+		alpha.Mul(s, beta)
+
+		c := utils.MultiExpG1([]*e.G1{entry.R, entry.T}, []*e.Scalar{alpha, utils.Neg(beta)})
+		if c.IsIdentity() {
+			witnesses[i].err = ErrBlocklisted
+			return
+		}
+		witnesses[i] = witness{
+			alpha:      alpha,
+			beta:       beta,
+			alphaTilde: alphaTilde,
+			betaTilde:  betaTilde,
+			c:          c,
+			u:          utils.MultiExpG1([]*e.G1{entry.R, entry.T}, []*e.Scalar{alphaTilde, utils.Neg(betaTilde)}),
+			v:          utils.MultiExpG1([]*e.G1{tag.R, tag.T}, []*e.Scalar{alphaTilde, utils.Neg(betaTilde)}),
+		}
+	})
+	cs := make([]*e.G1, len(entries))
+	us := make([]*e.G1, len(entries))
+	vs := make([]*e.G1, len(entries))
+	for i, w := range witnesses {
+		if w.err != nil {
+			return Presentation{}, w.err
+		}
+		cs[i], us[i], vs[i] = w.c, w.u, w.v
+	}
+
+	// Step 4: Derive a single challenge over all commitments
+	challenge := presentationChallenge(prover.Bytes(), tag, w, entries, cs, us, vs, nonce)
+
+	// Step 5: Compute the responses
+	nonMembership := make([]NonMembershipProof, len(entries))
+	for i, w := range witnesses {
+		nonMembership[i] = NonMembershipProof{
+			C:        w.c,
+			AlphaHat: proof.Response(w.alphaTilde, challenge, w.alpha),
+			BetaHat:  proof.Response(w.betaTilde, challenge, w.beta),
+		}
+	}
+	return Presentation{
+		Proof:         prover.Respond(challenge),
+		Tag:           tag,
+		NonMembership: nonMembership,
+	}, nil
+}
+
+// Verify checks a blocklistable presentation against the verifier's blocklist.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - verificationKey: The verification key of the issuer.
+//   - presentation: The presentation to be verified.
+//   - revealed: The disclosed messages, indexed by their position in the message vector.
+//   - secretIndex: The index of the hidden holder secret in the message vector.
+//   - entries: The blocklist snapshot the holder proved non-membership against.
+//   - nonce: The nonce the presentation was bound to.
+//
+// Returns:
+//   - boolean: True if the presentation is valid and not blocklisted, false otherwise.
+//   - error: An error if the presentation is malformed.
+func Verify(publicParams models.PublicParameters, verificationKey models.VerificationKey, presentation Presentation, revealed map[int]string, secretIndex int, entries []Tag, nonce []byte) (bool, error) {
+	sHat, ok := presentation.Proof.MHat[secretIndex]
+	if !ok || sHat == nil {
+		return false, errors.New("holder secret is not hidden in the presentation")
+	}
+	if len(presentation.NonMembership) != len(entries) {
+		return false, errors.New("presentation does not cover the blocklist")
+	}
+	tag := presentation.Tag
+	if tag.R == nil || tag.T == nil || tag.R.IsIdentity() {
+		return false, errors.New("presentation has an invalid tag")
+	}
+
+	// Step 1: Recompute the signature proof transcript and check the pairing equation
+	transcript, err := proof.TranscriptBytes(publicParams, presentation.Proof, revealed)
+	if err != nil {
+		return false, err
+	}
+	if !proof.CheckPairing(publicParams, verificationKey, presentation.Proof) {
+		return false, nil
+	}
+	challenge := presentation.Proof.Challenge
+	negC := utils.Neg(challenge)
+
+	// Step 2: Recompute the tag commitment W = R^{ŝ} · T^{-c}
+	w := utils.MultiExpG1([]*e.G1{tag.R, tag.T}, []*e.Scalar{sHat, negC})
+
+	// Step 3: Recompute the non-membership commitments and check C_i ≠ 1
+	cs := make([]*e.G1, len(entries))
+	us := make([]*e.G1, len(entries))
+	vs := make([]*e.G1, len(entries))
+	var malformed, blocked bool
+	var mu sync.Mutex
+	parallelFor(len(entries), func(i int) {
+		entry, nm := entries[i], presentation.NonMembership[i]
+		if nm.C == nil || nm.AlphaHat == nil || nm.BetaHat == nil {
+			mu.Lock()
+			malformed = true
+			mu.Unlock()
+			return
+		}
+		if nm.C.IsIdentity() {
+			mu.Lock()
+			blocked = true
+			mu.Unlock()
+			return
+		}
+		negBetaHat := utils.Neg(nm.BetaHat)
+		cs[i] = nm.C
+		us[i] = utils.MultiExpG1([]*e.G1{entry.R, entry.T, nm.C}, []*e.Scalar{nm.AlphaHat, negBetaHat, negC})
+		vs[i] = utils.MultiExpG1([]*e.G1{tag.R, tag.T}, []*e.Scalar{nm.AlphaHat, negBetaHat})
+	})
+	if malformed {
+		return false, errors.New("presentation has a malformed non-membership proof")
+	}
+	if blocked {
+		return false, nil
+	}
+
+	// Step 4: Check the challenge
+	expected := presentationChallenge(transcript, tag, w, entries, cs, us, vs, nonce)
+	return expected.IsEqual(challenge) == 1, nil
+}
+
+// presentationChallenge hashes the signature proof transcript, the tag and all non-membership commitments.
+func presentationChallenge(transcript []byte, tag Tag, w *e.G1, entries []Tag, cs, us, vs []*e.G1, nonce []byte) *e.Scalar {
+	inputs := [][]byte{
+		[]byte(presentationDomain),
+		transcript,
+		tag.R.BytesCompressed(),
+		tag.T.BytesCompressed(),
+		w.BytesCompressed(),
+	}
+	for i, entry := range entries {
+		inputs = append(inputs,
+			entry.R.BytesCompressed(),
+			entry.T.BytesCompressed(),
+			cs[i].BytesCompressed(),
+			us[i].BytesCompressed(),
+			vs[i].BytesCompressed(),
+		)
+	}
+	inputs = append(inputs, nonce)
+	return utils.HashToScalar(inputs...)
+}
+
+// parallelFor runs f(0), ..., f(n-1) on all available CPUs.
+func parallelFor(n int, f func(i int)) {
+	workers := runtime.NumCPU()
+	if workers > n {
+		workers = n
+	}
+	var wg sync.WaitGroup
+	next := make(chan int)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				f(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+}
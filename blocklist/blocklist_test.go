@@ -0,0 +1,112 @@
+package blocklist
+
+import (
+	"testing"
+
+	"github.com/aniagut/msc-bbs-plus-plus/keygen"
+	"github.com/aniagut/msc-bbs-plus-plus/models"
+	"github.com/aniagut/msc-bbs-plus-plus/sign"
+	"github.com/stretchr/testify/assert"
+)
+
+// issue generates a credential (name, role, holder secret) for testing.
+func issue(t *testing.T, result models.KeyGenResult, name string) ([]string, models.Signature) {
+	secret, err := NewHolderSecret()
+	assert.NoError(t, err, "NewHolderSecret should not return an error")
+	messages := []string{name, "member", secret}
+	signature, err := sign.Sign(result.PublicParameters, result.SigningKey, messages)
+	assert.NoError(t, err, "Sign should not return an error")
+	return messages, signature
+}
+
+// TestPresentEmptyBlocklist tests that a presentation is accepted against an empty blocklist.
+func TestPresentEmptyBlocklist(t *testing.T) {
+	result, err := keygen.KeyGen(3)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	messages, signature := issue(t, result, "alice")
+	nonce := []byte("nonce")
+
+	var list Blocklist
+	presentation, err := Present(result.PublicParameters, signature, messages, []int{1}, 2, list.Entries(), nonce)
+	assert.NoError(t, err, "Present should not return an error")
+
+	isValid, err := Verify(result.PublicParameters, result.VerificationKey, presentation, map[int]string{1: "member"}, 2, list.Entries(), nonce)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.True(t, isValid, "Verify should accept a valid presentation")
+}
+
+// TestBlockedUser tests that a blocklisted credential can no longer be presented while others can.
+func TestBlockedUser(t *testing.T) {
+	result, err := keygen.KeyGen(3)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	pp, vk := result.PublicParameters, result.VerificationKey
+	revealed := map[int]string{1: "member"}
+
+	var list Blocklist
+	for _, name := range []string{"carol", "dave"} {
+		messages, signature := issue(t, result, name)
+		presentation, err := Present(pp, signature, messages, []int{1}, 2, list.Entries(), []byte(name))
+		assert.NoError(t, err, "Present should not return an error")
+		list.Add(presentation.Tag)
+	}
+
+	// Mallory misbehaves and the verifier blocks the tag of her presentation
+	malloryMessages, mallorySignature := issue(t, result, "mallory")
+	presentation, err := Present(pp, mallorySignature, malloryMessages, []int{1}, 2, list.Entries(), []byte("abuse"))
+	assert.NoError(t, err, "Present should not return an error")
+	list.Add(presentation.Tag)
+
+	// Mallory can no longer produce a presentation
+	_, err = Present(pp, mallorySignature, malloryMessages, []int{1}, 2, list.Entries(), []byte("nonce"))
+	assert.ErrorIs(t, err, ErrBlocklisted, "Present should fail for a blocklisted credential")
+
+	// Alice is not affected
+	aliceMessages, aliceSignature := issue(t, result, "alice")
+	presentation, err = Present(pp, aliceSignature, aliceMessages, []int{1}, 2, list.Entries(), []byte("nonce"))
+	assert.NoError(t, err, "Present should not return an error")
+	isValid, err := Verify(pp, vk, presentation, revealed, 2, list.Entries(), []byte("nonce"))
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.True(t, isValid, "Verify should accept an unblocked credential")
+
+	// A presentation made against an older snapshot is not accepted
+	presentation, err = Present(pp, aliceSignature, aliceMessages, []int{1}, 2, list.Entries()[:1], []byte("nonce"))
+	assert.NoError(t, err, "Present should not return an error")
+	_, err = Verify(pp, vk, presentation, revealed, 2, list.Entries(), []byte("nonce"))
+	assert.Error(t, err, "Verify should reject a presentation that does not cover the blocklist")
+}
+
+// TestTagsUnlinkable tests that two presentations of the same credential carry different tags.
+func TestTagsUnlinkable(t *testing.T) {
+	result, err := keygen.KeyGen(3)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	messages, signature := issue(t, result, "alice")
+
+	p1, err := Present(result.PublicParameters, signature, messages, nil, 2, nil, []byte("n1"))
+	assert.NoError(t, err, "Present should not return an error")
+	p2, err := Present(result.PublicParameters, signature, messages, nil, 2, nil, []byte("n2"))
+	assert.NoError(t, err, "Present should not return an error")
+
+	assert.False(t, p1.Tag.T.IsEqual(p2.Tag.T), "Tags of different presentations should differ")
+}
+
+// TestForgedNonMembership tests that a presentation with a tampered non-membership proof is rejected.
+func TestForgedNonMembership(t *testing.T) {
+	result, err := keygen.KeyGen(3)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	pp, vk := result.PublicParameters, result.VerificationKey
+
+	var list Blocklist
+	messages, signature := issue(t, result, "mallory")
+	presentation, err := Present(pp, signature, messages, nil, 2, nil, []byte("abuse"))
+	assert.NoError(t, err, "Present should not return an error")
+	list.Add(presentation.Tag)
+
+	aliceMessages, aliceSignature := issue(t, result, "alice")
+	presentation, err = Present(pp, aliceSignature, aliceMessages, nil, 2, list.Entries(), []byte("nonce"))
+	assert.NoError(t, err, "Present should not return an error")
+	presentation.NonMembership[0].AlphaHat = presentation.NonMembership[0].BetaHat
+
+	isValid, err := Verify(pp, vk, presentation, map[int]string{}, 2, list.Entries(), []byte("nonce"))
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.False(t, isValid, "Verify should reject a tampered non-membership proof")
+}
@@ -0,0 +1,281 @@
+package commitment
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/aniagut/msc-bbs-plus-plus/models"
+	"github.com/aniagut/msc-bbs-plus-plus/proof"
+	"github.com/aniagut/msc-bbs-plus-plus/sigma"
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	e "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+// linkDomain separates the challenges of link proofs from other hashes.
+const linkDomain = "BBS++-COMMITMENT-LINK-V1"
+
+// Commitment is a hiding Pedersen vector commitment C = h0^r · ∏_k h₁[k]^{v_k} over the generators
+// of the public parameters. Unlike utils.ComputeCommitment it has no g1 term, so commitments
+// are additively homomorphic.
+type Commitment struct {
+	C *e.G1
+}
+
+// Opening is the opening of a commitment: the committed values and the blinding r.
+type Opening struct {
+	Values   []*e.Scalar
+	Blinding *e.Scalar
+}
+
+// LinkProof is a proof of knowledge of a signature together with a proof that a commitment
+// contains some of its hidden messages. The responses of the linked messages are those of the
+// signature proof, so only the response of the blinding is added.
+type LinkProof struct {
+	Proof models.Proof
+	RHat  *e.Scalar
+}
+
+// Commit commits to messages with a random blinding.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system; they must include h0.
+//   - m: The messages, at most one per generator h₁[k].
+//
+// Returns:
+//   - Commitment: The commitment.
+//   - Opening: The opening to be kept by the committer.
+//   - error: An error if the parameters do not support the commitment or randomness generation fails.
+func Commit(publicParams models.PublicParameters, m []string) (Commitment, Opening, error) {
+	values := make([]*e.Scalar, len(m))
+	for k, message := range m {
+		values[k] = utils.MessageToScalar(message)
+	}
+	r, err := utils.RandomScalar()
+	if err != nil {
+		return Commitment{}, Opening{}, err
+	}
+	opening := Opening{Values: values, Blinding: &r}
+	c, err := CommitScalars(publicParams, opening)
+	if err != nil {
+		return Commitment{}, Opening{}, err
+	}
+	return c, opening, nil
+}
+
+// CommitScalars computes the commitment of an opening.
+func CommitScalars(publicParams models.PublicParameters, opening Opening) (Commitment, error) {
+	if publicParams.H0 == nil {
+		return Commitment{}, errors.New("public parameters do not include the generator h0")
+	}
+	if len(opening.Values) > len(publicParams.H1) {
+		return Commitment{}, errors.New("more values than generators")
+	}
+	if opening.Blinding == nil {
+		return Commitment{}, errors.New("opening is missing the blinding")
+	}
+	points := []*e.G1{publicParams.H0}
+	scalars := []*e.Scalar{opening.Blinding}
+	for k, value := range opening.Values {
+		if value == nil {
+			return Commitment{}, errors.New("opening is missing a value")
+		}
+		points = append(points, &publicParams.H1[k])
+		scalars = append(scalars, value)
+	}
+	return Commitment{C: utils.MultiExpG1(points, scalars)}, nil
+}
+
+// Open checks that an opening matches a commitment.
+func Open(publicParams models.PublicParameters, commitment Commitment, opening Opening) bool {
+	if commitment.C == nil {
+		return false
+	}
+	expected, err := CommitScalars(publicParams, opening)
+	if err != nil {
+		return false
+	}
+	return expected.C.IsEqual(commitment.C)
+}
+
+// Add returns the commitment to the sum of the committed vectors.
+func Add(a, b Commitment) Commitment {
+	c := new(e.G1)
+	c.Add(a.C, b.C)
+	return Commitment{C: c}
+}
+
+// AddOpenings returns the opening of Add(a, b) from the openings of a and b.
+func AddOpenings(a, b Opening) Opening {
+	n := len(a.Values)
+	if len(b.Values) > n {
+		n = len(b.Values)
+	}
+	values := make([]*e.Scalar, n)
+	for k := range values {
+		values[k] = new(e.Scalar)
+		if k < len(a.Values) {
+			values[k].Add(values[k], a.Values[k])
+		}
+		if k < len(b.Values) {
+			values[k].Add(values[k], b.Values[k])
+		}
+	}
+	r := new(e.Scalar)
+	r.Add(a.Blinding, b.Blinding)
+	return Opening{Values: values, Blinding: r}
+}
+
+// Statement exports the commitment as the relation C = h0^{blinding} · ∏_k h₁[k]^{vars[k]} of the
+// sigma package, so that other proofs can show statements about the committed values.
+func Statement(publicParams models.PublicParameters, commitment Commitment, blinding string, vars []string) (*sigma.Statement, error) {
+	if publicParams.H0 == nil {
+		return nil, errors.New("public parameters do not include the generator h0")
+	}
+	if commitment.C == nil {
+		return nil, errors.New("commitment is missing")
+	}
+	if len(vars) > len(publicParams.H1) {
+		return nil, errors.New("more values than generators")
+	}
+	terms := []sigma.Term{{Base: sigma.G1(publicParams.H0), Var: blinding}}
+	for k, name := range vars {
+		terms = append(terms, sigma.Term{Base: sigma.G1(&publicParams.H1[k]), Var: name})
+	}
+	return sigma.NewStatement().Relation(sigma.G1(commitment.C), terms...), nil
+}
+
+// Witness exports the opening as the witness of Statement with the same names.
+func (o Opening) Witness(blinding string, vars []string) sigma.Witness {
+	w := sigma.Witness{blinding: o.Blinding}
+	for k, name := range vars {
+		if k < len(o.Values) {
+			w[name] = o.Values[k]
+		}
+	}
+	return w
+}
+
+// ProveLink proves knowledge of a signature and that the commitment contains its hidden messages:
+// value k of the commitment is message indexes[k] of the signature.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - signature: The signature on the messages.
+//   - m: The full message vector.
+//   - disclosed: The indexes of the messages revealed to the verifier.
+//   - commitment: The commitment to be linked.
+//   - opening: The opening of the commitment.
+//   - indexes: For every committed value, the index of the hidden message it equals.
+//   - nonce: A verifier-supplied nonce bound to the proof.
+//
+// Returns:
+//   - LinkProof: The proof.
+//   - error: An error if the commitment does not contain the messages or the proof cannot be generated.
+func ProveLink(publicParams models.PublicParameters, signature models.Signature, m []string, disclosed []int, commitment Commitment, opening Opening, indexes []int, nonce []byte) (LinkProof, error) {
+	if len(indexes) != len(opening.Values) {
+		return LinkProof{}, errors.New("every committed value must be linked to a message")
+	}
+	if !Open(publicParams, commitment, opening) {
+		return LinkProof{}, errors.New("opening does not match the commitment")
+	}
+
+	// Step 1: Commitment phase of the signature proof
+	prover, err := proof.NewProver(publicParams, signature, m, disclosed, nil)
+	if err != nil {
+		return LinkProof{}, err
+	}
+	blindings := make([]*e.Scalar, len(indexes))
+	for k, j := range indexes {
+		message := prover.Message(j)
+		if message == nil {
+			return LinkProof{}, errors.New("linked message must be hidden")
+		}
+		if message.IsEqual(opening.Values[k]) != 1 {
+			return LinkProof{}, errors.New("committed value does not equal the linked message")
+		}
+		blindings[k] = prover.Blinding(j)
+	}
+
+	// Step 2: Commit T = h0^{r̃} · ∏_k h₁[k]^{m̃_{indexes[k]}}
+	rTilde, err := utils.RandomScalar()
+	if err != nil {
+		return LinkProof{}, err
+	}
+	t, err := CommitScalars(publicParams, Opening{Values: blindings, Blinding: &rTilde})
+	if err != nil {
+		return LinkProof{}, err
+	}
+
+	// Step 3: Derive the challenge and respond
+	challenge := linkChallenge(prover.Bytes(), commitment, indexes, t.C, nonce)
+	return LinkProof{
+		Proof: prover.Respond(challenge),
+		RHat:  proof.Response(&rTilde, challenge, opening.Blinding),
+	}, nil
+}
+
+// VerifyLink checks a link proof.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - verificationKey: The verification key of the issuer.
+//   - commitment: The linked commitment.
+//   - indexes: For every committed value, the index of the hidden message it equals.
+//   - linkProof: The proof to be verified.
+//   - revealed: The disclosed messages, indexed by their position in the message vector.
+//   - nonce: The nonce the proof was bound to.
+//
+// Returns:
+//   - boolean: True if the proof is valid, false otherwise.
+//   - error: An error if the proof is malformed.
+func VerifyLink(publicParams models.PublicParameters, verificationKey models.VerificationKey, commitment Commitment, indexes []int, linkProof LinkProof, revealed map[int]string, nonce []byte) (bool, error) {
+	if commitment.C == nil || linkProof.RHat == nil {
+		return false, errors.New("link proof is missing components")
+	}
+
+	// Step 1: Recompute the signature proof transcript and check the pairing
+	transcript, err := proof.TranscriptBytes(publicParams, linkProof.Proof, revealed)
+	if err != nil {
+		return false, err
+	}
+	if !proof.CheckPairing(publicParams, verificationKey, linkProof.Proof) {
+		return false, nil
+	}
+
+	// Step 2: Recompute T = h0^{r̂} · ∏_k h₁[k]^{m̂_{indexes[k]}} · C^{-c}
+	responses := make([]*e.Scalar, len(indexes))
+	for k, j := range indexes {
+		responses[k] = linkProof.Proof.MHat[j]
+		if responses[k] == nil {
+			return false, errors.New("linked message must be hidden")
+		}
+	}
+	t, err := CommitScalars(publicParams, Opening{Values: responses, Blinding: linkProof.RHat})
+	if err != nil {
+		return false, err
+	}
+	cc := new(e.G1)
+	cc.ScalarMult(utils.Neg(linkProof.Proof.Challenge), commitment.C)
+	t.C.Add(t.C, cc)
+
+	// Step 3: Check the challenge
+	expected := linkChallenge(transcript, commitment, indexes, t.C, nonce)
+	return expected.IsEqual(linkProof.Proof.Challenge) == 1, nil
+}
+
+// linkChallenge hashes the signature proof transcript, the commitment, the linked indexes,
+// the commitment T and the nonce.
+func linkChallenge(transcript []byte, commitment Commitment, indexes []int, t *e.G1, nonce []byte) *e.Scalar {
+	inputs := [][]byte{
+		[]byte(linkDomain),
+		transcript,
+		commitment.C.BytesCompressed(),
+	}
+	for _, j := range indexes {
+		var buf [8]byte
+		binary.BigEndian.PutUint64(buf[:], uint64(j))
+		inputs = append(inputs, buf[:])
+	}
+	inputs = append(inputs, t.BytesCompressed(), nonce)
+	return utils.HashToScalar(inputs...)
+}
@@ -0,0 +1,102 @@
+package commitment
+
+import (
+	"testing"
+
+	"github.com/aniagut/msc-bbs-plus-plus/keygen"
+	"github.com/aniagut/msc-bbs-plus-plus/sigma"
+	"github.com/aniagut/msc-bbs-plus-plus/sign"
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCommitOpen tests committing to messages and opening the commitment.
+func TestCommitOpen(t *testing.T) {
+	result, err := keygen.KeyGen(3)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	publicParams := result.PublicParameters
+
+	commitment, opening, err := Commit(publicParams, []string{"a", "b"})
+	assert.NoError(t, err, "Commit should not return an error")
+	assert.True(t, Open(publicParams, commitment, opening), "Open should accept the opening")
+
+	other, _, err := Commit(publicParams, []string{"a", "b"})
+	assert.NoError(t, err, "Commit should not return an error")
+	assert.False(t, commitment.C.IsEqual(other.C), "Commitments to the same messages should differ")
+
+	opening.Values[1] = utils.MessageToScalar("c")
+	assert.False(t, Open(publicParams, commitment, opening), "Open should reject another value")
+
+	_, _, err = Commit(publicParams, []string{"a", "b", "c", "d"})
+	assert.Error(t, err, "Commit should reject more values than generators")
+}
+
+// TestAdd tests that commitments are additively homomorphic.
+func TestAdd(t *testing.T) {
+	result, err := keygen.KeyGen(2)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	publicParams := result.PublicParameters
+
+	a, openA, err := Commit(publicParams, []string{"x", "y"})
+	assert.NoError(t, err, "Commit should not return an error")
+	b, openB, err := Commit(publicParams, []string{"z"})
+	assert.NoError(t, err, "Commit should not return an error")
+
+	sum := AddOpenings(openA, openB)
+	assert.True(t, Open(publicParams, Add(a, b), sum), "The sum of openings should open the sum of commitments")
+	assert.False(t, Open(publicParams, Add(a, b), openA), "A single opening should not open the sum")
+}
+
+// TestStatement tests that an exported commitment can be proved with the sigma package.
+func TestStatement(t *testing.T) {
+	result, err := keygen.KeyGen(2)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	publicParams := result.PublicParameters
+	commitment, opening, err := Commit(publicParams, []string{"x", "y"})
+	assert.NoError(t, err, "Commit should not return an error")
+	vars := []string{"x", "y"}
+
+	statement, err := Statement(publicParams, commitment, "r", vars)
+	assert.NoError(t, err, "Statement should not return an error")
+	proof, err := sigma.Prove(sigma.NewTranscript("test"), statement, opening.Witness("r", vars))
+	assert.NoError(t, err, "Prove should not return an error")
+	isValid, err := sigma.Verify(sigma.NewTranscript("test"), statement, proof)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.True(t, isValid, "Verify should accept a proof of the opening")
+}
+
+// TestLink tests linking hidden credential messages to a commitment.
+func TestLink(t *testing.T) {
+	result, err := keygen.KeyGen(4)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	publicParams := result.PublicParameters
+	messages := []string{"alice", "secret", "student", "id-7"}
+	signature, err := sign.Sign(publicParams, result.SigningKey, messages)
+	assert.NoError(t, err, "Sign should not return an error")
+	nonce := []byte("nonce")
+	revealed := map[int]string{2: "student"}
+
+	commitment, opening, err := Commit(publicParams, []string{"id-7", "secret"})
+	assert.NoError(t, err, "Commit should not return an error")
+	indexes := []int{3, 1}
+
+	linkProof, err := ProveLink(publicParams, signature, messages, []int{2}, commitment, opening, indexes, nonce)
+	assert.NoError(t, err, "ProveLink should not return an error")
+
+	isValid, err := VerifyLink(publicParams, result.VerificationKey, commitment, indexes, linkProof, revealed, nonce)
+	assert.NoError(t, err, "VerifyLink should not return an error")
+	assert.True(t, isValid, "VerifyLink should accept a valid proof")
+
+	isValid, err = VerifyLink(publicParams, result.VerificationKey, commitment, []int{1, 3}, linkProof, revealed, nonce)
+	assert.NoError(t, err, "VerifyLink should not return an error")
+	assert.False(t, isValid, "VerifyLink should reject other positions")
+
+	other, otherOpening, err := Commit(publicParams, []string{"id-8", "secret"})
+	assert.NoError(t, err, "Commit should not return an error")
+	isValid, err = VerifyLink(publicParams, result.VerificationKey, other, indexes, linkProof, revealed, nonce)
+	assert.NoError(t, err, "VerifyLink should not return an error")
+	assert.False(t, isValid, "VerifyLink should reject another commitment")
+
+	_, err = ProveLink(publicParams, signature, messages, []int{2}, other, otherOpening, indexes, nonce)
+	assert.Error(t, err, "ProveLink should reject a commitment to other values")
+}
@@ -0,0 +1,240 @@
+package compressed
+
+import (
+	"encoding/binary"
+	"errors"
+	"sort"
+
+	"github.com/aniagut/msc-bbs-plus-plus/models"
+	"github.com/aniagut/msc-bbs-plus-plus/proof"
+	"github.com/aniagut/msc-bbs-plus-plus/sigma"
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	e "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+const (
+	// proofDomain separates the transcripts of compressed proofs from other proofs.
+	proofDomain = "BBS++-COMPRESSED-PROOF-V1"
+	// paddingDomain is the domain separation tag used to derive the padding generators.
+	paddingDomain = "BBS++-COMPRESSED-PADDING-V1"
+)
+
+const (
+	g1Size     = 48
+	scalarSize = 32
+)
+
+// Proof is a proof of knowledge of a BBS++ signature whose size is logarithmic in the number of
+// hidden messages. It follows compressed Σ-protocol theory (Attema–Cramer): the prover runs the
+// standard proof up to the challenge, but instead of the responses z = (r̂3, -m̂_j) of the relation
+//
+//	D^{r̂3} · ∏_{hidden} h_j^{-m̂_j} = T2 · (g1 · ∏_{disclosed} h_i^{m_i})^c
+//
+// it sends T2 and proves knowledge of z by recursive folding: every round halves the vector and
+// sends two group elements L and R, until a single scalar Z is left. The responses are
+// already zero-knowledge, so the folding needs no further blinding.
+type Proof struct {
+	ABar, BBar, D, T2 *e.G1
+	Challenge         *e.Scalar
+	EHat, R1Hat       *e.Scalar
+	L, R              []*e.G1
+	Z                 *e.Scalar
+}
+
+// Prove generates a compressed proof of knowledge of a signature on m that discloses the messages in disclosed.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - signature: The signature on the messages.
+//   - m: The full message vector.
+//   - disclosed: The indexes of the messages revealed to the verifier.
+//   - nonce: A verifier-supplied nonce bound to the proof.
+//
+// Returns:
+//   - Proof: The generated proof.
+//   - error: An error if the inputs are inconsistent or randomness generation fails.
+func Prove(publicParams models.PublicParameters, signature models.Signature, m []string, disclosed []int, nonce []byte) (Proof, error) {
+	// Step 1: Run the standard proof and keep T2 instead of the responses it is recomputed from
+	prover, err := proof.NewProver(publicParams, signature, m, disclosed, nil)
+	if err != nil {
+		return Proof{}, err
+	}
+	_, t2 := prover.Commitments()
+	t := sigma.NewTranscript(proofDomain)
+	t.AppendMessage("proof", prover.Bytes())
+	t.AppendMessage("nonce", nonce)
+	challenge := t.ChallengeScalar("challenge")
+	response := prover.Respond(challenge)
+
+	// Step 2: Collect the bases (D, h_j) and the responses z = (r̂3, -m̂_j) of the hidden messages
+	aBar, bBar := prover.Randomized()
+	hidden := hiddenIndexes(response.MHat)
+	bases := generators(publicParams, response.D, hidden)
+	z := make([]*e.Scalar, len(bases))
+	z[0] = response.R3Hat
+	for k, j := range hidden {
+		z[k+1] = utils.Neg(response.MHat[j])
+	}
+	for k := len(hidden) + 1; k < len(z); k++ {
+		z[k] = new(e.Scalar)
+	}
+
+	// Step 3: Fold until a single response is left
+	result := Proof{
+		ABar:      aBar,
+		BBar:      bBar,
+		D:         response.D,
+		T2:        t2,
+		Challenge: challenge,
+		EHat:      response.EHat,
+		R1Hat:     response.R1Hat,
+	}
+	for len(z) > 1 {
+		half := len(z) / 2
+		l := utils.MultiExpG1(bases[half:], z[:half])
+		r := utils.MultiExpG1(bases[:half], z[half:])
+		t.AppendElement("L", sigma.G1(l))
+		t.AppendElement("R", sigma.G1(r))
+		x := t.ChallengeScalar("fold")
+		result.L = append(result.L, l)
+		result.R = append(result.R, r)
+
+		// z' = z_L + x · z_R
+		folded := make([]*e.Scalar, half)
+		for k := range folded {
+			folded[k] = new(e.Scalar)
+			folded[k].Mul(x, z[half+k])
+			folded[k].Add(folded[k], z[k])
+		}
+		z = folded
+		bases = foldGenerators(bases, x)
+	}
+	result.Z = z[0]
+	return result, nil
+}
+
+// Verify checks a compressed proof.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - verificationKey: The verification key of the issuer.
+//   - p: The proof to be verified.
+//   - revealed: The disclosed messages, indexed by their position in the message vector.
+//   - nonce: The nonce the proof was bound to.
+//
+// Returns:
+//   - boolean: True if the proof is valid, false otherwise.
+//   - error: An error if the proof is malformed.
+func Verify(publicParams models.PublicParameters, verificationKey models.VerificationKey, p Proof, revealed map[int]string, nonce []byte) (bool, error) {
+	if p.ABar == nil || p.BBar == nil || p.D == nil || p.T2 == nil || p.Challenge == nil ||
+		p.EHat == nil || p.R1Hat == nil || p.Z == nil || len(p.L) != len(p.R) {
+		return false, errors.New("proof is missing components")
+	}
+	l := len(publicParams.H1)
+	var hidden []int
+	for j := 0; j < l; j++ {
+		if _, ok := revealed[j]; !ok {
+			hidden = append(hidden, j)
+		}
+	}
+	if len(revealed)+len(hidden) != l {
+		return false, errors.New("disclosed index out of range")
+	}
+	bases := generators(publicParams, p.D, hidden)
+	if 1<<len(p.L) != len(bases) {
+		return false, errors.New("number of folding rounds does not match the hidden messages")
+	}
+
+	// Step 1: Check the pairing and recompute T1 = D^{r̂1} · Ā^{-ê} · B̄^{-c}
+	if !proof.CheckPairing(publicParams, verificationKey, models.Proof{ABar: p.ABar, BBar: p.BBar}) {
+		return false, nil
+	}
+	negC := utils.Neg(p.Challenge)
+	t1 := utils.MultiExpG1([]*e.G1{p.D, p.ABar, p.BBar}, []*e.Scalar{p.R1Hat, utils.Neg(p.EHat), negC})
+
+	// Step 2: Check the challenge
+	t := sigma.NewTranscript(proofDomain)
+	t.AppendMessage("proof", proof.Transcript(revealed, p.ABar, p.BBar, p.D, t1, p.T2))
+	t.AppendMessage("nonce", nonce)
+	if t.ChallengeScalar("challenge").IsEqual(p.Challenge) != 1 {
+		return false, nil
+	}
+
+	// Step 3: Compute the target Q = T2 · (g1 · ∏_{disclosed} h_i^{m_i})^c
+	disclosed := new(e.G1)
+	*disclosed = *publicParams.G1
+	for i, message := range revealed {
+		term := new(e.G1)
+		term.ScalarMult(utils.MessageToScalar(message), &publicParams.H1[i])
+		disclosed.Add(disclosed, term)
+	}
+	q := new(e.G1)
+	q.ScalarMult(p.Challenge, disclosed)
+	q.Add(q, p.T2)
+
+	// Step 4: Fold the target Q' = L · Q^x · R^{x²} and the generators, then check g^Z = Q
+	for round := range p.L {
+		if p.L[round] == nil || p.R[round] == nil {
+			return false, errors.New("proof is missing components")
+		}
+		t.AppendElement("L", sigma.G1(p.L[round]))
+		t.AppendElement("R", sigma.G1(p.R[round]))
+		x := t.ChallengeScalar("fold")
+		x2 := new(e.Scalar)
+		x2.Mul(x, x)
+		q = utils.MultiExpG1([]*e.G1{p.L[round], q, p.R[round]}, []*e.Scalar{utils.One(), x, x2})
+		bases = foldGenerators(bases, x)
+	}
+	final := new(e.G1)
+	final.ScalarMult(p.Z, bases[0])
+	return final.IsEqual(q), nil
+}
+
+// Size returns the encoded size of the proof in bytes, with compressed group elements.
+func (p Proof) Size() int {
+	return (4+len(p.L)+len(p.R))*g1Size + 4*scalarSize
+}
+
+// StandardSize returns the encoded size in bytes of a standard proof.Prove proof with the given
+// number of hidden messages, for comparison.
+func StandardSize(hidden int) int {
+	return 3*g1Size + (4+hidden)*scalarSize
+}
+
+// generators returns the bases (D, h_j for hidden j), padded to a power of two with generators
+// derived by hashing; the padding responses are zero.
+func generators(publicParams models.PublicParameters, d *e.G1, hidden []int) []*e.G1 {
+	bases := []*e.G1{d}
+	for _, j := range hidden {
+		bases = append(bases, &publicParams.H1[j])
+	}
+	for k := len(bases); k&(k-1) != 0; k++ {
+		var buf [8]byte
+		binary.BigEndian.PutUint64(buf[:], uint64(k))
+		bases = append(bases, utils.HashToG1(buf[:], paddingDomain))
+	}
+	return bases
+}
+
+// foldGenerators computes g' = g_L^x · g_R, which satisfies ∏ g'^{z_L + x·z_R} = L · Q^x · R^{x²}
+// for L = g_R^{z_L}, R = g_L^{z_R} and Q = ∏ g^z.
+func foldGenerators(bases []*e.G1, x *e.Scalar) []*e.G1 {
+	half := len(bases) / 2
+	folded := make([]*e.G1, half)
+	for k := range folded {
+		folded[k] = new(e.G1)
+		folded[k].ScalarMult(x, bases[k])
+		folded[k].Add(folded[k], bases[half+k])
+	}
+	return folded
+}
+
+// hiddenIndexes returns the indexes of the hidden messages in increasing order.
+func hiddenIndexes(mHat map[int]*e.Scalar) []int {
+	indexes := make([]int, 0, len(mHat))
+	for j := range mHat {
+		indexes = append(indexes, j)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
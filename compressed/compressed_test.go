@@ -0,0 +1,134 @@
+package compressed
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aniagut/msc-bbs-plus-plus/keygen"
+	"github.com/aniagut/msc-bbs-plus-plus/proof"
+	"github.com/aniagut/msc-bbs-plus-plus/sign"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestProveVerify tests compressed proofs for different numbers of hidden messages, including
+// counts that need padding.
+func TestProveVerify(t *testing.T) {
+	for _, l := range []int{1, 2, 5, 8} {
+		result, err := keygen.KeyGen(l)
+		assert.NoError(t, err, "KeyGen should not return an error")
+		publicParams := result.PublicParameters
+		messages := make([]string, l)
+		for i := range messages {
+			messages[i] = fmt.Sprintf("message%d", i)
+		}
+		signature, err := sign.Sign(publicParams, result.SigningKey, messages)
+		assert.NoError(t, err, "Sign should not return an error")
+		nonce := []byte("nonce")
+		revealed := map[int]string{0: messages[0]}
+
+		p, err := Prove(publicParams, signature, messages, []int{0}, nonce)
+		assert.NoError(t, err, "Prove should not return an error")
+
+		isValid, err := Verify(publicParams, result.VerificationKey, p, revealed, nonce)
+		assert.NoError(t, err, "Verify should not return an error")
+		assert.True(t, isValid, "Verify should accept a valid proof with l=%d", l)
+
+		isValid, err = Verify(publicParams, result.VerificationKey, p, map[int]string{0: "other"}, nonce)
+		assert.NoError(t, err, "Verify should not return an error")
+		assert.False(t, isValid, "Verify should reject another disclosed message with l=%d", l)
+
+		isValid, err = Verify(publicParams, result.VerificationKey, p, revealed, []byte("other"))
+		assert.NoError(t, err, "Verify should not return an error")
+		assert.False(t, isValid, "Verify should reject another nonce with l=%d", l)
+	}
+}
+
+// TestTamperedFolding tests that a modified folding round or final response is rejected.
+func TestTamperedFolding(t *testing.T) {
+	result, err := keygen.KeyGen(6)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	publicParams := result.PublicParameters
+	messages := []string{"a", "b", "c", "d", "e", "f"}
+	signature, err := sign.Sign(publicParams, result.SigningKey, messages)
+	assert.NoError(t, err, "Sign should not return an error")
+	nonce := []byte("nonce")
+
+	p, err := Prove(publicParams, signature, messages, nil, nonce)
+	assert.NoError(t, err, "Prove should not return an error")
+
+	tampered := p
+	tampered.L = append(tampered.L[:0:0], p.L...)
+	tampered.L[0] = p.R[0]
+	isValid, err := Verify(publicParams, result.VerificationKey, tampered, map[int]string{}, nonce)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.False(t, isValid, "Verify should reject a modified folding round")
+
+	tampered = p
+	tampered.Z = p.EHat
+	isValid, err = Verify(publicParams, result.VerificationKey, tampered, map[int]string{}, nonce)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.False(t, isValid, "Verify should reject a modified response")
+}
+
+// TestSize tests that the proof size grows logarithmically with the hidden messages.
+func TestSize(t *testing.T) {
+	result, err := keygen.KeyGen(127)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	messages := make([]string, 127)
+	signature, err := sign.Sign(result.PublicParameters, result.SigningKey, messages)
+	assert.NoError(t, err, "Sign should not return an error")
+
+	p, err := Prove(result.PublicParameters, signature, messages, nil, nil)
+	assert.NoError(t, err, "Prove should not return an error")
+	assert.Len(t, p.L, 7, "Prove should fold 128 responses in 7 rounds")
+	assert.Less(t, p.Size(), StandardSize(127)/4, "The compressed proof should be much smaller")
+}
+
+// benchmarkAttributes is the credential size used by the benchmarks.
+const benchmarkAttributes = 128
+
+// BenchmarkProveCompressed measures compressed proofs with all messages hidden.
+func BenchmarkProveCompressed(b *testing.B) {
+	result, _ := keygen.KeyGen(benchmarkAttributes)
+	messages := make([]string, benchmarkAttributes)
+	signature, _ := sign.Sign(result.PublicParameters, result.SigningKey, messages)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Prove(result.PublicParameters, signature, messages, nil, nil)
+	}
+}
+
+// BenchmarkProveStandard measures standard proofs of the same credential for comparison.
+func BenchmarkProveStandard(b *testing.B) {
+	result, _ := keygen.KeyGen(benchmarkAttributes)
+	messages := make([]string, benchmarkAttributes)
+	signature, _ := sign.Sign(result.PublicParameters, result.SigningKey, messages)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = proof.Prove(result.PublicParameters, signature, messages, nil, nil)
+	}
+}
+
+// BenchmarkVerifyCompressed measures verification of compressed proofs.
+func BenchmarkVerifyCompressed(b *testing.B) {
+	result, _ := keygen.KeyGen(benchmarkAttributes)
+	messages := make([]string, benchmarkAttributes)
+	signature, _ := sign.Sign(result.PublicParameters, result.SigningKey, messages)
+	p, _ := Prove(result.PublicParameters, signature, messages, nil, nil)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Verify(result.PublicParameters, result.VerificationKey, p, map[int]string{}, nil)
+	}
+}
+
+// BenchmarkVerifyStandard measures verification of standard proofs for comparison.
+func BenchmarkVerifyStandard(b *testing.B) {
+	result, _ := keygen.KeyGen(benchmarkAttributes)
+	messages := make([]string, benchmarkAttributes)
+	signature, _ := sign.Sign(result.PublicParameters, result.SigningKey, messages)
+	p, _ := proof.Prove(result.PublicParameters, signature, messages, nil, nil)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = proof.Verify(result.PublicParameters, result.VerificationKey, p, map[int]string{}, nil)
+	}
+}
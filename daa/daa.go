@@ -0,0 +1,379 @@
+package daa
+
+import (
+	"crypto/rand"
+	"errors"
+	"sync"
+
+	"github.com/aniagut/msc-bbs-plus-plus/keygen"
+	"github.com/aniagut/msc-bbs-plus-plus/models"
+	"github.com/aniagut/msc-bbs-plus-plus/proof"
+	"github.com/aniagut/msc-bbs-plus-plus/sign"
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	"github.com/aniagut/msc-bbs-plus-plus/verify"
+	e "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+const (
+	// joinDomain separates join request digests from other hashes.
+	joinDomain = "BBS++-DAA-JOIN-V1"
+	// attestDomain separates attestation digests from other hashes.
+	attestDomain = "BBS++-DAA-ATTEST-V1"
+	// tpmDomain separates the challenges computed inside the TPM from other hashes.
+	tpmDomain = "BBS++-DAA-TPM-CHALLENGE-V1"
+	// basenameDomain is the domain separation tag used to derive the base J of a basename.
+	basenameDomain = "BBS++-DAA-BASENAME-V1"
+	// secretIndex is the position of the device secret in the credential.
+	secretIndex = 0
+)
+
+// ErrRogue is returned when a join request or an attestation comes from a TPM on the rogue list.
+var ErrRogue = errors.New("TPM is on the rogue list")
+
+// TPM is the device that holds the DAA secret f. The secret never leaves the device: the host
+// only obtains commitments b^f and Schnorr responses r + c·f computed by the TPM.
+type TPM interface {
+	// Commitment returns b^f for the device secret f.
+	Commitment(base *e.G1) (*e.G1, error)
+	// Commit starts a signing operation: it samples a fresh r and returns b^r for every base
+	// together with a handle for Sign.
+	Commit(bases []*e.G1) (int, []*e.G1, error)
+	// Sign completes the operation of the handle: it derives the challenge c from the host's
+	// digest and returns c and r + c·f. Each handle can be used only once.
+	Sign(handle int, digest []byte) (*e.Scalar, *e.Scalar, error)
+}
+
+// IssuerPublicKey is the public key of the DAA issuer: BBS++ parameters for credentials on a
+// single hidden device secret and the issuer's verification key.
+type IssuerPublicKey struct {
+	PublicParameters models.PublicParameters
+	VerificationKey  models.VerificationKey
+}
+
+// Issuer issues DAA credentials to TPMs.
+type Issuer struct {
+	publicKey  IssuerPublicKey
+	signingKey models.SigningKey
+}
+
+// JoinRequest is the blinded device secret Q = h_0^f with a proof of knowledge of f bound to the issuer's nonce.
+type JoinRequest struct {
+	Q         *e.G1
+	Challenge *e.Scalar
+	Response  *e.Scalar
+}
+
+// Credential is a BBS++ signature on the device secret, kept by the host together with Q = h_0^f.
+type Credential struct {
+	Signature models.Signature
+	Q         *e.G1
+}
+
+// Attestation is a DAA signature: a proof of knowledge of a credential on the device secret f
+// together with the pseudonym K = J^f, where J is derived from the basename or chosen at random.
+type Attestation struct {
+	Proof models.Proof
+	J     *e.G1
+	K     *e.G1
+}
+
+// RogueList holds the secrets extracted from compromised TPMs. It is safe for concurrent use.
+type RogueList struct {
+	mu      sync.RWMutex
+	secrets []*e.Scalar
+}
+
+// Setup generates the issuer's keys.
+//
+// Returns:
+//   - IssuerPublicKey: The public key of the issuer.
+//   - *Issuer: The issuer.
+//   - error: An error if the key generation fails.
+func Setup() (IssuerPublicKey, *Issuer, error) {
+	result, err := keygen.KeyGen(1)
+	if err != nil {
+		return IssuerPublicKey{}, nil, err
+	}
+	publicKey := IssuerPublicKey{PublicParameters: result.PublicParameters, VerificationKey: result.VerificationKey}
+	return publicKey, &Issuer{publicKey: publicKey, signingKey: result.SigningKey}, nil
+}
+
+// Join creates the join request of a TPM for the issuer's nonce.
+//
+// Parameters:
+//   - publicKey: The public key of the issuer.
+//   - tpm: The TPM of the joining device.
+//   - nonce: The nonce chosen by the issuer.
+//
+// Returns:
+//   - JoinRequest: The blinded device secret with a proof of knowledge.
+//   - error: An error if the TPM fails.
+func Join(publicKey IssuerPublicKey, tpm TPM, nonce []byte) (JoinRequest, error) {
+	h0 := &publicKey.PublicParameters.H1[secretIndex]
+	Q, err := tpm.Commitment(h0)
+	if err != nil {
+		return JoinRequest{}, err
+	}
+	handle, commitments, err := tpm.Commit([]*e.G1{h0})
+	if err != nil {
+		return JoinRequest{}, err
+	}
+	challenge, response, err := tpm.Sign(handle, joinDigest(Q, commitments[0], nonce))
+	if err != nil {
+		return JoinRequest{}, err
+	}
+	return JoinRequest{Q: Q, Challenge: challenge, Response: response}, nil
+}
+
+// Issue checks a join request and signs the blinded device secret: A = (g1·Q)^{1/(x+e)}.
+//
+// Parameters:
+//   - request: The join request of the device.
+//   - nonce: The nonce the request was bound to.
+//   - rogue: The rogue list, or nil.
+//
+// Returns:
+//   - models.Signature: The credential signature.
+//   - error: ErrRogue if the TPM is on the rogue list, or an error if the request is invalid.
+func (i *Issuer) Issue(request JoinRequest, nonce []byte, rogue *RogueList) (models.Signature, error) {
+	if request.Q == nil || request.Challenge == nil || request.Response == nil || request.Q.IsIdentity() {
+		return models.Signature{}, errors.New("join request is missing components")
+	}
+	h0 := &i.publicKey.PublicParameters.H1[secretIndex]
+
+	// Step 1: Recompute R = h_0^s · Q^{-c} and check the TPM's challenge
+	R := utils.MultiExpG1([]*e.G1{h0, request.Q}, []*e.Scalar{request.Response, utils.Neg(request.Challenge)})
+	if tpmChallenge(joinDigest(request.Q, R, nonce)).IsEqual(request.Challenge) != 1 {
+		return models.Signature{}, errors.New("invalid proof of knowledge of the device secret")
+	}
+
+	// Step 2: Reject TPMs whose secret has been extracted
+	if rogue.matches(h0, request.Q) {
+		return models.Signature{}, ErrRogue
+	}
+
+	// Step 3: Sign the commitment C = g1 · Q
+	c := new(e.G1)
+	c.Add(i.publicKey.PublicParameters.G1, request.Q)
+	return sign.SignCommitment(i.signingKey, c)
+}
+
+// VerifyCredential checks the credential received from the issuer before it is used.
+func VerifyCredential(publicKey IssuerPublicKey, credential Credential) bool {
+	if credential.Q == nil || credential.Signature.A == nil || credential.Signature.E == nil {
+		return false
+	}
+	c := new(e.G1)
+	c.Add(publicKey.PublicParameters.G1, credential.Q)
+	return verify.VerifyCommitment(publicKey.PublicParameters, publicKey.VerificationKey, c, credential.Signature)
+}
+
+// Attest produces an attestation signature on a message.
+//
+// Parameters:
+//   - publicKey: The public key of the issuer.
+//   - tpm: The TPM holding the device secret.
+//   - credential: The credential of the device.
+//   - basename: The basename of the verifier; attestations with the same non-empty basename are
+//     linkable, while an empty basename makes the attestation unlinkable.
+//   - message: The attested message.
+//   - nonce: A verifier-supplied nonce bound to the attestation.
+//
+// Returns:
+//   - Attestation: The attestation signature.
+//   - error: An error if the TPM fails or randomness generation fails.
+func Attest(publicKey IssuerPublicKey, tpm TPM, credential Credential, basename, message string, nonce []byte) (Attestation, error) {
+	publicParams := publicKey.PublicParameters
+	h0 := &publicParams.H1[secretIndex]
+
+	// Step 1: Derive J from the basename, or choose it at random, and obtain K = J^f
+	J, err := basenameBase(basename)
+	if err != nil {
+		return Attestation{}, err
+	}
+	K, err := tpm.Commitment(J)
+	if err != nil {
+		return Attestation{}, err
+	}
+
+	// Step 2: The TPM commits to f̃ as U = h_0^{f̃} and W = J^{f̃}
+	handle, commitments, err := tpm.Commit([]*e.G1{h0, J})
+	if err != nil {
+		return Attestation{}, err
+	}
+	U, W := commitments[0], commitments[1]
+
+	// Step 3: The host randomizes the credential as in proof.NewProver, with C = g1 · Q
+	random, err := utils.RandomScalars(5)
+	if err != nil {
+		return Attestation{}, err
+	}
+	r1, r2, r1Tilde, eTilde, r3Tilde := random[0], random[1], random[2], random[3], random[4]
+	r3 := new(e.Scalar)
+	r3.Inv(r2)
+	c := new(e.G1)
+	c.Add(publicParams.G1, credential.Q)
+	d := new(e.G1)
+	d.ScalarMult(r2, c)
+	r1r2 := new(e.Scalar)
+	r1r2.Mul(r1, r2)
+	aBar := new(e.G1)
+	aBar.ScalarMult(r1r2, credential.Signature.A)
+	bBar := utils.MultiExpG1([]*e.G1{d, aBar}, []*e.Scalar{r1, utils.Neg(credential.Signature.E)})
+
+	// Step 4: Commit T1 = D^{r̃1} · Ā^{-ẽ} and T2 = D^{r̃3} · h_0^{-f̃}
+	t1 := utils.MultiExpG1([]*e.G1{d, aBar}, []*e.Scalar{r1Tilde, utils.Neg(eTilde)})
+	t2 := new(e.G1)
+	t2.ScalarMult(r3Tilde, d)
+	negU := *U
+	negU.Neg()
+	t2.Add(t2, &negU)
+
+	// Step 5: The TPM derives the challenge from the host's digest and responds with f̂
+	transcript := proof.Transcript(nil, aBar, bBar, d, t1, t2)
+	challenge, fHat, err := tpm.Sign(handle, attestDigest(transcript, J, K, W, basename, message, nonce))
+	if err != nil {
+		return Attestation{}, err
+	}
+
+	return Attestation{
+		Proof: models.Proof{
+			ABar:      aBar,
+			BBar:      bBar,
+			D:         d,
+			Challenge: challenge,
+			EHat:      proof.Response(eTilde, challenge, credential.Signature.E),
+			R1Hat:     proof.Response(r1Tilde, challenge, r1),
+			R3Hat:     proof.Response(r3Tilde, challenge, r3),
+			MHat:      map[int]*e.Scalar{secretIndex: fHat},
+		},
+		J: J,
+		K: K,
+	}, nil
+}
+
+// Verify checks an attestation signature.
+//
+// Parameters:
+//   - publicKey: The public key of the issuer.
+//   - attestation: The attestation to be verified.
+//   - basename: The basename the attestation must be linked to, or empty for a random base.
+//   - message: The attested message.
+//   - nonce: The nonce the attestation was bound to.
+//   - rogue: The rogue list, or nil.
+//
+// Returns:
+//   - boolean: True if the attestation is valid, false otherwise.
+//   - error: ErrRogue if the TPM is on the rogue list, or an error if the attestation is malformed.
+func Verify(publicKey IssuerPublicKey, attestation Attestation, basename, message string, nonce []byte, rogue *RogueList) (bool, error) {
+	publicParams := publicKey.PublicParameters
+	fHat, ok := attestation.Proof.MHat[secretIndex]
+	if !ok || fHat == nil {
+		return false, errors.New("device secret is not hidden in the attestation")
+	}
+	if attestation.J == nil || attestation.K == nil || attestation.J.IsIdentity() {
+		return false, errors.New("attestation is missing components")
+	}
+	if basename != "" && !attestation.J.IsEqual(utils.HashToG1([]byte(basename), basenameDomain)) {
+		return false, nil
+	}
+
+	// Step 1: Recompute the credential proof transcript and check the pairing equation
+	transcript, err := proof.TranscriptBytes(publicParams, attestation.Proof, nil)
+	if err != nil {
+		return false, err
+	}
+	if !proof.CheckPairing(publicParams, publicKey.VerificationKey, attestation.Proof) {
+		return false, nil
+	}
+	challenge := attestation.Proof.Challenge
+
+	// Step 2: Recompute W = J^{f̂} · K^{-c} and check the challenge
+	W := utils.MultiExpG1([]*e.G1{attestation.J, attestation.K}, []*e.Scalar{fHat, utils.Neg(challenge)})
+	expected := tpmChallenge(attestDigest(transcript, attestation.J, attestation.K, W, basename, message, nonce))
+	if expected.IsEqual(challenge) != 1 {
+		return false, nil
+	}
+
+	// Step 3: Reject attestations of TPMs whose secret has been extracted
+	if rogue.matches(attestation.J, attestation.K) {
+		return false, ErrRogue
+	}
+	return true, nil
+}
+
+// Link reports whether two attestations for the same basename come from the same TPM.
+func Link(a, b Attestation) bool {
+	if a.J == nil || a.K == nil || b.J == nil || b.K == nil {
+		return false
+	}
+	return a.J.IsEqual(b.J) && a.K.IsEqual(b.K)
+}
+
+// Add puts the secret extracted from a compromised TPM on the rogue list.
+func (r *RogueList) Add(secret *e.Scalar) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.secrets = append(r.secrets, secret)
+}
+
+// Len returns the number of rogue secrets.
+func (r *RogueList) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.secrets)
+}
+
+// matches reports whether K = J^f for a rogue secret f. A nil list matches nothing.
+func (r *RogueList) matches(J, K *e.G1) bool {
+	if r == nil {
+		return false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	candidate := new(e.G1)
+	for _, secret := range r.secrets {
+		candidate.ScalarMult(secret, J)
+		if candidate.IsEqual(K) {
+			return true
+		}
+	}
+	return false
+}
+
+// basenameBase returns the base J of a basename, or a random base for an empty basename.
+func basenameBase(basename string) (*e.G1, error) {
+	if basename != "" {
+		return utils.HashToG1([]byte(basename), basenameDomain), nil
+	}
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, errors.New("failed to generate random base")
+	}
+	return utils.HashToG1(salt, basenameDomain), nil
+}
+
+// joinDigest hashes the blinded device secret, the TPM's commitment and the issuer's nonce.
+func joinDigest(Q, R *e.G1, nonce []byte) []byte {
+	return utils.ScalarToBytes(utils.HashToScalar([]byte(joinDomain), Q.BytesCompressed(), R.BytesCompressed(), nonce))
+}
+
+// attestDigest hashes the credential proof transcript, the pseudonym and its commitment, the basename,
+// the message and the nonce.
+func attestDigest(transcript []byte, J, K, W *e.G1, basename, message string, nonce []byte) []byte {
+	return utils.ScalarToBytes(utils.HashToScalar(
+		[]byte(attestDomain),
+		transcript,
+		J.BytesCompressed(),
+		K.BytesCompressed(),
+		W.BytesCompressed(),
+		[]byte(basename),
+		[]byte(message),
+		nonce,
+	))
+}
+
+// tpmChallenge derives the challenge from a host digest as the TPM does.
+func tpmChallenge(digest []byte) *e.Scalar {
+	return utils.HashToScalar([]byte(tpmDomain), digest)
+}
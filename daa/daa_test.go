@@ -0,0 +1,122 @@
+package daa
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// join runs the join protocol for a new software TPM.
+func join(t *testing.T, publicKey IssuerPublicKey, issuer *Issuer, rogue *RogueList) (*SoftwareTPM, Credential) {
+	tpm, err := NewSoftwareTPM()
+	assert.NoError(t, err, "NewSoftwareTPM should not return an error")
+	nonce := []byte("issuer nonce")
+	request, err := Join(publicKey, tpm, nonce)
+	assert.NoError(t, err, "Join should not return an error")
+	signature, err := issuer.Issue(request, nonce, rogue)
+	assert.NoError(t, err, "Issue should not return an error")
+	credential := Credential{Signature: signature, Q: request.Q}
+	assert.True(t, VerifyCredential(publicKey, credential), "Issued credential should be valid")
+	return tpm, credential
+}
+
+// TestJoinReplay tests that a join request cannot be reused for another nonce.
+func TestJoinReplay(t *testing.T) {
+	publicKey, issuer, err := Setup()
+	assert.NoError(t, err, "Setup should not return an error")
+	tpm, err := NewSoftwareTPM()
+	assert.NoError(t, err, "NewSoftwareTPM should not return an error")
+
+	request, err := Join(publicKey, tpm, []byte("nonce 1"))
+	assert.NoError(t, err, "Join should not return an error")
+	_, err = issuer.Issue(request, []byte("nonce 2"), nil)
+	assert.Error(t, err, "Issue should reject a request for another nonce")
+}
+
+// TestAttestVerify tests that attestations verify and are bound to the message and basename.
+func TestAttestVerify(t *testing.T) {
+	publicKey, issuer, err := Setup()
+	assert.NoError(t, err, "Setup should not return an error")
+	tpm, credential := join(t, publicKey, issuer, nil)
+	nonce := []byte("verifier nonce")
+
+	attestation, err := Attest(publicKey, tpm, credential, "verifier.example", "pcr digest", nonce)
+	assert.NoError(t, err, "Attest should not return an error")
+
+	isValid, err := Verify(publicKey, attestation, "verifier.example", "pcr digest", nonce, nil)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.True(t, isValid, "Verify should accept a valid attestation")
+
+	isValid, err = Verify(publicKey, attestation, "verifier.example", "other digest", nonce, nil)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.False(t, isValid, "Verify should reject an attestation on another message")
+
+	isValid, err = Verify(publicKey, attestation, "other.example", "pcr digest", nonce, nil)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.False(t, isValid, "Verify should reject an attestation for another basename")
+}
+
+// TestLinkability tests that attestations link exactly for equal basenames of the same TPM.
+func TestLinkability(t *testing.T) {
+	publicKey, issuer, err := Setup()
+	assert.NoError(t, err, "Setup should not return an error")
+	tpm, credential := join(t, publicKey, issuer, nil)
+	otherTPM, otherCredential := join(t, publicKey, issuer, nil)
+	nonce := []byte("nonce")
+
+	attest := func(tpm TPM, credential Credential, basename string) Attestation {
+		attestation, err := Attest(publicKey, tpm, credential, basename, "message", nonce)
+		assert.NoError(t, err, "Attest should not return an error")
+		isValid, err := Verify(publicKey, attestation, basename, "message", nonce, nil)
+		assert.NoError(t, err, "Verify should not return an error")
+		assert.True(t, isValid, "Verify should accept a valid attestation")
+		return attestation
+	}
+
+	first := attest(tpm, credential, "bsn")
+	assert.True(t, Link(first, attest(tpm, credential, "bsn")), "Same TPM and basename should link")
+	assert.False(t, Link(first, attest(otherTPM, otherCredential, "bsn")), "Different TPMs should not link")
+	assert.False(t, Link(first, attest(tpm, credential, "other bsn")), "Different basenames should not link")
+	assert.False(t, Link(attest(tpm, credential, ""), attest(tpm, credential, "")), "Random bases should not link")
+}
+
+// TestRogueList tests that a TPM with an extracted secret can neither attest nor join again.
+func TestRogueList(t *testing.T) {
+	publicKey, issuer, err := Setup()
+	assert.NoError(t, err, "Setup should not return an error")
+	var rogue RogueList
+	tpm, credential := join(t, publicKey, issuer, &rogue)
+	honestTPM, honestCredential := join(t, publicKey, issuer, &rogue)
+	nonce := []byte("nonce")
+
+	rogue.Add(tpm.Extract())
+	assert.Equal(t, 1, rogue.Len())
+
+	attestation, err := Attest(publicKey, tpm, credential, "", "message", nonce)
+	assert.NoError(t, err, "Attest should not return an error")
+	_, err = Verify(publicKey, attestation, "", "message", nonce, &rogue)
+	assert.ErrorIs(t, err, ErrRogue, "Verify should reject a rogue TPM")
+
+	attestation, err = Attest(publicKey, honestTPM, honestCredential, "", "message", nonce)
+	assert.NoError(t, err, "Attest should not return an error")
+	isValid, err := Verify(publicKey, attestation, "", "message", nonce, &rogue)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.True(t, isValid, "Verify should accept an honest TPM")
+
+	request, err := Join(publicKey, tpm, nonce)
+	assert.NoError(t, err, "Join should not return an error")
+	_, err = issuer.Issue(request, nonce, &rogue)
+	assert.ErrorIs(t, err, ErrRogue, "Issue should reject a rogue TPM")
+}
+
+// TestTPMHandleReuse tests that a TPM handle cannot be used twice.
+func TestTPMHandleReuse(t *testing.T) {
+	tpm, err := NewSoftwareTPM()
+	assert.NoError(t, err, "NewSoftwareTPM should not return an error")
+	handle, _, err := tpm.Commit(nil)
+	assert.NoError(t, err, "Commit should not return an error")
+	_, _, err = tpm.Sign(handle, []byte("digest"))
+	assert.NoError(t, err, "Sign should not return an error")
+	_, _, err = tpm.Sign(handle, []byte("digest"))
+	assert.Error(t, err, "Sign should reject a used handle")
+}
@@ -0,0 +1,76 @@
+package daa
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	e "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+// SoftwareTPM is a TPM implemented in software, for tests and devices without a hardware TPM.
+// It is safe for concurrent use.
+type SoftwareTPM struct {
+	f *e.Scalar
+
+	mu      sync.Mutex
+	next    int
+	pending map[int]*e.Scalar
+}
+
+// NewSoftwareTPM creates a software TPM with a fresh device secret.
+func NewSoftwareTPM() (*SoftwareTPM, error) {
+	f, err := utils.RandomScalar()
+	if err != nil {
+		return nil, err
+	}
+	return &SoftwareTPM{f: &f, pending: make(map[int]*e.Scalar)}, nil
+}
+
+// Commitment returns b^f for the device secret f.
+func (t *SoftwareTPM) Commitment(base *e.G1) (*e.G1, error) {
+	commitment := new(e.G1)
+	commitment.ScalarMult(t.f, base)
+	return commitment, nil
+}
+
+// Commit samples a fresh r and returns b^r for every base together with a handle for Sign.
+func (t *SoftwareTPM) Commit(bases []*e.G1) (int, []*e.G1, error) {
+	r, err := utils.RandomScalar()
+	if err != nil {
+		return 0, nil, err
+	}
+	commitments := make([]*e.G1, len(bases))
+	for i, base := range bases {
+		commitments[i] = new(e.G1)
+		commitments[i].ScalarMult(&r, base)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.next++
+	t.pending[t.next] = &r
+	return t.next, commitments, nil
+}
+
+// Sign derives the challenge c from the digest and returns c and r + c·f for the handle's r.
+func (t *SoftwareTPM) Sign(handle int, digest []byte) (*e.Scalar, *e.Scalar, error) {
+	t.mu.Lock()
+	r, ok := t.pending[handle]
+	delete(t.pending, handle)
+	t.mu.Unlock()
+	if !ok {
+		return nil, nil, errors.New("unknown or already used TPM handle")
+	}
+	challenge := tpmChallenge(digest)
+	response := new(e.Scalar)
+	response.Mul(challenge, t.f)
+	response.Add(response, r)
+	return challenge, response, nil
+}
+
+// Extract returns the device secret. It models extracting the key from a compromised device,
+// so that the secret can be published on a rogue list.
+func (t *SoftwareTPM) Extract() *e.Scalar {
+	return t.f
+}
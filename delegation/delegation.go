@@ -0,0 +1,287 @@
+package delegation
+
+import (
+	"errors"
+
+	"github.com/aniagut/msc-bbs-plus-plus/keygen"
+	"github.com/aniagut/msc-bbs-plus-plus/models"
+	"github.com/aniagut/msc-bbs-plus-plus/proof"
+	"github.com/aniagut/msc-bbs-plus-plus/sign"
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	"github.com/aniagut/msc-bbs-plus-plus/verify"
+	e "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+const (
+	// presentationDomain separates delegated presentation challenges from other hashes.
+	presentationDomain = "BBS++-DELEGATED-PRESENTATION-V1"
+	// randomizerDomain is the domain separation tag used to derive the key randomization bases.
+	randomizerDomain = "BBS++-DELEGATED-KEY-RANDOMIZER-V1"
+)
+
+// Parameters are the system parameters published by the root authority.
+//
+// All regional issuers sign credentials under the same PublicParameters, so that the parameters
+// do not reveal the issuer. The root certifies issuers with its own BBS++ key over a single
+// message, the issuer secret x, received as the commitment H_x = h_r^x.
+type Parameters struct {
+	PublicParameters models.PublicParameters
+	RootParameters   models.PublicParameters
+	RootKey          models.VerificationKey
+	K1               *e.G1
+	K2               *e.G2
+}
+
+// Root is the root authority that certifies regional issuers.
+type Root struct {
+	signingKey models.SigningKey
+}
+
+// IssuerPublicKey is the public key of a regional issuer: X2 = g2^x and H_x = h_r^x.
+type IssuerPublicKey struct {
+	VerificationKey models.VerificationKey
+	HX              *e.G1
+}
+
+// Issuer is a regional issuer.
+type Issuer struct {
+	SigningKey models.SigningKey
+	PublicKey  IssuerPublicKey
+}
+
+// Presentation proves possession of a credential from some issuer certified by the root without
+// revealing the issuer. The issuer key is randomized as X' = X2 · k2^ρ and H' = H_x · k1^ρ', and
+// the proof shows
+//   - e(Ā, X') / e(B̄, g2) = e(Ā, k2)^ρ: the credential is valid under X' · k2^{-ρ},
+//   - e(H', g2) / e(h_r, X') = e(k1, g2)^ρ' · e(h_r, k2)^{-ρ}: both randomized keys hide the same x,
+//   - g1 · H' = B̄_r^u · Ā_r^v · k1^ρ' with e(Ā_r, X_r) = e(B̄_r, g2): the root certified H' · k1^{-ρ'}.
+type Presentation struct {
+	Proof       models.Proof
+	XPrime      *e.G2
+	HPrime      *e.G1
+	ABarRoot    *e.G1
+	BBarRoot    *e.G1
+	RhoHat      *e.Scalar
+	RhoPrimeHat *e.Scalar
+	UHat        *e.Scalar
+	VHat        *e.Scalar
+}
+
+// Setup generates the system parameters for credentials on l messages and the root authority.
+//
+// Returns:
+//   - Parameters: The system parameters.
+//   - *Root: The root authority.
+//   - error: An error if the key generation fails.
+func Setup(l int) (Parameters, *Root, error) {
+	// 1. Generate the root's BBS++ key for certificates on a single message
+	root, err := keygen.KeyGen(1)
+	if err != nil {
+		return Parameters{}, nil, err
+	}
+
+	// 2. Generate the shared credential parameters
+	h1, err := utils.GenerateLRandomG1Elements(l)
+	if err != nil {
+		return Parameters{}, nil, err
+	}
+
+	return Parameters{
+		PublicParameters: models.PublicParameters{G1: e.G1Generator(), G2: e.G2Generator(), H1: h1},
+		RootParameters:   root.PublicParameters,
+		RootKey:          root.VerificationKey,
+		K1:               utils.HashToG1([]byte("k1"), randomizerDomain),
+		K2:               utils.HashToG2([]byte("k2"), randomizerDomain),
+	}, &Root{signingKey: root.SigningKey}, nil
+}
+
+// NewIssuer generates the key of a regional issuer.
+func NewIssuer(params Parameters) (Issuer, error) {
+	x, err := utils.RandomScalar()
+	if err != nil {
+		return Issuer{}, err
+	}
+	X2 := new(e.G2)
+	X2.ScalarMult(&x, params.PublicParameters.G2)
+	HX := new(e.G1)
+	HX.ScalarMult(&x, &params.RootParameters.H1[0])
+	return Issuer{
+		SigningKey: models.SigningKey{X: &x},
+		PublicKey:  IssuerPublicKey{VerificationKey: models.VerificationKey{X2: X2}, HX: HX},
+	}, nil
+}
+
+// Certify signs an issuer's key: the root checks e(H_x, g2) = e(h_r, X2) and signs the
+// commitment g1 · H_x to the issuer secret without learning it.
+func (r *Root) Certify(params Parameters, issuer IssuerPublicKey) (models.Signature, error) {
+	if !consistent(params, issuer) {
+		return models.Signature{}, errors.New("issuer key components do not match")
+	}
+	c := new(e.G1)
+	c.Add(params.RootParameters.G1, issuer.HX)
+	return sign.SignCommitment(r.signingKey, c)
+}
+
+// VerifyCertificate checks the root's certificate on an issuer's key.
+func VerifyCertificate(params Parameters, issuer IssuerPublicKey, certificate models.Signature) bool {
+	if !consistent(params, issuer) || certificate.A == nil || certificate.E == nil {
+		return false
+	}
+	c := new(e.G1)
+	c.Add(params.RootParameters.G1, issuer.HX)
+	return verify.VerifyCommitment(params.RootParameters, params.RootKey, c, certificate)
+}
+
+// Present generates a presentation of a credential from a certified issuer that hides the issuer.
+//
+// Parameters:
+//   - params: The system parameters.
+//   - issuer: The public key of the issuer of the credential.
+//   - certificate: The root's certificate on the issuer's key.
+//   - signature: The credential signature.
+//   - m: The full message vector.
+//   - disclosed: The indexes of the messages revealed to the verifier.
+//   - nonce: A verifier-supplied nonce bound to the presentation.
+//
+// Returns:
+//   - Presentation: The generated presentation.
+//   - error: An error if the proof cannot be generated.
+func Present(params Parameters, issuer IssuerPublicKey, certificate models.Signature, signature models.Signature, m []string, disclosed []int, nonce []byte) (Presentation, error) {
+	g1, g2 := params.PublicParameters.G1, params.PublicParameters.G2
+	hr := &params.RootParameters.H1[0]
+
+	// Step 1: Commitment phase of the credential proof
+	prover, err := proof.NewProver(params.PublicParameters, signature, m, disclosed, nil)
+	if err != nil {
+		return Presentation{}, err
+	}
+	aBar, _ := prover.Randomized()
+
+	// Step 2: Randomize the issuer key X' = X2 · k2^ρ and H' = H_x · k1^ρ'
+	random, err := utils.RandomScalars(7)
+	if err != nil {
+		return Presentation{}, err
+	}
+	rho, rhoPrime, r1 := random[0], random[1], random[2]
+	rhoTilde, rhoPrimeTilde, uTilde, vTilde := random[3], random[4], random[5], random[6]
+	xPrime := new(e.G2)
+	xPrime.ScalarMult(rho, params.K2)
+	xPrime.Add(xPrime, issuer.VerificationKey.X2)
+	hPrime := new(e.G1)
+	hPrime.ScalarMult(rhoPrime, params.K1)
+	hPrime.Add(hPrime, issuer.HX)
+
+	// Step 3: Randomize the certificate Ā_r = A_r^{r1}, B̄_r = (g1 · H_x)^{r1} · Ā_r^{-e_r}, with u = 1/r1 and v = e_r/r1
+	aBarRoot := new(e.G1)
+	aBarRoot.ScalarMult(r1, certificate.A)
+	c := new(e.G1)
+	c.Add(g1, issuer.HX)
+	bBarRoot := utils.MultiExpG1([]*e.G1{c, aBarRoot}, []*e.Scalar{r1, utils.Neg(certificate.E)})
+	u := new(e.Scalar)
+	u.Inv(r1)
+	v := new(e.Scalar)
+	v.Mul(certificate.E, u)
+
+	// Step 4: Commit to the blindings of ρ, ρ', u and v
+	gt1 := e.ProdPair([]*e.G1{aBar}, []*e.G2{params.K2}, []*e.Scalar{rhoTilde})
+	gt2 := e.ProdPair([]*e.G1{params.K1, hr}, []*e.G2{g2, params.K2}, []*e.Scalar{rhoPrimeTilde, utils.Neg(rhoTilde)})
+	w := utils.MultiExpG1([]*e.G1{bBarRoot, aBarRoot, params.K1}, []*e.Scalar{uTilde, vTilde, rhoPrimeTilde})
+
+	// Step 5: Derive the challenge and compute the responses
+	presentation := Presentation{XPrime: xPrime, HPrime: hPrime, ABarRoot: aBarRoot, BBarRoot: bBarRoot}
+	challenge := presentationChallenge(prover.Bytes(), presentation, gt1, gt2, w, nonce)
+	presentation.Proof = prover.Respond(challenge)
+	presentation.RhoHat = proof.Response(rhoTilde, challenge, rho)
+	presentation.RhoPrimeHat = proof.Response(rhoPrimeTilde, challenge, rhoPrime)
+	presentation.UHat = proof.Response(uTilde, challenge, u)
+	presentation.VHat = proof.Response(vTilde, challenge, v)
+	return presentation, nil
+}
+
+// Verify checks a delegated presentation against the root's parameters only.
+//
+// Parameters:
+//   - params: The system parameters.
+//   - presentation: The presentation to be verified.
+//   - revealed: The disclosed messages, indexed by their position in the message vector.
+//   - nonce: The nonce the presentation was bound to.
+//
+// Returns:
+//   - boolean: True if the presentation is valid, false otherwise.
+//   - error: An error if the presentation is malformed.
+func Verify(params Parameters, presentation Presentation, revealed map[int]string, nonce []byte) (bool, error) {
+	if presentation.XPrime == nil || presentation.HPrime == nil || presentation.ABarRoot == nil || presentation.BBarRoot == nil ||
+		presentation.RhoHat == nil || presentation.RhoPrimeHat == nil || presentation.UHat == nil || presentation.VHat == nil {
+		return false, errors.New("presentation is missing components")
+	}
+	g1, g2 := params.PublicParameters.G1, params.PublicParameters.G2
+	hr := &params.RootParameters.H1[0]
+
+	// Step 1: Recompute the credential proof transcript
+	transcript, err := proof.TranscriptBytes(params.PublicParameters, presentation.Proof, revealed)
+	if err != nil {
+		return false, err
+	}
+	aBar, bBar := presentation.Proof.ABar, presentation.Proof.BBar
+	if aBar.IsIdentity() || presentation.ABarRoot.IsIdentity() {
+		return false, nil
+	}
+
+	// Step 2: Check the root's certificate pairing e(Ā_r, X_r) = e(B̄_r, g2)
+	if !e.Pair(presentation.ABarRoot, params.RootKey.X2).IsEqual(e.Pair(presentation.BBarRoot, g2)) {
+		return false, nil
+	}
+
+	// Step 3: Recompute the commitments from the responses
+	challenge := presentation.Proof.Challenge
+	negC := utils.Neg(challenge)
+	gt1 := e.ProdPair(
+		[]*e.G1{aBar, aBar, bBar},
+		[]*e.G2{params.K2, presentation.XPrime, g2},
+		[]*e.Scalar{presentation.RhoHat, negC, challenge},
+	)
+	gt2 := e.ProdPair(
+		[]*e.G1{params.K1, hr, presentation.HPrime, hr},
+		[]*e.G2{g2, params.K2, g2, presentation.XPrime},
+		[]*e.Scalar{presentation.RhoPrimeHat, utils.Neg(presentation.RhoHat), negC, challenge},
+	)
+	c := new(e.G1)
+	c.Add(g1, presentation.HPrime)
+	w := utils.MultiExpG1(
+		[]*e.G1{presentation.BBarRoot, presentation.ABarRoot, params.K1, c},
+		[]*e.Scalar{presentation.UHat, presentation.VHat, presentation.RhoPrimeHat, negC},
+	)
+
+	// Step 4: Check the challenge
+	expected := presentationChallenge(transcript, presentation, gt1, gt2, w, nonce)
+	return expected.IsEqual(challenge) == 1, nil
+}
+
+// consistent checks that both components of an issuer key hide the same secret: e(H_x, g2) = e(h_r, X2).
+func consistent(params Parameters, issuer IssuerPublicKey) bool {
+	if issuer.HX == nil || issuer.VerificationKey.X2 == nil || issuer.HX.IsIdentity() {
+		return false
+	}
+	left := e.Pair(issuer.HX, params.RootParameters.G2)
+	right := e.Pair(&params.RootParameters.H1[0], issuer.VerificationKey.X2)
+	return left.IsEqual(right)
+}
+
+// presentationChallenge hashes the credential proof transcript, the randomized keys and certificate,
+// the commitments and the nonce.
+func presentationChallenge(transcript []byte, presentation Presentation, gt1, gt2 *e.Gt, w *e.G1, nonce []byte) *e.Scalar {
+	gt1Bytes, _ := gt1.MarshalBinary()
+	gt2Bytes, _ := gt2.MarshalBinary()
+	return utils.HashToScalar(
+		[]byte(presentationDomain),
+		transcript,
+		presentation.XPrime.BytesCompressed(),
+		presentation.HPrime.BytesCompressed(),
+		presentation.ABarRoot.BytesCompressed(),
+		presentation.BBarRoot.BytesCompressed(),
+		gt1Bytes,
+		gt2Bytes,
+		w.BytesCompressed(),
+		nonce,
+	)
+}
@@ -0,0 +1,77 @@
+package delegation
+
+import (
+	"testing"
+
+	"github.com/aniagut/msc-bbs-plus-plus/models"
+	"github.com/aniagut/msc-bbs-plus-plus/sign"
+	"github.com/stretchr/testify/assert"
+)
+
+// certifiedIssuer creates a regional issuer and its certificate.
+func certifiedIssuer(t *testing.T, params Parameters, root *Root) (Issuer, models.Signature) {
+	issuer, err := NewIssuer(params)
+	assert.NoError(t, err, "NewIssuer should not return an error")
+	certificate, err := root.Certify(params, issuer.PublicKey)
+	assert.NoError(t, err, "Certify should not return an error")
+	assert.True(t, VerifyCertificate(params, issuer.PublicKey, certificate), "Certificate should be valid")
+	return issuer, certificate
+}
+
+// TestPresentVerify tests that credentials of any certified issuer are accepted.
+func TestPresentVerify(t *testing.T) {
+	params, root, err := Setup(3)
+	assert.NoError(t, err, "Setup should not return an error")
+	messages := []string{"alice", "student", "2026"}
+	nonce := []byte("nonce")
+	revealed := map[int]string{1: "student"}
+
+	for i := 0; i < 2; i++ {
+		issuer, certificate := certifiedIssuer(t, params, root)
+		signature, err := sign.Sign(params.PublicParameters, issuer.SigningKey, messages)
+		assert.NoError(t, err, "Sign should not return an error")
+
+		presentation, err := Present(params, issuer.PublicKey, certificate, signature, messages, []int{1}, nonce)
+		assert.NoError(t, err, "Present should not return an error")
+		isValid, err := Verify(params, presentation, revealed, nonce)
+		assert.NoError(t, err, "Verify should not return an error")
+		assert.True(t, isValid, "Verify should accept a credential of a certified issuer")
+
+		isValid, err = Verify(params, presentation, map[int]string{1: "teacher"}, nonce)
+		assert.NoError(t, err, "Verify should not return an error")
+		assert.False(t, isValid, "Verify should reject other disclosed messages")
+	}
+}
+
+// TestUncertifiedIssuer tests that credentials of an issuer without a valid certificate are rejected.
+func TestUncertifiedIssuer(t *testing.T) {
+	params, root, err := Setup(2)
+	assert.NoError(t, err, "Setup should not return an error")
+	_, certificate := certifiedIssuer(t, params, root)
+	rogue, err := NewIssuer(params)
+	assert.NoError(t, err, "NewIssuer should not return an error")
+	assert.False(t, VerifyCertificate(params, rogue.PublicKey, certificate), "Certificate should not transfer to another issuer")
+
+	messages := []string{"alice", "student"}
+	signature, err := sign.Sign(params.PublicParameters, rogue.SigningKey, messages)
+	assert.NoError(t, err, "Sign should not return an error")
+	presentation, err := Present(params, rogue.PublicKey, certificate, signature, messages, nil, []byte("nonce"))
+	assert.NoError(t, err, "Present should not return an error")
+	isValid, err := Verify(params, presentation, map[int]string{}, []byte("nonce"))
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.False(t, isValid, "Verify should reject a credential of an uncertified issuer")
+}
+
+// TestCertifyInconsistentKey tests that the root refuses keys whose components do not match.
+func TestCertifyInconsistentKey(t *testing.T) {
+	params, root, err := Setup(1)
+	assert.NoError(t, err, "Setup should not return an error")
+	first, err := NewIssuer(params)
+	assert.NoError(t, err, "NewIssuer should not return an error")
+	second, err := NewIssuer(params)
+	assert.NoError(t, err, "NewIssuer should not return an error")
+
+	mixed := IssuerPublicKey{VerificationKey: first.PublicKey.VerificationKey, HX: second.PublicKey.HX}
+	_, err = root.Certify(params, mixed)
+	assert.Error(t, err, "Certify should reject an inconsistent key")
+}
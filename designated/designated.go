@@ -0,0 +1,205 @@
+package designated
+
+import (
+	"errors"
+
+	"github.com/aniagut/msc-bbs-plus-plus/models"
+	"github.com/aniagut/msc-bbs-plus-plus/proof"
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	e "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+// presentationDomain separates designated-verifier presentation challenges from other hashes.
+const presentationDomain = "BBS++-DESIGNATED-VERIFIER-PRESENTATION-V1"
+
+// Presentation is a designated-verifier presentation: an OR proof of "I know a signature on the
+// messages" and "I know the verifier's secret key y". The signature proof carries its own share of
+// the challenge c1 in Proof.Challenge; the key branch carries c2 and its response, and
+// c1 + c2 must equal the challenge derived from both commitments.
+//
+// The verifier knows y, so it could have produced the presentation itself with Simulate, and the
+// presentation does not convince anyone else.
+type Presentation struct {
+	Proof        models.Proof
+	KeyChallenge *e.Scalar
+	KeyResponse  *e.Scalar
+}
+
+// KeyGen generates the key pair of a designated verifier.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//
+// Returns:
+//   - VerifierSecretKey: The secret key kept by the verifier.
+//   - VerifierPublicKey: The public key given to holders.
+//   - error: An error if the key generation fails.
+func KeyGen(publicParams models.PublicParameters) (models.VerifierSecretKey, models.VerifierPublicKey, error) {
+	y, err := utils.RandomScalar()
+	if err != nil {
+		return models.VerifierSecretKey{}, models.VerifierPublicKey{}, err
+	}
+	Y := new(e.G1)
+	Y.ScalarMult(&y, publicParams.G1)
+	return models.VerifierSecretKey{Y: &y}, models.VerifierPublicKey{Y: Y}, nil
+}
+
+// Present generates a presentation for a designated verifier.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - verifierKey: The public key of the designated verifier.
+//   - signature: The signature on the messages.
+//   - m: The full message vector.
+//   - disclosed: The indexes of the messages revealed to the verifier.
+//   - nonce: A verifier-supplied nonce bound to the presentation.
+//
+// Returns:
+//   - Presentation: The generated presentation.
+//   - error: An error if the proof cannot be generated.
+func Present(publicParams models.PublicParameters, verifierKey models.VerifierPublicKey, signature models.Signature, m []string, disclosed []int, nonce []byte) (Presentation, error) {
+	if verifierKey.Y == nil {
+		return Presentation{}, errors.New("verifier key is missing")
+	}
+
+	// Step 1: Commitment phase of the signature proof
+	prover, err := proof.NewProver(publicParams, signature, m, disclosed, nil)
+	if err != nil {
+		return Presentation{}, err
+	}
+
+	// Step 2: Simulate the key branch W = g1^{s2} · Y^{-c2}
+	random, err := utils.RandomScalars(2)
+	if err != nil {
+		return Presentation{}, err
+	}
+	c2, s2 := random[0], random[1]
+	w := keyCommitment(publicParams, verifierKey, c2, s2)
+
+	// Step 3: Derive the challenge and answer the signature branch with c1 = c - c2
+	challenge := presentationChallenge(prover.Bytes(), verifierKey, w, nonce)
+	c1 := new(e.Scalar)
+	c1.Sub(challenge, c2)
+
+	return Presentation{Proof: prover.Respond(c1), KeyChallenge: c2, KeyResponse: s2}, nil
+}
+
+// Verify checks a designated-verifier presentation.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - verificationKey: The verification key of the issuer.
+//   - verifierKey: The public key of the designated verifier.
+//   - presentation: The presentation to be verified.
+//   - revealed: The disclosed messages, indexed by their position in the message vector.
+//   - nonce: The nonce the presentation was bound to.
+//
+// Returns:
+//   - boolean: True if the presentation is valid, false otherwise.
+//   - error: An error if the presentation is malformed.
+func Verify(publicParams models.PublicParameters, verificationKey models.VerificationKey, verifierKey models.VerifierPublicKey, presentation Presentation, revealed map[int]string, nonce []byte) (bool, error) {
+	if verifierKey.Y == nil {
+		return false, errors.New("verifier key is missing")
+	}
+	if presentation.KeyChallenge == nil || presentation.KeyResponse == nil {
+		return false, errors.New("presentation is missing the key branch")
+	}
+
+	// Step 1: Recompute the signature branch and check the pairing
+	transcript, err := proof.TranscriptBytes(publicParams, presentation.Proof, revealed)
+	if err != nil {
+		return false, err
+	}
+	if !proof.CheckPairing(publicParams, verificationKey, presentation.Proof) {
+		return false, nil
+	}
+
+	// Step 2: Recompute the key branch and check that c1 + c2 is the challenge
+	w := keyCommitment(publicParams, verifierKey, presentation.KeyChallenge, presentation.KeyResponse)
+	sum := new(e.Scalar)
+	sum.Add(presentation.Proof.Challenge, presentation.KeyChallenge)
+	return presentationChallenge(transcript, verifierKey, w, nonce).IsEqual(sum) == 1, nil
+}
+
+// Simulate produces a presentation that verifies for any disclosed messages, using the verifier's
+// secret key instead of a signature. The randomized signature (Ā, B̄) is re-randomized from any
+// earlier presentation of a credential of the same issuer, which reveals nothing about its holder.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - secretKey: The secret key of the designated verifier.
+//   - reference: A signature proof from an earlier presentation of the issuer's credentials.
+//   - revealed: The disclosed messages the simulated presentation claims.
+//   - nonce: The nonce the presentation is bound to.
+//
+// Returns:
+//   - Presentation: The simulated presentation.
+//   - error: An error if the inputs are inconsistent or randomness generation fails.
+func Simulate(publicParams models.PublicParameters, secretKey models.VerifierSecretKey, reference models.Proof, revealed map[int]string, nonce []byte) (Presentation, error) {
+	if secretKey.Y == nil || reference.ABar == nil || reference.BBar == nil {
+		return Presentation{}, errors.New("simulation needs the verifier key and a reference proof")
+	}
+	l := len(publicParams.H1)
+	hidden := l - len(revealed)
+	if hidden < 0 {
+		return Presentation{}, errors.New("too many disclosed messages")
+	}
+
+	// Step 1: Re-randomize (Ā, B̄) and simulate the signature branch for a random c1
+	random, err := utils.RandomScalars(7 + hidden)
+	if err != nil {
+		return Presentation{}, err
+	}
+	t, k := random[0], random[1]
+	simulated := models.Proof{
+		ABar:      new(e.G1),
+		BBar:      new(e.G1),
+		D:         new(e.G1),
+		Challenge: random[2],
+		EHat:      random[3],
+		R1Hat:     random[4],
+		R3Hat:     random[5],
+		MHat:      make(map[int]*e.Scalar, hidden),
+	}
+	simulated.ABar.ScalarMult(t, reference.ABar)
+	simulated.BBar.ScalarMult(t, reference.BBar)
+	simulated.D.ScalarMult(random[6], publicParams.G1)
+	next := 7
+	for j := 0; j < l; j++ {
+		if _, ok := revealed[j]; !ok {
+			simulated.MHat[j] = random[next]
+			next++
+		}
+	}
+	transcript, err := proof.TranscriptBytes(publicParams, simulated, revealed)
+	if err != nil {
+		return Presentation{}, err
+	}
+
+	// Step 2: Prove knowledge of y with W = g1^k for c2 = c - c1
+	verifierKey := models.VerifierPublicKey{Y: new(e.G1)}
+	verifierKey.Y.ScalarMult(secretKey.Y, publicParams.G1)
+	w := new(e.G1)
+	w.ScalarMult(k, publicParams.G1)
+	c2 := new(e.Scalar)
+	c2.Sub(presentationChallenge(transcript, verifierKey, w, nonce), simulated.Challenge)
+
+	return Presentation{Proof: simulated, KeyChallenge: c2, KeyResponse: proof.Response(k, c2, secretKey.Y)}, nil
+}
+
+// keyCommitment recomputes the commitment W = g1^{s2} · Y^{-c2} of the key branch.
+func keyCommitment(publicParams models.PublicParameters, verifierKey models.VerifierPublicKey, challenge, response *e.Scalar) *e.G1 {
+	return utils.MultiExpG1([]*e.G1{publicParams.G1, verifierKey.Y}, []*e.Scalar{response, utils.Neg(challenge)})
+}
+
+// presentationChallenge hashes the signature proof transcript, the verifier key, the key branch
+// commitment and the nonce.
+func presentationChallenge(transcript []byte, verifierKey models.VerifierPublicKey, w *e.G1, nonce []byte) *e.Scalar {
+	return utils.HashToScalar(
+		[]byte(presentationDomain),
+		transcript,
+		verifierKey.Y.BytesCompressed(),
+		w.BytesCompressed(),
+		nonce,
+	)
+}
@@ -0,0 +1,68 @@
+package designated
+
+import (
+	"testing"
+
+	"github.com/aniagut/msc-bbs-plus-plus/keygen"
+	"github.com/aniagut/msc-bbs-plus-plus/sign"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPresentVerify tests that a presentation verifies for its designated verifier only.
+func TestPresentVerify(t *testing.T) {
+	result, err := keygen.KeyGen(3)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	publicParams := result.PublicParameters
+	messages := []string{"alice", "student", "2026"}
+	signature, err := sign.Sign(publicParams, result.SigningKey, messages)
+	assert.NoError(t, err, "Sign should not return an error")
+	_, verifierKey, err := KeyGen(publicParams)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	_, otherKey, err := KeyGen(publicParams)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	nonce := []byte("nonce")
+	revealed := map[int]string{1: "student"}
+
+	presentation, err := Present(publicParams, verifierKey, signature, messages, []int{1}, nonce)
+	assert.NoError(t, err, "Present should not return an error")
+
+	isValid, err := Verify(publicParams, result.VerificationKey, verifierKey, presentation, revealed, nonce)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.True(t, isValid, "Verify should accept a valid presentation")
+
+	isValid, err = Verify(publicParams, result.VerificationKey, otherKey, presentation, revealed, nonce)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.False(t, isValid, "Verify should reject a presentation made for another verifier")
+
+	isValid, err = Verify(publicParams, result.VerificationKey, verifierKey, presentation, map[int]string{1: "teacher"}, nonce)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.False(t, isValid, "Verify should reject other disclosed messages")
+
+	isValid, err = Verify(publicParams, result.VerificationKey, verifierKey, presentation, revealed, []byte("other"))
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.False(t, isValid, "Verify should reject another nonce")
+}
+
+// TestSimulate tests that the designated verifier can produce indistinguishable presentations for
+// messages that were never signed, so presentations do not convince third parties.
+func TestSimulate(t *testing.T) {
+	result, err := keygen.KeyGen(3)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	publicParams := result.PublicParameters
+	messages := []string{"alice", "student", "2026"}
+	signature, err := sign.Sign(publicParams, result.SigningKey, messages)
+	assert.NoError(t, err, "Sign should not return an error")
+	secretKey, verifierKey, err := KeyGen(publicParams)
+	assert.NoError(t, err, "KeyGen should not return an error")
+
+	genuine, err := Present(publicParams, verifierKey, signature, messages, nil, []byte("first"))
+	assert.NoError(t, err, "Present should not return an error")
+
+	forged := map[int]string{0: "mallory", 1: "admin"}
+	simulated, err := Simulate(publicParams, secretKey, genuine.Proof, forged, []byte("second"))
+	assert.NoError(t, err, "Simulate should not return an error")
+
+	isValid, err := Verify(publicParams, result.VerificationKey, verifierKey, simulated, forged, []byte("second"))
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.True(t, isValid, "Verify should accept a simulated presentation")
+}
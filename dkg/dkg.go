@@ -0,0 +1,484 @@
+package dkg
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aniagut/msc-bbs-plus-plus/models"
+	"github.com/aniagut/msc-bbs-plus-plus/network"
+	"github.com/aniagut/msc-bbs-plus-plus/shamir"
+	"github.com/aniagut/msc-bbs-plus-plus/threshold"
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	e "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+// The protocol is the distributed key generation of Gennaro, Jarecki, Krawczyk and Rabin:
+//
+//  1. Every party deals a random secret with Pedersen VSS in G2 (commitments g₂^{a_k}·h₂^{b_k}),
+//     parties complain about invalid shares and dealers answer by revealing them. Dealers with
+//     t or more complaints or an invalid answer are disqualified; the rest form QUAL.
+//  2. Every dealer in QUAL publishes Feldman commitments g₂^{a_k}. A party that receives a share
+//     inconsistent with them complains with its share; the dealer's polynomial is then
+//     reconstructed from the shares of all parties.
+//
+// The signing key x = Σ_{i ∈ QUAL} a_{i,0} is never known to a single party.
+
+// pedersenDomain is the domain separation tag for the second Pedersen generator h₂.
+const pedersenDomain = "BBS++-DKG-PEDERSEN-GENERATOR-V1"
+
+// Protocol rounds.
+const (
+	roundCommitments = iota + 1
+	roundShares
+	roundComplaints
+	roundAnswers
+	roundFeldman
+	roundFeldmanComplaints
+	roundReconstruction
+)
+
+// Result is the output of the key generation for one party.
+type Result struct {
+	// Share is the party's share of the signing key x.
+	Share threshold.KeyShare
+	// VerificationShares holds g₂^{x_j} for every party j.
+	VerificationShares map[int]*e.G2
+	// VerificationKey is the combined verification key X₂ = g₂^x.
+	VerificationKey models.VerificationKey
+	// Qualified lists the dealers whose secrets make up x.
+	Qualified []int
+}
+
+// Participant is one party of the distributed key generation.
+type Participant struct {
+	id, n, t int
+	g2, h2   *e.G2
+}
+
+// dealtShare is a pair of Pedersen VSS shares (f(j), f'(j)).
+type dealtShare struct {
+	S      *e.Scalar
+	SPrime *e.Scalar
+}
+
+type commitmentsMsg struct{ Commitments []*e.G2 }
+type complaintsMsg struct{ Dealers []int }
+type answersMsg struct{ Shares map[int]dealtShare }
+type feldmanMsg struct{ Commitments []*e.G2 }
+type feldmanComplaintsMsg struct{ Shares map[int]dealtShare }
+type reconstructionMsg struct{ Shares map[int]dealtShare }
+
+// NewParticipant creates party id (1..n) of a key generation with threshold t.
+func NewParticipant(publicParams models.PublicParameters, id, n, t int) (*Participant, error) {
+	if t < 1 || t > n {
+		return nil, errors.New("threshold must satisfy 1 <= t <= n")
+	}
+	if id < 1 || id > n {
+		return nil, errors.New("party identifier must be in 1..n")
+	}
+	return &Participant{
+		id: id,
+		n:  n,
+		t:  t,
+		g2: publicParams.G2,
+		h2: utils.HashToG2([]byte("h2"), pedersenDomain),
+	}, nil
+}
+
+// Run executes the key generation over the given transport.
+//
+// Returns:
+//   - Result: The party's key share and the public key material.
+//   - error: An error if the protocol cannot complete.
+func (p *Participant) Run(transport network.Transport) (Result, error) {
+	others := p.n - 1
+
+	// Phase 1, dealing: random polynomials f (secret) and f' (blinding) of degree t-1
+	secret, err := utils.RandomScalar()
+	if err != nil {
+		return Result{}, err
+	}
+	f, err := shamir.RandomPolynomial(&secret, p.t-1)
+	if err != nil {
+		return Result{}, err
+	}
+	blinding, err := utils.RandomScalar()
+	if err != nil {
+		return Result{}, err
+	}
+	fPrime, err := shamir.RandomPolynomial(&blinding, p.t-1)
+	if err != nil {
+		return Result{}, err
+	}
+	pedersen := make([]*e.G2, p.t)
+	for k := range pedersen {
+		pedersen[k] = p.commit(f[k], fPrime[k])
+	}
+	if err := transport.Broadcast(roundCommitments, commitmentsMsg{Commitments: pedersen}); err != nil {
+		return Result{}, err
+	}
+	dealt := make(map[int]dealtShare, p.n)
+	for j := 1; j <= p.n; j++ {
+		share := dealtShare{S: f.Evaluate(j), SPrime: fPrime.Evaluate(j)}
+		dealt[j] = share
+		if j == p.id {
+			continue
+		}
+		if err := transport.Send(j, roundShares, share); err != nil {
+			return Result{}, err
+		}
+	}
+
+	// Phase 1, verification: collect commitments and shares, complain about invalid shares
+	commitments := map[int][]*e.G2{p.id: pedersen}
+	messages, err := receive(transport, roundCommitments, others)
+	if err != nil {
+		return Result{}, err
+	}
+	for _, msg := range messages {
+		if c, ok := msg.Payload.(commitmentsMsg); ok && msg.Broadcast && len(c.Commitments) == p.t {
+			commitments[msg.From] = c.Commitments
+		}
+	}
+	received := map[int]dealtShare{p.id: dealt[p.id]}
+	messages, err = receive(transport, roundShares, others)
+	if err != nil {
+		return Result{}, err
+	}
+	for _, msg := range messages {
+		if s, ok := msg.Payload.(dealtShare); ok && !msg.Broadcast && s.S != nil && s.SPrime != nil {
+			received[msg.From] = s
+		}
+	}
+	var complaints []int
+	for i := 1; i <= p.n; i++ {
+		c, ok := commitments[i]
+		if !ok {
+			continue
+		}
+		if s, ok := received[i]; !ok || !p.verifyPedersen(c, p.id, s) {
+			complaints = append(complaints, i)
+		}
+	}
+	if err := transport.Broadcast(roundComplaints, complaintsMsg{Dealers: complaints}); err != nil {
+		return Result{}, err
+	}
+
+	// Phase 1, complaints: answer complaints against us by revealing the disputed shares
+	complaintsAgainst := make(map[int][]int)
+	for _, i := range complaints {
+		complaintsAgainst[i] = append(complaintsAgainst[i], p.id)
+	}
+	messages, err = receive(transport, roundComplaints, others)
+	if err != nil {
+		return Result{}, err
+	}
+	for _, msg := range messages {
+		if c, ok := msg.Payload.(complaintsMsg); ok && msg.Broadcast {
+			for _, i := range uniqueInts(c.Dealers) {
+				complaintsAgainst[i] = append(complaintsAgainst[i], msg.From)
+			}
+		}
+	}
+	answer := make(map[int]dealtShare)
+	for _, j := range complaintsAgainst[p.id] {
+		answer[j] = dealt[j]
+	}
+	if err := transport.Broadcast(roundAnswers, answersMsg{Shares: answer}); err != nil {
+		return Result{}, err
+	}
+	answers := map[int]map[int]dealtShare{p.id: answer}
+	messages, err = receive(transport, roundAnswers, others)
+	if err != nil {
+		return Result{}, err
+	}
+	for _, msg := range messages {
+		if a, ok := msg.Payload.(answersMsg); ok && msg.Broadcast {
+			answers[msg.From] = a.Shares
+		}
+	}
+
+	// Phase 1, disqualification: every honest party computes the same QUAL
+	var qualified []int
+	for i := 1; i <= p.n; i++ {
+		c, ok := commitments[i]
+		if !ok || len(complaintsAgainst[i]) >= p.t {
+			continue
+		}
+		valid := true
+		for _, j := range complaintsAgainst[i] {
+			s, ok := answers[i][j]
+			if !ok || s.S == nil || s.SPrime == nil || !p.verifyPedersen(c, j, s) {
+				valid = false
+				break
+			}
+			if j == p.id {
+				received[i] = s
+			}
+		}
+		if valid {
+			qualified = append(qualified, i)
+		}
+	}
+	if len(qualified) < p.t {
+		return Result{}, errors.New("too few qualified dealers")
+	}
+
+	// Phase 2: publish Feldman commitments g₂^{a_k}
+	feldman := make([]*e.G2, p.t)
+	for k := range feldman {
+		feldman[k] = new(e.G2)
+		feldman[k].ScalarMult(f[k], p.g2)
+	}
+	if err := transport.Broadcast(roundFeldman, feldmanMsg{Commitments: feldman}); err != nil {
+		return Result{}, err
+	}
+	feldmanCommitments := map[int][]*e.G2{p.id: feldman}
+	messages, err = receive(transport, roundFeldman, others)
+	if err != nil {
+		return Result{}, err
+	}
+	for _, msg := range messages {
+		if c, ok := msg.Payload.(feldmanMsg); ok && msg.Broadcast && len(c.Commitments) == p.t {
+			feldmanCommitments[msg.From] = c.Commitments
+		}
+	}
+
+	// Phase 2, complaints: reveal our share from every dealer whose Feldman commitments it contradicts
+	ownComplaints := make(map[int]dealtShare)
+	for _, i := range qualified {
+		c, ok := feldmanCommitments[i]
+		if !ok || !p.verifyFeldman(c, p.id, received[i].S) {
+			ownComplaints[i] = received[i]
+		}
+	}
+	if err := transport.Broadcast(roundFeldmanComplaints, feldmanComplaintsMsg{Shares: ownComplaints}); err != nil {
+		return Result{}, err
+	}
+	accused := make(map[int]bool)
+	for i := range ownComplaints {
+		accused[i] = true
+	}
+	messages, err = receive(transport, roundFeldmanComplaints, others)
+	if err != nil {
+		return Result{}, err
+	}
+	for _, msg := range messages {
+		c, ok := msg.Payload.(feldmanComplaintsMsg)
+		if !ok || !msg.Broadcast {
+			continue
+		}
+		for i, s := range c.Shares {
+			// A complaint is valid if the share matches the Pedersen commitments but not the Feldman ones
+			if !contains(qualified, i) || s.S == nil || s.SPrime == nil || !p.verifyPedersen(commitments[i], msg.From, s) {
+				continue
+			}
+			if fc, ok := feldmanCommitments[i]; !ok || !p.verifyFeldman(fc, msg.From, s.S) {
+				accused[i] = true
+			}
+		}
+	}
+	for _, i := range qualified {
+		if _, ok := feldmanCommitments[i]; !ok {
+			accused[i] = true
+		}
+	}
+
+	// Phase 2, reconstruction: recover g₂^{f_i(j)} of accused dealers from everyone's shares
+	reveal := make(map[int]dealtShare)
+	for i := range accused {
+		reveal[i] = received[i]
+	}
+	if err := transport.Broadcast(roundReconstruction, reconstructionMsg{Shares: reveal}); err != nil {
+		return Result{}, err
+	}
+	messages, err = receive(transport, roundReconstruction, others)
+	if err != nil {
+		return Result{}, err
+	}
+	reconstructed := make(map[int]map[int]*e.Scalar)
+	for i := range accused {
+		shares := []shamir.Share{{Index: p.id, Value: received[i].S}}
+		for _, msg := range messages {
+			r, ok := msg.Payload.(reconstructionMsg)
+			if !ok || !msg.Broadcast {
+				continue
+			}
+			if s, ok := r.Shares[i]; ok && s.S != nil && s.SPrime != nil && p.verifyPedersen(commitments[i], msg.From, s) {
+				shares = append(shares, shamir.Share{Index: msg.From, Value: s.S})
+			}
+		}
+		if len(shares) < p.t {
+			return Result{}, errors.New("too few shares to reconstruct a misbehaving dealer")
+		}
+		evaluations, err := interpolateAll(shares[:p.t], p.n)
+		if err != nil {
+			return Result{}, err
+		}
+		reconstructed[i] = evaluations
+	}
+
+	// Output: key share, verification shares and the combined verification key
+	x := new(e.Scalar)
+	for _, i := range qualified {
+		if evaluations, ok := reconstructed[i]; ok {
+			x.Add(x, evaluations[p.id])
+		} else {
+			x.Add(x, received[i].S)
+		}
+	}
+	verificationShares := make(map[int]*e.G2, p.n)
+	for j := 0; j <= p.n; j++ {
+		sum := new(e.G2)
+		sum.SetIdentity()
+		for _, i := range qualified {
+			term := new(e.G2)
+			if evaluations, ok := reconstructed[i]; ok {
+				term.ScalarMult(evaluations[j], p.g2)
+			} else {
+				term = evaluateInExponent(feldmanCommitments[i], j)
+			}
+			sum.Add(sum, term)
+		}
+		verificationShares[j] = sum
+	}
+	vk := verificationShares[0]
+	delete(verificationShares, 0)
+
+	return Result{
+		Share:              threshold.KeyShare{Index: p.id, X: x},
+		VerificationShares: verificationShares,
+		VerificationKey:    models.VerificationKey{X2: vk},
+		Qualified:          qualified,
+	}, nil
+}
+
+// commit computes the Pedersen commitment g₂^a · h₂^b.
+func (p *Participant) commit(a, b *e.Scalar) *e.G2 {
+	ga := new(e.G2)
+	ga.ScalarMult(a, p.g2)
+	hb := new(e.G2)
+	hb.ScalarMult(b, p.h2)
+	ga.Add(ga, hb)
+	return ga
+}
+
+// verifyPedersen checks g₂^{s} · h₂^{s'} = ∏_k C_k^{j^k}.
+func (p *Participant) verifyPedersen(commitments []*e.G2, j int, share dealtShare) bool {
+	return p.commit(share.S, share.SPrime).IsEqual(evaluateInExponent(commitments, j))
+}
+
+// verifyFeldman checks g₂^{s} = ∏_k A_k^{j^k}.
+func (p *Participant) verifyFeldman(commitments []*e.G2, j int, s *e.Scalar) bool {
+	gs := new(e.G2)
+	gs.ScalarMult(s, p.g2)
+	return gs.IsEqual(evaluateInExponent(commitments, j))
+}
+
+// evaluateInExponent computes ∏_k C_k^{j^k}, the commitment to f(j).
+func evaluateInExponent(commitments []*e.G2, j int) *e.G2 {
+	js := new(e.Scalar)
+	js.SetUint64(uint64(j))
+	power := new(e.Scalar)
+	power.SetOne()
+	result := new(e.G2)
+	result.SetIdentity()
+	term := new(e.G2)
+	for _, c := range commitments {
+		term.ScalarMult(power, c)
+		result.Add(result, term)
+		power.Mul(power, js)
+	}
+	return result
+}
+
+// interpolateAll evaluates the polynomial through the given t shares at 0, 1, ..., n.
+func interpolateAll(shares []shamir.Share, n int) (map[int]*e.Scalar, error) {
+	evaluations := make(map[int]*e.Scalar, n+1)
+	for x := 0; x <= n; x++ {
+		value, err := shamir.Interpolate(shares, x)
+		if err != nil {
+			return nil, err
+		}
+		evaluations[x] = value
+	}
+	return evaluations, nil
+}
+
+// receive collects the messages of a round, tolerating parties that do not respond.
+func receive(transport network.Transport, round int, count int) ([]network.Message, error) {
+	messages, err := transport.Receive(round, count)
+	if err != nil && !errors.Is(err, network.ErrTimeout) {
+		return nil, err
+	}
+	// Keep only the first message of each sender
+	seen := make(map[int]bool, len(messages))
+	unique := messages[:0]
+	for _, msg := range messages {
+		if !seen[msg.From] {
+			seen[msg.From] = true
+			unique = append(unique, msg)
+		}
+	}
+	return unique, nil
+}
+
+// uniqueInts returns the distinct values of a slice.
+func uniqueInts(values []int) []int {
+	seen := make(map[int]bool, len(values))
+	var unique []int
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
+
+// contains reports whether a sorted slice contains v.
+func contains(sorted []int, v int) bool {
+	k := sort.SearchInts(sorted, v)
+	return k < len(sorted) && sorted[k] == v
+}
+
+// RunLocal runs the key generation between the given participants on an in-process simulated network.
+//
+// Returns:
+//   - map[int]Result: The result of every participant, indexed by party identifier.
+//   - error: The first error reported by a participant.
+func RunLocal(participants []*Participant, timeout time.Duration) (map[int]Result, error) {
+	parties := make([]int, len(participants))
+	for k, participant := range participants {
+		parties[k] = participant.id
+	}
+	net := network.NewMemory(parties, timeout)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var firstErr error
+	results := make(map[int]Result, len(participants))
+	for _, participant := range participants {
+		wg.Add(1)
+		go func(participant *Participant) {
+			defer wg.Done()
+			result, err := participant.Run(net.Endpoint(participant.id))
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			results[participant.id] = result
+		}(participant)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return results, nil
+}
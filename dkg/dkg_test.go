@@ -0,0 +1,222 @@
+package dkg
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aniagut/msc-bbs-plus-plus/keygen"
+	"github.com/aniagut/msc-bbs-plus-plus/models"
+	"github.com/aniagut/msc-bbs-plus-plus/network"
+	"github.com/aniagut/msc-bbs-plus-plus/shamir"
+	"github.com/aniagut/msc-bbs-plus-plus/threshold"
+	"github.com/aniagut/msc-bbs-plus-plus/verify"
+	e "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/stretchr/testify/assert"
+)
+
+// newParticipants creates n participants with threshold t.
+func newParticipants(t *testing.T, publicParams models.PublicParameters, n, threshold int) []*Participant {
+	participants := make([]*Participant, n)
+	for i := range participants {
+		p, err := NewParticipant(publicParams, i+1, n, threshold)
+		assert.NoError(t, err, "NewParticipant should not return an error")
+		participants[i] = p
+	}
+	return participants
+}
+
+// tamperedTransport lets a test rewrite the messages a party sends, simulating a misbehaving party.
+type tamperedTransport struct {
+	network.Transport
+	tamper func(to, round int, payload interface{}) interface{}
+}
+
+func (t tamperedTransport) Send(to int, round int, payload interface{}) error {
+	return t.Transport.Send(to, round, t.tamper(to, round, payload))
+}
+
+func (t tamperedTransport) Broadcast(round int, payload interface{}) error {
+	return t.Transport.Broadcast(round, t.tamper(0, round, payload))
+}
+
+// runTampered runs the participants like RunLocal, with the messages of party id rewritten by tamper.
+// Broadcast messages are passed to tamper with to = 0.
+func runTampered(t *testing.T, participants []*Participant, id int, tamper func(to, round int, payload interface{}) interface{}) map[int]Result {
+	parties := make([]int, len(participants))
+	for k, participant := range participants {
+		parties[k] = participant.id
+	}
+	net := network.NewMemory(parties, time.Second)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	results := make(map[int]Result, len(participants))
+	for _, participant := range participants {
+		var transport network.Transport = net.Endpoint(participant.id)
+		if participant.id == id {
+			transport = tamperedTransport{Transport: transport, tamper: tamper}
+		}
+		wg.Add(1)
+		go func(participant *Participant, transport network.Transport) {
+			defer wg.Done()
+			result, err := participant.Run(transport)
+			assert.NoError(t, err, "Run should not return an error")
+			mu.Lock()
+			defer mu.Unlock()
+			results[participant.id] = result
+		}(participant, transport)
+	}
+	wg.Wait()
+	return results
+}
+
+// corruptShare returns a tamper function that replaces the shares dealt to the victims, or to every
+// party if none are given, with value(to).
+func corruptShare(value func(to int) uint64, victims ...int) func(to, round int, payload interface{}) interface{} {
+	return func(to, round int, payload interface{}) interface{} {
+		share, ok := payload.(dealtShare)
+		if !ok || round != roundShares {
+			return payload
+		}
+		if len(victims) > 0 && !contains(victims, to) {
+			return payload
+		}
+		corrupted := dealtShare{S: new(e.Scalar), SPrime: share.SPrime}
+		corrupted.S.SetUint64(value(to))
+		return corrupted
+	}
+}
+
+// checkConsistent checks that all results agree and that the shares match the verification key.
+func checkConsistent(t *testing.T, publicParams models.PublicParameters, results map[int]Result, threshold int) {
+	vk := results[1].VerificationKey.X2
+	for id, result := range results {
+		assert.True(t, vk.IsEqual(result.VerificationKey.X2), "Party %d should output the same verification key", id)
+		assert.Equal(t, results[1].Qualified, result.Qualified, "Party %d should compute the same QUAL", id)
+
+		// The verification share of each party matches its key share
+		expected := new(e.G2)
+		expected.ScalarMult(result.Share.X, publicParams.G2)
+		assert.True(t, expected.IsEqual(results[1].VerificationShares[id]), "Verification share of party %d should match its key share", id)
+	}
+
+	// Any t shares reconstruct x with g₂^x = X₂
+	var shares []shamir.Share
+	for id := 1; len(shares) < threshold; id++ {
+		shares = append(shares, shamir.Share{Index: id, Value: results[id].Share.X})
+	}
+	x, err := shamir.Combine(shares)
+	assert.NoError(t, err, "Combine should not return an error")
+	gx := new(e.G2)
+	gx.ScalarMult(x, publicParams.G2)
+	assert.True(t, gx.IsEqual(vk), "Shares should reconstruct the secret key of the verification key")
+}
+
+// TestDKGHonest tests a run in which all parties follow the protocol.
+func TestDKGHonest(t *testing.T) {
+	params, err := keygen.KeyGen(3)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	pp := params.PublicParameters
+
+	results, err := RunLocal(newParticipants(t, pp, 5, 3), time.Second)
+	assert.NoError(t, err, "RunLocal should not return an error")
+	assert.Len(t, results, 5, "Every party should produce a result")
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, results[1].Qualified, "All dealers should be qualified")
+	checkConsistent(t, pp, results, 3)
+}
+
+// TestDKGThresholdSigning tests that DKG shares can be used for threshold signing.
+func TestDKGThresholdSigning(t *testing.T) {
+	params, err := keygen.KeyGen(3)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	pp := params.PublicParameters
+
+	results, err := RunLocal(newParticipants(t, pp, 3, 2), time.Second)
+	assert.NoError(t, err, "RunLocal should not return an error")
+
+	triples, err := threshold.Preprocess(3, 2, 1)
+	assert.NoError(t, err, "Preprocess should not return an error")
+	nodes := []*threshold.Node{
+		threshold.NewNode(results[1].Share, 2, triples[1]),
+		threshold.NewNode(results[3].Share, 2, triples[3]),
+	}
+	messages := []string{"message1", "message2", "message3"}
+	vk := results[1].VerificationKey
+
+	signature, err := threshold.SignLocal(pp, vk, nodes, messages, 0, time.Second)
+	assert.NoError(t, err, "SignLocal should not return an error")
+	isValid, err := verify.Verify(pp, vk, messages, signature)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.True(t, isValid, "Signature should be valid under the DKG verification key")
+}
+
+// TestDKGAnsweredComplaint tests that a dealer who answers a complaint correctly stays qualified.
+func TestDKGAnsweredComplaint(t *testing.T) {
+	params, _ := keygen.KeyGen(3)
+	pp := params.PublicParameters
+	participants := newParticipants(t, pp, 5, 3)
+
+	results := runTampered(t, participants, 2, corruptShare(func(int) uint64 { return 7 }, 4))
+	assert.Contains(t, results[1].Qualified, 2, "Dealer should stay qualified after a valid answer")
+	checkConsistent(t, pp, results, 3)
+}
+
+// TestDKGDisqualifiedDealer tests that a dealer with t or more complaints is disqualified.
+func TestDKGDisqualifiedDealer(t *testing.T) {
+	params, _ := keygen.KeyGen(3)
+	pp := params.PublicParameters
+	participants := newParticipants(t, pp, 5, 3)
+
+	results := runTampered(t, participants, 2, corruptShare(func(to int) uint64 { return uint64(to) }))
+	assert.Equal(t, []int{1, 3, 4, 5}, results[1].Qualified, "Misbehaving dealer should be disqualified")
+	checkConsistent(t, pp, results, 3)
+}
+
+// TestDKGFeldmanComplaint tests that a dealer publishing wrong Feldman commitments is reconstructed.
+func TestDKGFeldmanComplaint(t *testing.T) {
+	params, _ := keygen.KeyGen(3)
+	pp := params.PublicParameters
+	participants := newParticipants(t, pp, 5, 3)
+
+	results := runTampered(t, participants, 3, func(to, round int, payload interface{}) interface{} {
+		msg, ok := payload.(feldmanMsg)
+		if !ok || round != roundFeldman {
+			return payload
+		}
+		commitments := append([]*e.G2(nil), msg.Commitments...)
+		commitments[0] = e.G2Generator()
+		return feldmanMsg{Commitments: commitments}
+	})
+	assert.Contains(t, results[1].Qualified, 3, "Dealer should stay in QUAL and be reconstructed")
+	checkConsistent(t, pp, results, 3)
+}
+
+// TestDKGSilentParty tests that a party that never responds is excluded from QUAL.
+func TestDKGSilentParty(t *testing.T) {
+	params, _ := keygen.KeyGen(3)
+	pp := params.PublicParameters
+	participants := newParticipants(t, pp, 4, 2)
+	net := network.NewMemory([]int{1, 2, 3, 4}, 100*time.Millisecond)
+
+	// Party 4 never runs
+	type output struct {
+		result Result
+		err    error
+	}
+	outputs := make(chan output, 3)
+	for _, participant := range participants[:3] {
+		go func(participant *Participant) {
+			result, err := participant.Run(net.Endpoint(participant.id))
+			outputs <- output{result, err}
+		}(participant)
+	}
+	results := make(map[int]Result)
+	for i := 0; i < 3; i++ {
+		out := <-outputs
+		assert.NoError(t, out.err, "Run should not return an error")
+		results[out.result.Share.Index] = out.result
+	}
+	assert.Equal(t, []int{1, 2, 3}, results[1].Qualified, "Silent party should not be qualified")
+	checkConsistent(t, pp, results, 2)
+}
@@ -0,0 +1,222 @@
+package escrow
+
+import (
+	"errors"
+
+	"github.com/aniagut/msc-bbs-plus-plus/models"
+	"github.com/aniagut/msc-bbs-plus-plus/proof"
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	e "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+// presentationDomain separates escrow presentation challenges from other hashes.
+const presentationDomain = "BBS++-ESCROW-PRESENTATION-V1"
+
+// ErrNoMatch is returned by Identify when the decrypted value matches none of the candidates.
+var ErrNoMatch = errors.New("ciphertext matches none of the candidates")
+
+// PrivateKey is the escrow authority's ElGamal decryption key y.
+type PrivateKey struct {
+	Y *e.Scalar
+}
+
+// PublicKey is the escrow authority's ElGamal encryption key Y = g1^y.
+type PublicKey struct {
+	Y *e.G1
+}
+
+// Ciphertext is an ElGamal encryption (E1, E2) = (g1^k, g1^m · Y^k) of a hidden message m.
+// The message is encrypted in the exponent: decryption yields g1^m, not m, and since m is the
+// hash of the message string, the authority learns the message only by comparing g1^m with
+// candidate values, as Identify does.
+type Ciphertext struct {
+	E1 *e.G1
+	E2 *e.G1
+}
+
+// Presentation is a proof of knowledge of a signature together with an encryption of one hidden
+// message to the escrow authority and a proof that the ciphertext contains the signed value.
+type Presentation struct {
+	Proof      models.Proof
+	Ciphertext Ciphertext
+	KHat       *e.Scalar
+}
+
+// KeyGen generates the key pair of the escrow authority.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//
+// Returns:
+//   - PrivateKey: The decryption key kept by the authority.
+//   - PublicKey: The encryption key used by holders.
+//   - error: An error if the key generation fails.
+func KeyGen(publicParams models.PublicParameters) (PrivateKey, PublicKey, error) {
+	y, err := utils.RandomScalar()
+	if err != nil {
+		return PrivateKey{}, PublicKey{}, err
+	}
+	Y := new(e.G1)
+	Y.ScalarMult(&y, publicParams.G1)
+	return PrivateKey{Y: &y}, PublicKey{Y: Y}, nil
+}
+
+// Present generates a presentation that encrypts the hidden message at escrowIndex to the authority.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - escrowKey: The public key of the escrow authority.
+//   - signature: The signature on the messages.
+//   - m: The full message vector.
+//   - disclosed: The indexes of the messages revealed to the verifier.
+//   - escrowIndex: The index of the hidden message to be encrypted.
+//   - label: The conditions under which the authority may decrypt; bound to the ciphertext.
+//   - nonce: A verifier-supplied nonce bound to the presentation.
+//
+// Returns:
+//   - Presentation: The generated presentation.
+//   - error: An error if the proof cannot be generated.
+func Present(publicParams models.PublicParameters, escrowKey PublicKey, signature models.Signature, m []string, disclosed []int, escrowIndex int, label string, nonce []byte) (Presentation, error) {
+	if escrowKey.Y == nil {
+		return Presentation{}, errors.New("escrow key is missing")
+	}
+
+	// Step 1: Commitment phase of the signature proof
+	prover, err := proof.NewProver(publicParams, signature, m, disclosed, nil)
+	if err != nil {
+		return Presentation{}, err
+	}
+	message, mTilde := prover.Message(escrowIndex), prover.Blinding(escrowIndex)
+	if message == nil {
+		return Presentation{}, errors.New("escrowed message must be hidden")
+	}
+
+	// Step 2: Encrypt g1^m as (g1^k, g1^m · Y^k)
+	random, err := utils.RandomScalars(2)
+	if err != nil {
+		return Presentation{}, err
+	}
+	k, kTilde := random[0], random[1]
+	ciphertext := Ciphertext{E1: new(e.G1)}
+	ciphertext.E1.ScalarMult(k, publicParams.G1)
+	ciphertext.E2 = utils.MultiExpG1([]*e.G1{publicParams.G1, escrowKey.Y}, []*e.Scalar{message, k})
+
+	// Step 3: Commit W1 = g1^{k̃} and W2 = g1^{m̃} · Y^{k̃} with the blinding of the signed message
+	w1 := new(e.G1)
+	w1.ScalarMult(kTilde, publicParams.G1)
+	w2 := utils.MultiExpG1([]*e.G1{publicParams.G1, escrowKey.Y}, []*e.Scalar{mTilde, kTilde})
+
+	// Step 4: Derive the challenge and compute the responses
+	challenge := presentationChallenge(prover.Bytes(), escrowKey, ciphertext, w1, w2, escrowIndex, label, nonce)
+	return Presentation{
+		Proof:      prover.Respond(challenge),
+		Ciphertext: ciphertext,
+		KHat:       proof.Response(kTilde, challenge, k),
+	}, nil
+}
+
+// Verify checks an escrow presentation.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - verificationKey: The verification key of the issuer.
+//   - escrowKey: The public key of the escrow authority.
+//   - presentation: The presentation to be verified.
+//   - revealed: The disclosed messages, indexed by their position in the message vector.
+//   - escrowIndex: The index of the encrypted message.
+//   - label: The conditions the ciphertext was bound to.
+//   - nonce: The nonce the presentation was bound to.
+//
+// Returns:
+//   - boolean: True if the presentation is valid, false otherwise.
+//   - error: An error if the presentation is malformed.
+func Verify(publicParams models.PublicParameters, verificationKey models.VerificationKey, escrowKey PublicKey, presentation Presentation, revealed map[int]string, escrowIndex int, label string, nonce []byte) (bool, error) {
+	if escrowKey.Y == nil {
+		return false, errors.New("escrow key is missing")
+	}
+	mHat, ok := presentation.Proof.MHat[escrowIndex]
+	if !ok || mHat == nil {
+		return false, errors.New("escrowed message is not hidden in the presentation")
+	}
+	ciphertext := presentation.Ciphertext
+	if ciphertext.E1 == nil || ciphertext.E2 == nil || presentation.KHat == nil {
+		return false, errors.New("presentation is missing components")
+	}
+
+	// Step 1: Recompute the signature proof transcript and check the pairing equation
+	transcript, err := proof.TranscriptBytes(publicParams, presentation.Proof, revealed)
+	if err != nil {
+		return false, err
+	}
+	if !proof.CheckPairing(publicParams, verificationKey, presentation.Proof) {
+		return false, nil
+	}
+	challenge := presentation.Proof.Challenge
+	negC := utils.Neg(challenge)
+
+	// Step 2: Recompute W1 = g1^{k̂} · E1^{-c} and W2 = g1^{m̂} · Y^{k̂} · E2^{-c}
+	w1 := utils.MultiExpG1([]*e.G1{publicParams.G1, ciphertext.E1}, []*e.Scalar{presentation.KHat, negC})
+	w2 := utils.MultiExpG1([]*e.G1{publicParams.G1, escrowKey.Y, ciphertext.E2}, []*e.Scalar{mHat, presentation.KHat, negC})
+
+	// Step 3: Check the challenge
+	expected := presentationChallenge(transcript, escrowKey, ciphertext, w1, w2, escrowIndex, label, nonce)
+	return expected.IsEqual(challenge) == 1, nil
+}
+
+// Decrypt recovers g1^m = E2 · E1^{-y} from a ciphertext. It does not recover the message
+// itself; use Identify to find the message among candidate values.
+func Decrypt(privateKey PrivateKey, ciphertext Ciphertext) (*e.G1, error) {
+	if privateKey.Y == nil {
+		return nil, errors.New("escrow key is missing")
+	}
+	if ciphertext.E1 == nil || ciphertext.E2 == nil {
+		return nil, errors.New("ciphertext is missing components")
+	}
+	mask := new(e.G1)
+	mask.ScalarMult(utils.Neg(privateKey.Y), ciphertext.E1)
+	plaintext := new(e.G1)
+	plaintext.Add(ciphertext.E2, mask)
+	return plaintext, nil
+}
+
+// Identify decrypts a ciphertext and returns the candidate message it contains. Since messages
+// are encrypted in the exponent, this is the authority's only way to recover a message: it
+// compares the plaintext against known values, such as the user IDs of its register, and
+// cannot deanonymize a holder whose value is not among the candidates.
+//
+// Returns:
+//   - string: The matching candidate.
+//   - error: ErrNoMatch if no candidate matches, or an error if the ciphertext is malformed.
+func Identify(publicParams models.PublicParameters, privateKey PrivateKey, ciphertext Ciphertext, candidates []string) (string, error) {
+	plaintext, err := Decrypt(privateKey, ciphertext)
+	if err != nil {
+		return "", err
+	}
+	candidate := new(e.G1)
+	for _, message := range candidates {
+		candidate.ScalarMult(utils.MessageToScalar(message), publicParams.G1)
+		if candidate.IsEqual(plaintext) {
+			return message, nil
+		}
+	}
+	return "", ErrNoMatch
+}
+
+// presentationChallenge hashes the signature proof transcript, the ciphertext and its commitments,
+// the escrowed position, the label and the nonce.
+func presentationChallenge(transcript []byte, escrowKey PublicKey, ciphertext Ciphertext, w1, w2 *e.G1, escrowIndex int, label string, nonce []byte) *e.Scalar {
+	index := new(e.Scalar)
+	index.SetUint64(uint64(escrowIndex))
+	return utils.HashToScalar(
+		[]byte(presentationDomain),
+		transcript,
+		escrowKey.Y.BytesCompressed(),
+		ciphertext.E1.BytesCompressed(),
+		ciphertext.E2.BytesCompressed(),
+		w1.BytesCompressed(),
+		w2.BytesCompressed(),
+		utils.ScalarToBytes(index),
+		[]byte(label),
+		nonce,
+	)
+}
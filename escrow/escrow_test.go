@@ -0,0 +1,81 @@
+package escrow
+
+import (
+	"testing"
+
+	"github.com/aniagut/msc-bbs-plus-plus/keygen"
+	"github.com/aniagut/msc-bbs-plus-plus/sign"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPresentVerifyIdentify tests the full flow: the holder encrypts the hidden user ID, the verifier
+// checks the presentation and the authority identifies the user.
+func TestPresentVerifyIdentify(t *testing.T) {
+	result, err := keygen.KeyGen(3)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	publicParams := result.PublicParameters
+	messages := []string{"user-42", "student", "2026"}
+	signature, err := sign.Sign(publicParams, result.SigningKey, messages)
+	assert.NoError(t, err, "Sign should not return an error")
+	privateKey, escrowKey, err := KeyGen(publicParams)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	nonce := []byte("nonce")
+	revealed := map[int]string{1: "student"}
+
+	presentation, err := Present(publicParams, escrowKey, signature, messages, []int{1}, 0, "court order", nonce)
+	assert.NoError(t, err, "Present should not return an error")
+
+	isValid, err := Verify(publicParams, result.VerificationKey, escrowKey, presentation, revealed, 0, "court order", nonce)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.True(t, isValid, "Verify should accept a valid presentation")
+
+	isValid, err = Verify(publicParams, result.VerificationKey, escrowKey, presentation, revealed, 0, "other label", nonce)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.False(t, isValid, "Verify should reject a presentation with another label")
+
+	isValid, err = Verify(publicParams, result.VerificationKey, escrowKey, presentation, revealed, 2, "court order", nonce)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.False(t, isValid, "Verify should reject a presentation claimed for another position")
+
+	userID, err := Identify(publicParams, privateKey, presentation.Ciphertext, []string{"user-1", "user-42", "user-7"})
+	assert.NoError(t, err, "Identify should not return an error")
+	assert.Equal(t, "user-42", userID, "Identify should find the encrypted user ID")
+
+	_, err = Identify(publicParams, privateKey, presentation.Ciphertext, []string{"user-1"})
+	assert.ErrorIs(t, err, ErrNoMatch, "Identify should report an unknown user")
+}
+
+// TestWrongCiphertext tests that a ciphertext of another value cannot be attached to a presentation.
+func TestWrongCiphertext(t *testing.T) {
+	result, err := keygen.KeyGen(2)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	publicParams := result.PublicParameters
+	_, escrowKey, err := KeyGen(publicParams)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	nonce := []byte("nonce")
+
+	sign1, err := sign.Sign(publicParams, result.SigningKey, []string{"user-1", "x"})
+	assert.NoError(t, err, "Sign should not return an error")
+	sign2, err := sign.Sign(publicParams, result.SigningKey, []string{"user-2", "x"})
+	assert.NoError(t, err, "Sign should not return an error")
+
+	presentation, err := Present(publicParams, escrowKey, sign1, []string{"user-1", "x"}, nil, 0, "", nonce)
+	assert.NoError(t, err, "Present should not return an error")
+	other, err := Present(publicParams, escrowKey, sign2, []string{"user-2", "x"}, nil, 0, "", nonce)
+	assert.NoError(t, err, "Present should not return an error")
+
+	presentation.Ciphertext = other.Ciphertext
+	isValid, err := Verify(publicParams, result.VerificationKey, escrowKey, presentation, map[int]string{}, 0, "", nonce)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.False(t, isValid, "Verify should reject a swapped ciphertext")
+
+	_, err = Present(publicParams, escrowKey, sign1, []string{"user-1", "x"}, []int{0}, 0, "", nonce)
+	assert.Error(t, err, "Present should refuse to escrow a disclosed message")
+
+	_, err = Present(publicParams, PublicKey{}, sign1, []string{"user-1", "x"}, nil, 0, "", nonce)
+	assert.Error(t, err, "Present should reject a missing escrow key")
+	_, err = Verify(publicParams, result.VerificationKey, PublicKey{}, presentation, map[int]string{}, 0, "", nonce)
+	assert.Error(t, err, "Verify should reject a missing escrow key")
+	_, err = Decrypt(PrivateKey{}, presentation.Ciphertext)
+	assert.Error(t, err, "Decrypt should reject a missing escrow key")
+}
@@ -0,0 +1,105 @@
+package experiments
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aniagut/msc-bbs-plus-plus/compressed"
+	"github.com/aniagut/msc-bbs-plus-plus/keygen"
+	"github.com/aniagut/msc-bbs-plus-plus/proof"
+	"github.com/aniagut/msc-bbs-plus-plus/sign"
+)
+
+// MeasureCompressedProofByHiddenCount compares the size and the prove and verify times of standard
+// and compressed proofs for credentials with different numbers of hidden messages and saves the
+// results to a file.
+func MeasureCompressedProofByHiddenCount() {
+	// Open the results file for writing
+	file, err := os.Create("experiments/results/compressed_proof_results_hidden_count.txt")
+	if err != nil {
+		fmt.Printf("Error creating results file: %v\n", err)
+		return
+	}
+	defer file.Close()
+	// Write the header to the file
+	_, err = file.WriteString("HiddenCount,StandardSize,CompressedSize,AverageStandardProveTime,AverageCompressedProveTime,AverageStandardVerifyTime,AverageCompressedVerifyTime\n")
+	if err != nil {
+		fmt.Printf("Error writing to results file: %v\n", err)
+		return
+	}
+
+	// Define the numbers of hidden messages to test
+	hiddenCounts := []int{1, 10, 50, 100, 200, 500}
+	nonce := []byte("nonce")
+	for _, l := range hiddenCounts {
+		// Generate keys and sign a message vector of length l
+		keyGenResult, err := keygen.KeyGen(l)
+		if err != nil {
+			fmt.Printf("Error generating keys for l=%d: %v\n", l, err)
+			return
+		}
+		publicParams := keyGenResult.PublicParameters
+		messageVector := make([]string, l)
+		for i := range messageVector {
+			messageVector[i] = fmt.Sprintf("message%d", i)
+		}
+		signature, err := sign.Sign(publicParams, keyGenResult.SigningKey, messageVector)
+		if err != nil {
+			fmt.Printf("Error during Sign for l=%d: %v\n", l, err)
+			return
+		}
+		revealed := map[int]string{}
+
+		var standardProve, compressedProve, standardVerify, compressedVerify time.Duration
+		var compressedSize int
+		// Run both provers and verifiers 10 times and measure the total time
+		for i := 0; i < 10; i++ {
+			start := time.Now()
+			standardProof, err := proof.Prove(publicParams, signature, messageVector, nil, nonce)
+			standardProve += time.Since(start)
+			if err != nil {
+				fmt.Printf("Error during Prove for l=%d: %v\n", l, err)
+				return
+			}
+
+			start = time.Now()
+			compressedProof, err := compressed.Prove(publicParams, signature, messageVector, nil, nonce)
+			compressedProve += time.Since(start)
+			if err != nil {
+				fmt.Printf("Error during compressed Prove for l=%d: %v\n", l, err)
+				return
+			}
+			compressedSize = compressedProof.Size()
+
+			start = time.Now()
+			_, err = proof.Verify(publicParams, keyGenResult.VerificationKey, standardProof, revealed, nonce)
+			standardVerify += time.Since(start)
+			if err != nil {
+				fmt.Printf("Error during Verify for l=%d: %v\n", l, err)
+				return
+			}
+
+			start = time.Now()
+			_, err = compressed.Verify(publicParams, keyGenResult.VerificationKey, compressedProof, revealed, nonce)
+			compressedVerify += time.Since(start)
+			if err != nil {
+				fmt.Printf("Error during compressed Verify for l=%d: %v\n", l, err)
+				return
+			}
+		}
+
+		// Print the results
+		standardSize := compressed.StandardSize(l)
+		fmt.Printf("Hidden=%d: size %dB vs %dB, Prove %v vs %v, Verify %v vs %v\n", l, standardSize, compressedSize,
+			standardProve/10, compressedProve/10, standardVerify/10, compressedVerify/10)
+
+		// Write the results to the file
+		_, err = file.WriteString(fmt.Sprintf("%d,%d,%d,%v,%v,%v,%v\n", l, standardSize, compressedSize,
+			standardProve/10, compressedProve/10, standardVerify/10, compressedVerify/10))
+		if err != nil {
+			fmt.Printf("Error writing to results file: %v\n", err)
+			return
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package experiments
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aniagut/msc-bbs-plus-plus/groupsig"
+)
+
+// MeasureRevocationCheckTimeByListLength measures the time taken to check a group signature
+// against revocation lists of different lengths, with a linear scan and with the revocation
+// index, and saves the results to a file.
+func MeasureRevocationCheckTimeByListLength() {
+	// Open the results file for writing
+	file, err := os.Create("experiments/results/groupsig_revocation_time_results_list_length.txt")
+	if err != nil {
+		fmt.Printf("Error creating results file: %v\n", err)
+		return
+	}
+	defer file.Close()
+	// Write the header to the file
+	_, err = file.WriteString("RevocationListLength,AverageScanTime,IndexBuildTime,AverageIndexCheckTime\n")
+	if err != nil {
+		fmt.Printf("Error writing to results file: %v\n", err)
+		return
+	}
+
+	// Set up a group and an unrevoked signer, so that the scan has to visit every token
+	publicKey, issuerKey, _, err := groupsig.Setup()
+	if err != nil {
+		fmt.Printf("Error during Setup: %v\n", err)
+		return
+	}
+	manager := groupsig.NewManager(publicKey, issuerKey)
+	signer, err := manager.Join()
+	if err != nil {
+		fmt.Printf("Error during Join: %v\n", err)
+		return
+	}
+	signature, err := groupsig.SignRevocable(publicKey, signer, "message")
+	if err != nil {
+		fmt.Printf("Error during SignRevocable: %v\n", err)
+		return
+	}
+
+	// Define the lengths of the revocation list to test
+	listLengths := []int{10, 100, 1000, 10000}
+	for _, length := range listLengths {
+		// Admit and revoke members until the list has the required length
+		for len(manager.RevocationList()) < length {
+			member, err := manager.Join()
+			if err != nil {
+				fmt.Printf("Error during Join: %v\n", err)
+				return
+			}
+			if _, err := manager.Revoke(member.Index); err != nil {
+				fmt.Printf("Error during Revoke: %v\n", err)
+				return
+			}
+		}
+		tokens := manager.RevocationList()
+
+		var scanTime time.Duration
+		// Run the linear scan 10 times and measure the total time
+		for i := 0; i < 10; i++ {
+			start := time.Now()
+			if _, _, err := groupsig.CheckRevocation(signature, tokens); err != nil {
+				fmt.Printf("Error during CheckRevocation for list length=%d: %v\n", length, err)
+				return
+			}
+			scanTime += time.Since(start)
+		}
+
+		// Build the index once and run the check 10 times
+		start := time.Now()
+		index, err := groupsig.NewRevocationIndex(tokens)
+		if err != nil {
+			fmt.Printf("Error during NewRevocationIndex for list length=%d: %v\n", length, err)
+			return
+		}
+		buildTime := time.Since(start)
+		var indexTime time.Duration
+		for i := 0; i < 10; i++ {
+			start := time.Now()
+			if _, _, err := index.Check(signature); err != nil {
+				fmt.Printf("Error during index Check for list length=%d: %v\n", length, err)
+				return
+			}
+			indexTime += time.Since(start)
+		}
+
+		// Print the results
+		fmt.Printf("Revocation list length=%d: scan %v, index build %v, index check %v\n", length, scanTime/10, buildTime, indexTime/10)
+
+		// Write the results to the file
+		_, err = file.WriteString(fmt.Sprintf("%d,%v,%v,%v\n", length, scanTime/10, buildTime, indexTime/10))
+		if err != nil {
+			fmt.Printf("Error writing to results file: %v\n", err)
+			return
+		}
+	}
+}
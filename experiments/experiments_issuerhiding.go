@@ -0,0 +1,95 @@
+package experiments
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aniagut/msc-bbs-plus-plus/issuerhiding"
+	"github.com/aniagut/msc-bbs-plus-plus/keygen"
+	"github.com/aniagut/msc-bbs-plus-plus/models"
+	"github.com/aniagut/msc-bbs-plus-plus/sign"
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	e "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+// MeasureIssuerHidingTimeByPolicySize measures the time taken to create and verify issuer-hiding
+// presentations for policies with different numbers of accepted issuers and saves the results to a file.
+func MeasureIssuerHidingTimeByPolicySize() {
+	// Open the results file for writing
+	file, err := os.Create("experiments/results/issuer_hiding_time_results_policy_size.txt")
+	if err != nil {
+		fmt.Printf("Error creating results file: %v\n", err)
+		return
+	}
+	defer file.Close()
+	// Write the header to the file
+	_, err = file.WriteString("PolicySize,AveragePresentTime,AverageVerifyTime\n")
+	if err != nil {
+		fmt.Printf("Error writing to results file: %v\n", err)
+		return
+	}
+
+	// Generate the shared public parameters and the holder's issuer
+	keyGenResult, err := keygen.KeyGen(5)
+	if err != nil {
+		fmt.Printf("Error generating keys: %v\n", err)
+		return
+	}
+	publicParams := keyGenResult.PublicParameters
+	messageVector := []string{"message1", "message2", "message3", "message4", "message5"}
+	signature, err := sign.Sign(publicParams, keyGenResult.SigningKey, messageVector)
+	if err != nil {
+		fmt.Printf("Error during Sign: %v\n", err)
+		return
+	}
+	revealed := map[int]string{0: messageVector[0]}
+	nonce := []byte("nonce")
+
+	// Define the policy sizes to test
+	policySizes := []int{1, 2, 5, 10, 20, 50, 100}
+	policy := []models.VerificationKey{keyGenResult.VerificationKey}
+	for _, size := range policySizes {
+		// Add other issuers until the policy has the required size
+		for len(policy) < size {
+			x, err := utils.RandomScalar()
+			if err != nil {
+				fmt.Printf("Error generating issuer key: %v\n", err)
+				return
+			}
+			X2 := new(e.G2)
+			X2.ScalarMult(&x, publicParams.G2)
+			policy = append(policy, models.VerificationKey{X2: X2})
+		}
+
+		var presentTime, verifyTime time.Duration
+		// Run Present and Verify 10 times and measure the total time
+		for i := 0; i < 10; i++ {
+			start := time.Now()
+			presentation, err := issuerhiding.Present(publicParams, policy, keyGenResult.VerificationKey, signature, messageVector, []int{0}, nonce)
+			presentTime += time.Since(start)
+			if err != nil {
+				fmt.Printf("Error during Present for policy size=%d: %v\n", size, err)
+				return
+			}
+
+			start = time.Now()
+			_, err = issuerhiding.Verify(publicParams, policy, presentation, revealed, nonce)
+			verifyTime += time.Since(start)
+			if err != nil {
+				fmt.Printf("Error during Verify for policy size=%d: %v\n", size, err)
+				return
+			}
+		}
+
+		// Print the results
+		fmt.Printf("Policy size=%d: Present %v, Verify %v\n", size, presentTime/10, verifyTime/10)
+
+		// Write the results to the file
+		_, err = file.WriteString(fmt.Sprintf("%d,%v,%v\n", size, presentTime/10, verifyTime/10))
+		if err != nil {
+			fmt.Printf("Error writing to results file: %v\n", err)
+			return
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package experiments
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aniagut/msc-bbs-plus-plus/keygen"
+	"github.com/aniagut/msc-bbs-plus-plus/kvac"
+	"github.com/aniagut/msc-bbs-plus-plus/proof"
+	"github.com/aniagut/msc-bbs-plus-plus/verify"
+)
+
+// MeasureKeyedVerifyTimeByMessageVectorLength compares keyed (MAC) verification with pairing-based
+// verification, for both signatures and presentations, for different message vector lengths
+// and saves the results to a file.
+func MeasureKeyedVerifyTimeByMessageVectorLength() {
+	// Open the results file for writing
+	file, err := os.Create("experiments/results/kvac_verify_time_results_msg_vector_length.txt")
+	if err != nil {
+		fmt.Printf("Error creating results file: %v\n", err)
+		return
+	}
+	defer file.Close()
+	// Write the header to the file
+	_, err = file.WriteString("MessageVectorLength,AverageKeyedVerifyTime,AveragePairingVerifyTime,AverageKeyedPresentationTime,AveragePairingPresentationTime\n")
+	if err != nil {
+		fmt.Printf("Error writing to results file: %v\n", err)
+		return
+	}
+
+	// Define the sizes of the messages vector to test
+	messageVectorLengths := []int{1, 2, 5, 10, 20, 50, 100, 200, 500, 1000}
+
+	// Iterate over each message vector length
+	for _, length := range messageVectorLengths {
+		// Generate keys for the system
+		keyGenResult, err := keygen.KeyGen(length)
+		if err != nil {
+			fmt.Printf("Error generating keys: %v\n", err)
+			return
+		}
+		publicParams, signingKey, verificationKey := keyGenResult.PublicParameters, keyGenResult.SigningKey, keyGenResult.VerificationKey
+
+		// Create a message vector of the length `length` and issue a MAC on it
+		messageVector := make([]string, length)
+		for i := 0; i < length; i++ {
+			messageVector[i] = fmt.Sprintf("message%d", i+1)
+		}
+		mac, err := kvac.Issue(publicParams, signingKey, messageVector)
+		if err != nil {
+			fmt.Printf("Error during Issue for message vector length=%d: %v\n", length, err)
+			return
+		}
+
+		// Create a presentation disclosing the first message
+		nonce := []byte("nonce")
+		revealed := map[int]string{0: messageVector[0]}
+		presentation, err := proof.Prove(publicParams, mac, messageVector, []int{0}, nonce)
+		if err != nil {
+			fmt.Printf("Error during Prove for message vector length=%d: %v\n", length, err)
+			return
+		}
+
+		var keyedTime, pairingTime, keyedPresentationTime, pairingPresentationTime time.Duration
+		// Run each verification 10 times and measure the total time
+		for i := 0; i < 10; i++ {
+			start := time.Now()
+			_, err := kvac.Verify(publicParams, signingKey, messageVector, mac)
+			keyedTime += time.Since(start)
+			if err != nil {
+				fmt.Printf("Error during keyed Verify for message vector length=%d: %v\n", length, err)
+				return
+			}
+
+			start = time.Now()
+			_, err = verify.Verify(publicParams, verificationKey, messageVector, mac)
+			pairingTime += time.Since(start)
+			if err != nil {
+				fmt.Printf("Error during Verify for message vector length=%d: %v\n", length, err)
+				return
+			}
+
+			start = time.Now()
+			_, err = kvac.VerifyPresentation(publicParams, signingKey, presentation, revealed, nonce)
+			keyedPresentationTime += time.Since(start)
+			if err != nil {
+				fmt.Printf("Error during keyed presentation verification for message vector length=%d: %v\n", length, err)
+				return
+			}
+
+			start = time.Now()
+			_, err = proof.Verify(publicParams, verificationKey, presentation, revealed, nonce)
+			pairingPresentationTime += time.Since(start)
+			if err != nil {
+				fmt.Printf("Error during presentation verification for message vector length=%d: %v\n", length, err)
+				return
+			}
+		}
+
+		// Print the results
+		fmt.Printf("Average keyed/pairing Verify time for message vector length=%d: %v / %v\n", length, keyedTime/10, pairingTime/10)
+		fmt.Printf("Average keyed/pairing presentation verification time for message vector length=%d: %v / %v\n", length, keyedPresentationTime/10, pairingPresentationTime/10)
+
+		// Write the results to the file
+		_, err = file.WriteString(fmt.Sprintf("%d,%v,%v,%v,%v\n", length, keyedTime/10, pairingTime/10, keyedPresentationTime/10, pairingPresentationTime/10))
+		if err != nil {
+			fmt.Printf("Error writing to results file: %v\n", err)
+			return
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package experiments
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aniagut/msc-bbs-plus-plus/scheme"
+)
+
+// MeasureSchemesByMessageVectorLength measures Sign, Verify, Present and VerifyPresentation of the
+// BBS++ and Pointcheval–Sanders schemes side by side for different message vector lengths
+// and saves the results to a file.
+func MeasureSchemesByMessageVectorLength() {
+	// Open the results file for writing
+	file, err := os.Create("experiments/results/scheme_time_results_msg_vector_length.txt")
+	if err != nil {
+		fmt.Printf("Error creating results file: %v\n", err)
+		return
+	}
+	defer file.Close()
+	// Write the header to the file
+	_, err = file.WriteString("Scheme,MessageVectorLength,AverageSignTime,AverageVerifyTime,AveragePresentTime,AverageVerifyPresentationTime\n")
+	if err != nil {
+		fmt.Printf("Error writing to results file: %v\n", err)
+		return
+	}
+
+	// Define the sizes of the messages vector to test
+	messageVectorLengths := []int{1, 2, 5, 10, 20, 50, 100, 200}
+	nonce := []byte("nonce")
+
+	for _, name := range []string{scheme.NameBBS, scheme.NamePS} {
+		s, err := scheme.New(name)
+		if err != nil {
+			fmt.Printf("Error creating scheme %s: %v\n", name, err)
+			return
+		}
+		// Iterate over each message vector length
+		for _, length := range messageVectorLengths {
+			signingKey, publicKey, err := s.KeyGen(length)
+			if err != nil {
+				fmt.Printf("Error generating keys: %v\n", err)
+				return
+			}
+
+			// Create a message vector of the length `length`, disclosing the first message
+			messageVector := make([]string, length)
+			for i := 0; i < length; i++ {
+				messageVector[i] = fmt.Sprintf("message%d", i+1)
+			}
+			revealed := map[int]string{0: messageVector[0]}
+
+			var signTime, verifyTime, presentTime, verifyPresentationTime time.Duration
+			// Run every operation 10 times and measure the total time
+			for i := 0; i < 10; i++ {
+				start := time.Now()
+				signature, err := s.Sign(signingKey, messageVector)
+				signTime += time.Since(start)
+				if err != nil {
+					fmt.Printf("Error during %s Sign for message vector length=%d: %v\n", name, length, err)
+					return
+				}
+
+				start = time.Now()
+				_, err = s.Verify(publicKey, messageVector, signature)
+				verifyTime += time.Since(start)
+				if err != nil {
+					fmt.Printf("Error during %s Verify for message vector length=%d: %v\n", name, length, err)
+					return
+				}
+
+				start = time.Now()
+				presentation, err := s.Present(publicKey, signature, messageVector, []int{0}, nonce)
+				presentTime += time.Since(start)
+				if err != nil {
+					fmt.Printf("Error during %s Present for message vector length=%d: %v\n", name, length, err)
+					return
+				}
+
+				start = time.Now()
+				_, err = s.VerifyPresentation(publicKey, presentation, revealed, nonce)
+				verifyPresentationTime += time.Since(start)
+				if err != nil {
+					fmt.Printf("Error during %s VerifyPresentation for message vector length=%d: %v\n", name, length, err)
+					return
+				}
+			}
+
+			// Print the results
+			fmt.Printf("%s, message vector length=%d: Sign %v, Verify %v, Present %v, VerifyPresentation %v\n",
+				name, length, signTime/10, verifyTime/10, presentTime/10, verifyPresentationTime/10)
+
+			// Write the results to the file
+			_, err = file.WriteString(fmt.Sprintf("%s,%d,%v,%v,%v,%v\n", name, length, signTime/10, verifyTime/10, presentTime/10, verifyPresentationTime/10))
+			if err != nil {
+				fmt.Printf("Error writing to results file: %v\n", err)
+				return
+			}
+		}
+	}
+}
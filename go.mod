@@ -1,6 +1,6 @@
 module github.com/aniagut/msc-bbs-plus-plus
 
-go 1.18
+go 1.22.0
 
 require (
 	github.com/cloudflare/circl v1.6.1
@@ -0,0 +1,687 @@
+package groupsig
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+
+	"github.com/aniagut/msc-bbs-plus-plus/proof"
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	e "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+// Domain separation tags of the hashes used by the group signature scheme.
+const (
+	signDomain      = "BBS04-GROUP-SIGNATURE-V1"
+	openDomain      = "BBS04-OPENING-V1"
+	generatorDomain = "BBS04-GENERATOR-V1"
+)
+
+// Sizes of the serialized group elements and structures.
+const (
+	g1Size = 48
+	g2Size = 96
+
+	// SignatureSize is the length of a serialized group signature.
+	SignatureSize = 3*g1Size + 7*e.ScalarSize
+	// RevocableSignatureSize is the length of a serialized signature produced by SignRevocable.
+	RevocableSignatureSize = SignatureSize + 2*g1Size
+	// PublicKeySize is the length of a serialized group public key.
+	PublicKeySize = 3*g1Size + g2Size
+	// MemberKeySize is the length of a serialized member key.
+	MemberKeySize = 4 + g1Size + 2*e.ScalarSize
+	// OpeningSize is the length of a serialized opening.
+	OpeningSize = 4 + g1Size + 3*e.ScalarSize
+)
+
+// ErrUnknownSigner is returned by Open when the signer is not in the registry.
+var ErrUnknownSigner = errors.New("signer is not a registered group member")
+
+// h0 is the generator that binds the second member secret y into the member key.
+var h0 = utils.HashToG1([]byte("h0"), generatorDomain)
+
+// PublicKey is the group public key (h, u, v, w) with u^{ξ1} = v^{ξ2} = h and w = g2^γ.
+// The generators g1 and g2 are the fixed generators of BLS12-381.
+type PublicKey struct {
+	H *e.G1
+	U *e.G1
+	V *e.G1
+	W *e.G2
+}
+
+// IssuerKey is the secret γ held by the group manager to admit members.
+type IssuerKey struct {
+	Gamma *e.Scalar
+}
+
+// OpeningKey is the secret (ξ1, ξ2) held by the opening authority to trace signatures.
+type OpeningKey struct {
+	Xi1 *e.Scalar
+	Xi2 *e.Scalar
+}
+
+// MemberKey is the private key (A, x, y) of a group member with A^{γ+x} = g1·h0^y. As in BBS04,
+// the group manager generates the key in Join, so it knows x and y as well as the member does.
+// The secret y is published as the member's revocation token when the member is revoked.
+type MemberKey struct {
+	Index int
+	A     *e.G1
+	X     *e.Scalar
+	Y     *e.Scalar
+}
+
+// Registration is the entry the group manager records for every member.
+type Registration struct {
+	Index int
+	A     *e.G1
+}
+
+// Signature is a BBS04 group signature: the linear encryption (T1, T2, T3) of the signer's A
+// and a signature of knowledge (c, sα, sβ, sx, sy, sδ1, sδ2) of the member key inside it.
+// Signatures produced by SignRevocable also carry the revocation tag K = B^y on a random base B.
+type Signature struct {
+	T1        *e.G1
+	T2        *e.G1
+	T3        *e.G1
+	Challenge *e.Scalar
+	SAlpha    *e.Scalar
+	SBeta     *e.Scalar
+	SX        *e.Scalar
+	SY        *e.Scalar
+	SDelta1   *e.Scalar
+	SDelta2   *e.Scalar
+	B         *e.G1
+	K         *e.G1
+}
+
+// Opening names the signer of a signature together with a proof that the opening authority
+// decrypted the signature correctly, so that anyone can check the claim with Judge.
+type Opening struct {
+	Index     int
+	A         *e.G1
+	Challenge *e.Scalar
+	SXi1      *e.Scalar
+	SXi2      *e.Scalar
+}
+
+// Manager is the group manager: it admits members and keeps the registry used for opening.
+type Manager struct {
+	publicKey PublicKey
+	issuerKey IssuerKey
+
+	mu       sync.Mutex
+	registry []Registration
+	tokens   map[int]*e.Scalar
+	revoked  []RevocationToken
+}
+
+// Setup generates the group public key, the issuing key of the group manager and the
+// opening key of the opening authority.
+//
+// Returns:
+//   - PublicKey: The group public key.
+//   - IssuerKey: The secret key used to admit members.
+//   - OpeningKey: The secret key used to open signatures.
+//   - error: An error if the key generation fails.
+func Setup() (PublicKey, IssuerKey, OpeningKey, error) {
+	// 1. Select random h ∈ G1 and ξ1, ξ2, γ ∈ Zp*
+	h, err := utils.RandomG1Element()
+	if err != nil {
+		return PublicKey{}, IssuerKey{}, OpeningKey{}, err
+	}
+	scalars, err := utils.RandomScalars(3)
+	if err != nil {
+		return PublicKey{}, IssuerKey{}, OpeningKey{}, err
+	}
+	xi1, xi2, gamma := scalars[0], scalars[1], scalars[2]
+
+	// 2. Compute u = h^{1/ξ1}, v = h^{1/ξ2} and w = g2^γ
+	inverse := new(e.Scalar)
+	u := new(e.G1)
+	inverse.Inv(xi1)
+	u.ScalarMult(inverse, &h)
+	v := new(e.G1)
+	inverse.Inv(xi2)
+	v.ScalarMult(inverse, &h)
+	w := new(e.G2)
+	w.ScalarMult(gamma, e.G2Generator())
+
+	return PublicKey{H: &h, U: u, V: v, W: w}, IssuerKey{Gamma: gamma}, OpeningKey{Xi1: xi1, Xi2: xi2}, nil
+}
+
+// NewManager creates a group manager with an empty registry.
+func NewManager(publicKey PublicKey, issuerKey IssuerKey) *Manager {
+	return &Manager{publicKey: publicKey, issuerKey: issuerKey, tokens: make(map[int]*e.Scalar)}
+}
+
+// Join admits a new member: it issues a member key (A, x, y) with A = (g1·h0^y)^{1/(γ+x)} and
+// records the member's A in the registry and y as the member's revocation token.
+//
+// Returns:
+//   - MemberKey: The private key of the new member.
+//   - error: An error if the key generation fails.
+func (m *Manager) Join() (MemberKey, error) {
+	exponent := new(e.Scalar)
+	var x e.Scalar
+	for {
+		var err error
+		x, err = utils.RandomScalar()
+		if err != nil {
+			return MemberKey{}, err
+		}
+		exponent.Add(m.issuerKey.Gamma, &x)
+		if exponent.IsZero() == 0 {
+			break
+		}
+	}
+	y, err := utils.RandomScalar()
+	if err != nil {
+		return MemberKey{}, err
+	}
+	exponent.Inv(exponent)
+	A := new(e.G1)
+	A.ScalarMult(&y, h0)
+	A.Add(A, e.G1Generator())
+	A.ScalarMult(exponent, A)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	index := len(m.registry) + 1
+	m.registry = append(m.registry, Registration{Index: index, A: A})
+	m.tokens[index] = &y
+	return MemberKey{Index: index, A: A, X: &x, Y: &y}, nil
+}
+
+// Registry returns a copy of the registered members.
+func (m *Manager) Registry() []Registration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Registration(nil), m.registry...)
+}
+
+// VerifyMemberKey checks that a member key is well formed: e(A, w·g2^x) = e(g1·h0^y, g2).
+func VerifyMemberKey(publicKey PublicKey, memberKey MemberKey) bool {
+	if memberKey.A == nil || memberKey.X == nil || memberKey.Y == nil {
+		return false
+	}
+	q := new(e.G2)
+	q.ScalarMult(memberKey.X, e.G2Generator())
+	q.Add(q, publicKey.W)
+	p := new(e.G1)
+	p.ScalarMult(memberKey.Y, h0)
+	p.Add(p, e.G1Generator())
+	return e.Pair(memberKey.A, q).IsEqual(e.Pair(p, e.G2Generator()))
+}
+
+// Sign produces an anonymous group signature on a message.
+//
+// Parameters:
+//   - publicKey: The group public key.
+//   - memberKey: The private key of the signing member.
+//   - message: The message to be signed.
+//
+// Returns:
+//   - Signature: The group signature.
+//   - error: An error if the signing process fails.
+func Sign(publicKey PublicKey, memberKey MemberKey, message string) (Signature, error) {
+	return sign(publicKey, memberKey, message, nil)
+}
+
+// sign produces a group signature and, when base is not nil, the revocation tag K = base^y
+// together with a proof that it uses the same y as the member key.
+func sign(publicKey PublicKey, memberKey MemberKey, message string, base *e.G1) (Signature, error) {
+	if memberKey.A == nil || memberKey.X == nil || memberKey.Y == nil {
+		return Signature{}, errors.New("member key is missing components")
+	}
+
+	// Step 1: Select random α, β and blinding factors rα, rβ, rx, ry, rδ1, rδ2
+	scalars, err := utils.RandomScalars(8)
+	if err != nil {
+		return Signature{}, err
+	}
+	alpha, beta := scalars[0], scalars[1]
+	rAlpha, rBeta, rX, rY, rDelta1, rDelta2 := scalars[2], scalars[3], scalars[4], scalars[5], scalars[6], scalars[7]
+
+	// Step 2: Encrypt A as T1 = u^α, T2 = v^β, T3 = A·h^{α+β}
+	T1 := new(e.G1)
+	T1.ScalarMult(alpha, publicKey.U)
+	T2 := new(e.G1)
+	T2.ScalarMult(beta, publicKey.V)
+	sum := new(e.Scalar)
+	sum.Add(alpha, beta)
+	T3 := new(e.G1)
+	T3.ScalarMult(sum, publicKey.H)
+	T3.Add(T3, memberKey.A)
+
+	// Step 3: Compute δ1 = x·α and δ2 = x·β
+	delta1 := new(e.Scalar)
+	delta1.Mul(memberKey.X, alpha)
+	delta2 := new(e.Scalar)
+	delta2.Mul(memberKey.X, beta)
+
+	// Step 4: Compute the commitments R1..R5 of the signature of knowledge
+	R1 := new(e.G1)
+	R1.ScalarMult(rAlpha, publicKey.U)
+	R2 := new(e.G1)
+	R2.ScalarMult(rBeta, publicKey.V)
+	R3 := commitPairing(publicKey, T3, rX, rY, rAlpha, rBeta, rDelta1, rDelta2, nil)
+	R4 := linearCommitment(T1, rX, publicKey.U, utils.Neg(rDelta1))
+	R5 := linearCommitment(T2, rX, publicKey.V, utils.Neg(rDelta2))
+	signature := Signature{T1: T1, T2: T2, T3: T3}
+	var R6 *e.G1
+	if base != nil {
+		signature.B = base
+		signature.K = new(e.G1)
+		signature.K.ScalarMult(memberKey.Y, base)
+		R6 = new(e.G1)
+		R6.ScalarMult(rY, base)
+	}
+
+	// Step 5: Compute the challenge and the responses s = r + c·secret
+	signature.Challenge = challenge(message, signature, R1, R2, R3, R4, R5, R6)
+	signature.SAlpha = proof.Response(rAlpha, signature.Challenge, alpha)
+	signature.SBeta = proof.Response(rBeta, signature.Challenge, beta)
+	signature.SX = proof.Response(rX, signature.Challenge, memberKey.X)
+	signature.SY = proof.Response(rY, signature.Challenge, memberKey.Y)
+	signature.SDelta1 = proof.Response(rDelta1, signature.Challenge, delta1)
+	signature.SDelta2 = proof.Response(rDelta2, signature.Challenge, delta2)
+	return signature, nil
+}
+
+// Verify checks a group signature on a message against the group public key.
+//
+// Parameters:
+//   - publicKey: The group public key.
+//   - message: The signed message.
+//   - signature: The group signature.
+//
+// Returns:
+//   - boolean: True if the signature is valid, false otherwise.
+//   - error: An error if the signature is malformed.
+func Verify(publicKey PublicKey, message string, signature Signature) (bool, error) {
+	if !signature.complete() {
+		return false, errors.New("signature is missing components")
+	}
+	if (signature.B == nil) != (signature.K == nil) {
+		return false, errors.New("signature has an incomplete revocation tag")
+	}
+	c := signature.Challenge
+
+	// Step 1: Recompute R1 = u^{sα}·T1^{-c} and R2 = v^{sβ}·T2^{-c}
+	R1 := linearCommitment(publicKey.U, signature.SAlpha, signature.T1, utils.Neg(c))
+	R2 := linearCommitment(publicKey.V, signature.SBeta, signature.T2, utils.Neg(c))
+
+	// Step 2: Recompute R3 = e(T3, g2)^{sx}·e(h, w)^{-sα-sβ}·e(h, g2)^{-sδ1-sδ2}·e(h0, g2)^{-sy}·(e(T3, w)/e(g1, g2))^c
+	R3 := commitPairing(publicKey, signature.T3, signature.SX, signature.SY, signature.SAlpha, signature.SBeta, signature.SDelta1, signature.SDelta2, c)
+
+	// Step 3: Recompute R4 = T1^{sx}·u^{-sδ1} and R5 = T2^{sx}·v^{-sδ2}
+	R4 := linearCommitment(signature.T1, signature.SX, publicKey.U, utils.Neg(signature.SDelta1))
+	R5 := linearCommitment(signature.T2, signature.SX, publicKey.V, utils.Neg(signature.SDelta2))
+
+	// Step 4: Recompute R6 = B^{sy}·K^{-c} for signatures with a revocation tag
+	var R6 *e.G1
+	if signature.B != nil {
+		if signature.B.IsIdentity() {
+			return false, nil
+		}
+		R6 = linearCommitment(signature.B, signature.SY, signature.K, utils.Neg(c))
+	}
+
+	// Step 5: Check the challenge
+	expected := challenge(message, signature, R1, R2, R3, R4, R5, R6)
+	return expected.IsEqual(c) == 1, nil
+}
+
+// Open traces a valid signature to its signer: it decrypts A = T3 / (T1^{ξ1}·T2^{ξ2}),
+// looks it up in the registry and proves that the decryption is correct.
+//
+// Parameters:
+//   - publicKey: The group public key.
+//   - openingKey: The secret key of the opening authority.
+//   - registry: The members registered by the group manager.
+//   - message: The signed message.
+//   - signature: The group signature.
+//
+// Returns:
+//   - Opening: The signer's identity and the proof of correct opening.
+//   - error: ErrUnknownSigner if the signer is not registered, or an error if the signature is invalid.
+func Open(publicKey PublicKey, openingKey OpeningKey, registry []Registration, message string, signature Signature) (Opening, error) {
+	isValid, err := Verify(publicKey, message, signature)
+	if err != nil {
+		return Opening{}, err
+	}
+	if !isValid {
+		return Opening{}, errors.New("cannot open an invalid signature")
+	}
+
+	// Step 1: Decrypt A = T3 - ξ1·T1 - ξ2·T2
+	mask := linearCommitment(signature.T1, openingKey.Xi1, signature.T2, openingKey.Xi2)
+	mask.Neg()
+	A := new(e.G1)
+	A.Add(signature.T3, mask)
+
+	// Step 2: Find the registered member
+	opening := Opening{Index: -1, A: A}
+	for _, registration := range registry {
+		if registration.A.IsEqual(A) {
+			opening.Index = registration.Index
+			break
+		}
+	}
+	if opening.Index < 0 {
+		return Opening{}, ErrUnknownSigner
+	}
+
+	// Step 3: Prove knowledge of ξ1, ξ2 with u^{ξ1} = h, v^{ξ2} = h and T1^{ξ1}·T2^{ξ2} = T3/A
+	blindings, err := utils.RandomScalars(2)
+	if err != nil {
+		return Opening{}, err
+	}
+	R1 := new(e.G1)
+	R1.ScalarMult(blindings[0], publicKey.U)
+	R2 := new(e.G1)
+	R2.ScalarMult(blindings[1], publicKey.V)
+	R3 := linearCommitment(signature.T1, blindings[0], signature.T2, blindings[1])
+	opening.Challenge = openingChallenge(message, signature, A, R1, R2, R3)
+	opening.SXi1 = proof.Response(blindings[0], opening.Challenge, openingKey.Xi1)
+	opening.SXi2 = proof.Response(blindings[1], opening.Challenge, openingKey.Xi2)
+	return opening, nil
+}
+
+// Judge checks an opening: the signature must be valid, the opening proof must show that the
+// signature decrypts to A and A must be registered to the claimed member.
+//
+// Parameters:
+//   - publicKey: The group public key.
+//   - registry: The members registered by the group manager.
+//   - message: The signed message.
+//   - signature: The group signature.
+//   - opening: The opening produced by the opening authority.
+//
+// Returns:
+//   - boolean: True if the opening is correct, false otherwise.
+//   - error: An error if the signature or the opening is malformed.
+func Judge(publicKey PublicKey, registry []Registration, message string, signature Signature, opening Opening) (bool, error) {
+	if opening.A == nil || opening.Challenge == nil || opening.SXi1 == nil || opening.SXi2 == nil {
+		return false, errors.New("opening is missing components")
+	}
+	isValid, err := Verify(publicKey, message, signature)
+	if err != nil || !isValid {
+		return false, err
+	}
+
+	registered := false
+	for _, registration := range registry {
+		if registration.Index == opening.Index && registration.A.IsEqual(opening.A) {
+			registered = true
+			break
+		}
+	}
+	if !registered {
+		return false, nil
+	}
+
+	// Recompute R1 = u^{s1}·h^{-c}, R2 = v^{s2}·h^{-c} and R3 = T1^{s1}·T2^{s2}·(T3/A)^{-c}
+	c := opening.Challenge
+	R1 := linearCommitment(publicKey.U, opening.SXi1, publicKey.H, utils.Neg(c))
+	R2 := linearCommitment(publicKey.V, opening.SXi2, publicKey.H, utils.Neg(c))
+	mask := new(e.G1)
+	*mask = *opening.A
+	mask.Neg()
+	mask.Add(mask, signature.T3)
+	R3 := linearCommitment(signature.T1, opening.SXi1, signature.T2, opening.SXi2)
+	mask.ScalarMult(utils.Neg(c), mask)
+	R3.Add(R3, mask)
+
+	expected := openingChallenge(message, signature, opening.A, R1, R2, R3)
+	return expected.IsEqual(c) == 1, nil
+}
+
+// commitPairing computes e(T3, g2)^{a}·e(h, w)^{-b1-b2}·e(h, g2)^{-d1-d2}·e(h0, g2)^{-y}, multiplied
+// by (e(T3, w)/e(g1, g2))^c when c is not nil, as a single product of three pairings.
+func commitPairing(publicKey PublicKey, T3 *e.G1, a, y, b1, b2, d1, d2, c *e.Scalar) *e.Gt {
+	g2 := e.G2Generator()
+
+	// e(T3, g2^a·w^c)
+	q1 := new(e.G2)
+	q1.ScalarMult(a, g2)
+	// e(h, w^{-(b1+b2)}·g2^{-(d1+d2)})
+	b := new(e.Scalar)
+	b.Add(b1, b2)
+	d := new(e.Scalar)
+	d.Add(d1, d2)
+	q2 := new(e.G2)
+	q2.ScalarMult(utils.Neg(b), publicKey.W)
+	term := new(e.G2)
+	term.ScalarMult(utils.Neg(d), g2)
+	q2.Add(q2, term)
+	// e(h0^{-y}·g1^{-c}, g2)
+	p3 := new(e.G1)
+	p3.ScalarMult(utils.Neg(y), h0)
+
+	if c != nil {
+		term.ScalarMult(c, publicKey.W)
+		q1.Add(q1, term)
+		gc := new(e.G1)
+		gc.ScalarMult(utils.Neg(c), e.G1Generator())
+		p3.Add(p3, gc)
+	}
+	return e.ProdPair([]*e.G1{T3, publicKey.H, p3}, []*e.G2{q1, q2, g2}, []*e.Scalar{utils.One(), utils.One(), utils.One()})
+}
+
+// linearCommitment computes P^a·Q^b.
+func linearCommitment(P *e.G1, a *e.Scalar, Q *e.G1, b *e.Scalar) *e.G1 {
+	return utils.MultiExpG1([]*e.G1{P, Q}, []*e.Scalar{a, b})
+}
+
+// challenge computes the Fiat–Shamir challenge of a group signature.
+func challenge(message string, signature Signature, R1, R2 *e.G1, R3 *e.Gt, R4, R5, R6 *e.G1) *e.Scalar {
+	r3, _ := R3.MarshalBinary()
+	inputs := [][]byte{
+		[]byte(signDomain),
+		[]byte(message),
+		signature.T1.BytesCompressed(),
+		signature.T2.BytesCompressed(),
+		signature.T3.BytesCompressed(),
+		R1.BytesCompressed(),
+		R2.BytesCompressed(),
+		r3,
+		R4.BytesCompressed(),
+		R5.BytesCompressed(),
+	}
+	if R6 != nil {
+		inputs = append(inputs, signature.B.BytesCompressed(), signature.K.BytesCompressed(), R6.BytesCompressed())
+	}
+	return utils.HashToScalar(inputs...)
+}
+
+// openingChallenge computes the Fiat–Shamir challenge of an opening proof.
+func openingChallenge(message string, signature Signature, A, R1, R2, R3 *e.G1) *e.Scalar {
+	return utils.HashToScalar(
+		[]byte(openDomain),
+		[]byte(message),
+		signature.T1.BytesCompressed(),
+		signature.T2.BytesCompressed(),
+		signature.T3.BytesCompressed(),
+		A.BytesCompressed(),
+		R1.BytesCompressed(),
+		R2.BytesCompressed(),
+		R3.BytesCompressed(),
+	)
+}
+
+// complete reports whether all components of the signature are present.
+func (s Signature) complete() bool {
+	return s.T1 != nil && s.T2 != nil && s.T3 != nil && s.Challenge != nil &&
+		s.SAlpha != nil && s.SBeta != nil && s.SX != nil && s.SY != nil && s.SDelta1 != nil && s.SDelta2 != nil
+}
+
+// MarshalBinary serializes the signature as T1 || T2 || T3 || c || sα || sβ || sx || sy || sδ1 || sδ2,
+// followed by B || K for signatures with a revocation tag.
+func (s Signature) MarshalBinary() ([]byte, error) {
+	if !s.complete() || (s.B == nil) != (s.K == nil) {
+		return nil, errors.New("signature is missing components")
+	}
+	out := make([]byte, 0, RevocableSignatureSize)
+	for _, point := range []*e.G1{s.T1, s.T2, s.T3} {
+		out = append(out, point.BytesCompressed()...)
+	}
+	out, err := appendScalars(out, s.Challenge, s.SAlpha, s.SBeta, s.SX, s.SY, s.SDelta1, s.SDelta2)
+	if err != nil || s.B == nil {
+		return out, err
+	}
+	out = append(out, s.B.BytesCompressed()...)
+	return append(out, s.K.BytesCompressed()...), nil
+}
+
+// UnmarshalBinary parses a signature serialized with MarshalBinary.
+func (s *Signature) UnmarshalBinary(data []byte) error {
+	if len(data) != SignatureSize && len(data) != RevocableSignatureSize {
+		return errors.New("invalid signature length")
+	}
+	points, err := readG1(data, 3)
+	if err != nil {
+		return err
+	}
+	scalars, err := readScalars(data[3*g1Size:], 7)
+	if err != nil {
+		return err
+	}
+	*s = Signature{
+		T1: points[0], T2: points[1], T3: points[2],
+		Challenge: scalars[0], SAlpha: scalars[1], SBeta: scalars[2],
+		SX: scalars[3], SY: scalars[4], SDelta1: scalars[5], SDelta2: scalars[6],
+	}
+	if len(data) == RevocableSignatureSize {
+		tag, err := readG1(data[SignatureSize:], 2)
+		if err != nil {
+			return err
+		}
+		s.B, s.K = tag[0], tag[1]
+	}
+	return nil
+}
+
+// MarshalBinary serializes the public key as h || u || v || w.
+func (pk PublicKey) MarshalBinary() ([]byte, error) {
+	if pk.H == nil || pk.U == nil || pk.V == nil || pk.W == nil {
+		return nil, errors.New("public key is missing components")
+	}
+	out := make([]byte, 0, PublicKeySize)
+	for _, point := range []*e.G1{pk.H, pk.U, pk.V} {
+		out = append(out, point.BytesCompressed()...)
+	}
+	return append(out, pk.W.BytesCompressed()...), nil
+}
+
+// UnmarshalBinary parses a public key serialized with MarshalBinary.
+func (pk *PublicKey) UnmarshalBinary(data []byte) error {
+	if len(data) != PublicKeySize {
+		return errors.New("invalid public key length")
+	}
+	points, err := readG1(data, 3)
+	if err != nil {
+		return err
+	}
+	w := new(e.G2)
+	if err := w.SetBytes(data[3*g1Size:]); err != nil {
+		return errors.New("invalid G2 element")
+	}
+	*pk = PublicKey{H: points[0], U: points[1], V: points[2], W: w}
+	return nil
+}
+
+// MarshalBinary serializes the member key as index || A || x || y.
+func (mk MemberKey) MarshalBinary() ([]byte, error) {
+	if mk.A == nil || mk.X == nil || mk.Y == nil || mk.Index < 0 || int64(mk.Index) > 0xffffffff {
+		return nil, errors.New("member key cannot be serialized")
+	}
+	out := make([]byte, 4, MemberKeySize)
+	binary.BigEndian.PutUint32(out, uint32(mk.Index))
+	out = append(out, mk.A.BytesCompressed()...)
+	return appendScalars(out, mk.X, mk.Y)
+}
+
+// UnmarshalBinary parses a member key serialized with MarshalBinary.
+func (mk *MemberKey) UnmarshalBinary(data []byte) error {
+	if len(data) != MemberKeySize {
+		return errors.New("invalid member key length")
+	}
+	points, err := readG1(data[4:], 1)
+	if err != nil {
+		return err
+	}
+	scalars, err := readScalars(data[4+g1Size:], 2)
+	if err != nil {
+		return err
+	}
+	*mk = MemberKey{Index: int(binary.BigEndian.Uint32(data)), A: points[0], X: scalars[0], Y: scalars[1]}
+	return nil
+}
+
+// MarshalBinary serializes the opening as index || A || c || s1 || s2.
+func (o Opening) MarshalBinary() ([]byte, error) {
+	if o.A == nil || o.Challenge == nil || o.SXi1 == nil || o.SXi2 == nil || o.Index < 0 || int64(o.Index) > 0xffffffff {
+		return nil, errors.New("opening cannot be serialized")
+	}
+	out := make([]byte, 4, OpeningSize)
+	binary.BigEndian.PutUint32(out, uint32(o.Index))
+	out = append(out, o.A.BytesCompressed()...)
+	return appendScalars(out, o.Challenge, o.SXi1, o.SXi2)
+}
+
+// UnmarshalBinary parses an opening serialized with MarshalBinary.
+func (o *Opening) UnmarshalBinary(data []byte) error {
+	if len(data) != OpeningSize {
+		return errors.New("invalid opening length")
+	}
+	points, err := readG1(data[4:], 1)
+	if err != nil {
+		return err
+	}
+	scalars, err := readScalars(data[4+g1Size:], 3)
+	if err != nil {
+		return err
+	}
+	*o = Opening{
+		Index: int(binary.BigEndian.Uint32(data)), A: points[0],
+		Challenge: scalars[0], SXi1: scalars[1], SXi2: scalars[2],
+	}
+	return nil
+}
+
+// appendScalars appends the fixed-size encodings of the scalars to out.
+func appendScalars(out []byte, scalars ...*e.Scalar) ([]byte, error) {
+	for _, scalar := range scalars {
+		encoded, err := scalar.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, encoded...)
+	}
+	return out, nil
+}
+
+// readG1 parses count compressed G1 elements from the start of data.
+func readG1(data []byte, count int) ([]*e.G1, error) {
+	points := make([]*e.G1, count)
+	for i := range points {
+		points[i] = new(e.G1)
+		if err := points[i].SetBytes(data[i*g1Size : (i+1)*g1Size]); err != nil {
+			return nil, errors.New("invalid G1 element")
+		}
+	}
+	return points, nil
+}
+
+// readScalars parses count scalars from the start of data.
+func readScalars(data []byte, count int) ([]*e.Scalar, error) {
+	scalars := make([]*e.Scalar, count)
+	for i := range scalars {
+		scalars[i] = new(e.Scalar)
+		if err := scalars[i].UnmarshalBinary(data[i*e.ScalarSize : (i+1)*e.ScalarSize]); err != nil {
+			return nil, errors.New("invalid scalar")
+		}
+	}
+	return scalars, nil
+}
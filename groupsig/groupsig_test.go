@@ -0,0 +1,216 @@
+package groupsig
+
+import (
+	"testing"
+
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupGroup creates a group with the given number of members.
+func setupGroup(t *testing.T, members int) (PublicKey, OpeningKey, *Manager, []MemberKey) {
+	publicKey, issuerKey, openingKey, err := Setup()
+	assert.NoError(t, err, "Setup should not return an error")
+	manager := NewManager(publicKey, issuerKey)
+	keys := make([]MemberKey, members)
+	for i := range keys {
+		keys[i], err = manager.Join()
+		assert.NoError(t, err, "Join should not return an error")
+		assert.True(t, VerifyMemberKey(publicKey, keys[i]), "Join should issue a valid member key")
+	}
+	return publicKey, openingKey, manager, keys
+}
+
+// TestSignVerify tests that group signatures verify and are bound to the message.
+func TestSignVerify(t *testing.T) {
+	publicKey, _, _, keys := setupGroup(t, 2)
+
+	signature, err := Sign(publicKey, keys[0], "message")
+	assert.NoError(t, err, "Sign should not return an error")
+
+	isValid, err := Verify(publicKey, "message", signature)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.True(t, isValid, "Verify should accept a valid signature")
+
+	isValid, err = Verify(publicKey, "other message", signature)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.False(t, isValid, "Verify should reject a signature on another message")
+
+	other, err := Sign(publicKey, keys[0], "message")
+	assert.NoError(t, err, "Sign should not return an error")
+	assert.False(t, other.T3.IsEqual(signature.T3), "Signatures of one member should be unlinkable")
+}
+
+// TestForgedMemberKey tests that a key not issued by the manager cannot produce valid signatures.
+func TestForgedMemberKey(t *testing.T) {
+	publicKey, _, _, keys := setupGroup(t, 1)
+	forged := keys[0]
+	forged.X = utils.One()
+	assert.False(t, VerifyMemberKey(publicKey, forged), "VerifyMemberKey should reject a forged key")
+
+	signature, err := Sign(publicKey, forged, "message")
+	assert.NoError(t, err, "Sign should not return an error")
+	isValid, err := Verify(publicKey, "message", signature)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.False(t, isValid, "Verify should reject a signature under a forged key")
+}
+
+// TestOpenJudge tests that the opening authority traces signatures and the judge checks the opening.
+func TestOpenJudge(t *testing.T) {
+	publicKey, openingKey, manager, keys := setupGroup(t, 3)
+	registry := manager.Registry()
+
+	signature, err := Sign(publicKey, keys[1], "message")
+	assert.NoError(t, err, "Sign should not return an error")
+
+	opening, err := Open(publicKey, openingKey, registry, "message", signature)
+	assert.NoError(t, err, "Open should not return an error")
+	assert.Equal(t, keys[1].Index, opening.Index, "Open should identify the signer")
+
+	isValid, err := Judge(publicKey, registry, "message", signature, opening)
+	assert.NoError(t, err, "Judge should not return an error")
+	assert.True(t, isValid, "Judge should accept a correct opening")
+
+	framed := opening
+	framed.Index, framed.A = keys[0].Index, keys[0].A
+	isValid, err = Judge(publicKey, registry, "message", signature, framed)
+	assert.NoError(t, err, "Judge should not return an error")
+	assert.False(t, isValid, "Judge should reject an opening blaming another member")
+
+	_, err = Open(publicKey, openingKey, registry[:1], "message", signature)
+	assert.ErrorIs(t, err, ErrUnknownSigner, "Open should report an unregistered signer")
+}
+
+// TestSerialization tests that all structures survive a round trip through their binary encoding.
+func TestSerialization(t *testing.T) {
+	publicKey, openingKey, manager, keys := setupGroup(t, 1)
+	signature, err := Sign(publicKey, keys[0], "message")
+	assert.NoError(t, err, "Sign should not return an error")
+	opening, err := Open(publicKey, openingKey, manager.Registry(), "message", signature)
+	assert.NoError(t, err, "Open should not return an error")
+
+	data, err := publicKey.MarshalBinary()
+	assert.NoError(t, err, "MarshalBinary should not return an error")
+	var decodedKey PublicKey
+	assert.NoError(t, decodedKey.UnmarshalBinary(data), "UnmarshalBinary should not return an error")
+
+	data, err = signature.MarshalBinary()
+	assert.NoError(t, err, "MarshalBinary should not return an error")
+	assert.Len(t, data, SignatureSize)
+	var decodedSignature Signature
+	assert.NoError(t, decodedSignature.UnmarshalBinary(data), "UnmarshalBinary should not return an error")
+
+	isValid, err := Verify(decodedKey, "message", decodedSignature)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.True(t, isValid, "Verify should accept a decoded signature")
+
+	data, err = keys[0].MarshalBinary()
+	assert.NoError(t, err, "MarshalBinary should not return an error")
+	var decodedMember MemberKey
+	assert.NoError(t, decodedMember.UnmarshalBinary(data), "UnmarshalBinary should not return an error")
+	assert.True(t, VerifyMemberKey(publicKey, decodedMember), "Decoded member key should be valid")
+
+	data, err = opening.MarshalBinary()
+	assert.NoError(t, err, "MarshalBinary should not return an error")
+	var decodedOpening Opening
+	assert.NoError(t, decodedOpening.UnmarshalBinary(data), "UnmarshalBinary should not return an error")
+	isValid, err = Judge(publicKey, manager.Registry(), "message", signature, decodedOpening)
+	assert.NoError(t, err, "Judge should not return an error")
+	assert.True(t, isValid, "Judge should accept a decoded opening")
+
+	assert.Error(t, decodedSignature.UnmarshalBinary(data), "UnmarshalBinary should reject data of the wrong length")
+}
+
+// TestRevocation tests that revoked members are detected by the scan and the index while
+// signatures of other members still verify.
+func TestRevocation(t *testing.T) {
+	publicKey, _, manager, keys := setupGroup(t, 3)
+	token, err := manager.Revoke(keys[0].Index)
+	assert.NoError(t, err, "Revoke should not return an error")
+	_, err = manager.Revoke(99)
+	assert.Error(t, err, "Revoke should reject an unknown member")
+	tokens := manager.RevocationList()
+	assert.Len(t, tokens, 1)
+
+	revoked, err := SignRevocable(publicKey, keys[0], "message")
+	assert.NoError(t, err, "SignRevocable should not return an error")
+	_, err = VerifyRevocable(publicKey, "message", revoked, tokens)
+	assert.ErrorIs(t, err, ErrRevoked, "VerifyRevocable should reject a revoked member")
+
+	honest, err := SignRevocable(publicKey, keys[1], "message")
+	assert.NoError(t, err, "SignRevocable should not return an error")
+	isValid, err := VerifyRevocable(publicKey, "message", honest, tokens)
+	assert.NoError(t, err, "VerifyRevocable should not return an error")
+	assert.True(t, isValid, "VerifyRevocable should accept an unrevoked member")
+
+	again, err := SignRevocable(publicKey, keys[1], "message")
+	assert.NoError(t, err, "SignRevocable should not return an error")
+	assert.False(t, again.K.IsEqual(honest.K), "Revocation tags of an unrevoked member should be unlinkable")
+
+	index, err := NewRevocationIndex(tokens)
+	assert.NoError(t, err, "NewRevocationIndex should not return an error")
+	member, isRevoked, err := index.Check(revoked)
+	assert.NoError(t, err, "Check should not return an error")
+	assert.True(t, isRevoked, "Check should detect a revoked member")
+	assert.Equal(t, token.Index, member)
+
+	_, isRevoked, err = index.Check(honest)
+	assert.NoError(t, err, "Check should not return an error")
+	assert.False(t, isRevoked, "Check should accept an unrevoked member")
+
+	_, err = VerifyRevocableWithIndex(publicKey, "message", revoked, index)
+	assert.ErrorIs(t, err, ErrRevoked, "VerifyRevocableWithIndex should reject a revoked member")
+	isValid, err = VerifyRevocableWithIndex(publicKey, "message", honest, index)
+	assert.NoError(t, err, "VerifyRevocableWithIndex should not return an error")
+	assert.True(t, isValid, "VerifyRevocableWithIndex should accept an unrevoked member")
+
+	plain, err := Sign(publicKey, keys[2], "message")
+	assert.NoError(t, err, "Sign should not return an error")
+	_, _, err = index.Check(plain)
+	assert.Error(t, err, "Check should reject a signature without a revocation tag")
+}
+
+// TestRevocationTokenCannotSign tests that the published token and the public registry do not
+// let anyone produce signatures that verify or open to the revoked member.
+func TestRevocationTokenCannotSign(t *testing.T) {
+	publicKey, openingKey, manager, keys := setupGroup(t, 2)
+	token, err := manager.Revoke(keys[0].Index)
+	assert.NoError(t, err, "Revoke should not return an error")
+	registry := manager.Registry()
+
+	// The attacker knows A from the registry and y from the token, but not x
+	forged := MemberKey{Index: token.Index, A: registry[0].A, X: token.Y, Y: token.Y}
+	assert.False(t, VerifyMemberKey(publicKey, forged), "The token should not complete the member key")
+
+	signature, err := Sign(publicKey, forged, "message")
+	assert.NoError(t, err, "Sign should not return an error")
+	isValid, err := Verify(publicKey, "message", signature)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.False(t, isValid, "Verify should reject a signature made with the token")
+
+	_, err = Open(publicKey, openingKey, registry, "message", signature)
+	assert.Error(t, err, "Open should not attribute a signature made with the token")
+}
+
+// TestRevocationTagBinding tests that the revocation tag cannot be swapped for another member's.
+func TestRevocationTagBinding(t *testing.T) {
+	publicKey, _, _, keys := setupGroup(t, 2)
+	signature, err := SignRevocable(publicKey, keys[0], "message")
+	assert.NoError(t, err, "SignRevocable should not return an error")
+	other, err := SignRevocable(publicKey, keys[1], "message")
+	assert.NoError(t, err, "SignRevocable should not return an error")
+
+	signature.K = other.K
+	isValid, err := Verify(publicKey, "message", signature)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.False(t, isValid, "Verify should reject a swapped revocation tag")
+
+	data, err := other.MarshalBinary()
+	assert.NoError(t, err, "MarshalBinary should not return an error")
+	assert.Len(t, data, RevocableSignatureSize)
+	var decoded Signature
+	assert.NoError(t, decoded.UnmarshalBinary(data), "UnmarshalBinary should not return an error")
+	isValid, err = Verify(publicKey, "message", decoded)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.True(t, isValid, "Verify should accept a decoded revocable signature")
+}
@@ -0,0 +1,212 @@
+package groupsig
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	e "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+// ErrRevoked is returned when a signature was produced by a revoked member.
+var ErrRevoked = errors.New("signer has been revoked")
+
+// RevocationToken is the published revocation secret y of a revoked member. A signature with
+// tag (B, K) was produced by the member exactly when K = B^y.
+//
+// The token is not the signing secret x, so publishing it does not let anyone sign as the member.
+// It does link every revocable signature the member has ever produced, so tokens should only be
+// released for members that are actually revoked.
+type RevocationToken struct {
+	Index int
+	Y     *e.Scalar
+}
+
+// SignRevocable produces a group signature with a revocation tag so that verifiers can check
+// it against the published revocation list. Every signature uses a fresh random base, so the
+// signatures of unrevoked members stay unlinkable.
+//
+// Parameters:
+//   - publicKey: The group public key.
+//   - memberKey: The private key of the signing member.
+//   - message: The message to be signed.
+//
+// Returns:
+//   - Signature: The group signature with the tag (B, K).
+//   - error: An error if the signing process fails.
+func SignRevocable(publicKey PublicKey, memberKey MemberKey, message string) (Signature, error) {
+	base, err := utils.RandomG1Element()
+	if err != nil {
+		return Signature{}, err
+	}
+	return sign(publicKey, memberKey, message, &base)
+}
+
+// Revoke revokes a member and returns the revocation token to be published.
+func (m *Manager) Revoke(index int) (RevocationToken, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	y, ok := m.tokens[index]
+	if !ok {
+		return RevocationToken{}, fmt.Errorf("member %d is not registered", index)
+	}
+	for _, token := range m.revoked {
+		if token.Index == index {
+			return token, nil
+		}
+	}
+	token := RevocationToken{Index: index, Y: y}
+	m.revoked = append(m.revoked, token)
+	return token, nil
+}
+
+// RevocationList returns a copy of the tokens of all revoked members.
+func (m *Manager) RevocationList() []RevocationToken {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]RevocationToken(nil), m.revoked...)
+}
+
+// CheckRevocation scans the revocation list and reports the index of the revoked member that
+// produced the signature, testing K = B^y for every token.
+//
+// Returns:
+//   - int: The index of the revoked signer, or -1.
+//   - boolean: True if the signer has been revoked.
+//   - error: An error if the signature has no revocation tag.
+func CheckRevocation(signature Signature, tokens []RevocationToken) (int, bool, error) {
+	if signature.B == nil || signature.K == nil {
+		return -1, false, errors.New("signature has no revocation tag")
+	}
+	tag := new(e.G1)
+	for _, token := range tokens {
+		tag.ScalarMult(token.Y, signature.B)
+		if tag.IsEqual(signature.K) {
+			return token.Index, true, nil
+		}
+	}
+	return -1, false, nil
+}
+
+// RevocationIndex speeds up checking signatures against a long revocation list. Every signature
+// has its own base B, so the tags B^y cannot be computed ahead of time; instead Check builds a
+// fixed-base table of B once per signature, after which every token costs about a quarter of the
+// exponentiation done by CheckRevocation. The table costs about five exponentiations, so the index
+// pays off for lists of more than a few dozen tokens.
+type RevocationIndex struct {
+	indexes []int
+	digits  [][]byte
+}
+
+// NewRevocationIndex precomputes the base-16 digits of the tokens.
+func NewRevocationIndex(tokens []RevocationToken) (*RevocationIndex, error) {
+	index := &RevocationIndex{indexes: make([]int, len(tokens)), digits: make([][]byte, len(tokens))}
+	for i, token := range tokens {
+		encoded, err := token.Y.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		index.indexes[i] = token.Index
+		index.digits[i] = nibbles(encoded)
+	}
+	return index, nil
+}
+
+// Check reports the index of the revoked member that produced the signature.
+//
+// Returns:
+//   - int: The index of the revoked signer, or -1.
+//   - boolean: True if the signer has been revoked.
+//   - error: An error if the signature has no revocation tag.
+func (ri *RevocationIndex) Check(signature Signature) (int, bool, error) {
+	if signature.B == nil || signature.K == nil {
+		return -1, false, errors.New("signature has no revocation tag")
+	}
+	table := newFixedBaseTable(signature.B)
+	for i, digits := range ri.digits {
+		if table.exp(digits).IsEqual(signature.K) {
+			return ri.indexes[i], true, nil
+		}
+	}
+	return -1, false, nil
+}
+
+// fixedBaseTable holds B^{d·16^w} for every position w of a 4-bit digit and every digit d.
+type fixedBaseTable [2 * e.ScalarSize][16]e.G1
+
+// newFixedBaseTable computes the table of the base.
+func newFixedBaseTable(base *e.G1) *fixedBaseTable {
+	table := new(fixedBaseTable)
+	power := *base
+	for w := range table {
+		table[w][0].SetIdentity()
+		for d := 1; d < 16; d++ {
+			table[w][d].Add(&table[w][d-1], &power)
+		}
+		for i := 0; i < 4; i++ {
+			power.Double()
+		}
+	}
+	return table
+}
+
+// exp computes B^s from the digits of s, least significant first, with one addition per digit.
+func (table *fixedBaseTable) exp(digits []byte) *e.G1 {
+	result := new(e.G1)
+	result.SetIdentity()
+	for w, d := range digits {
+		if d != 0 {
+			result.Add(result, &table[w][d])
+		}
+	}
+	return result
+}
+
+// nibbles splits a big-endian scalar encoding into 4-bit digits, least significant first.
+func nibbles(encoded []byte) []byte {
+	digits := make([]byte, 0, 2*len(encoded))
+	for i := len(encoded) - 1; i >= 0; i-- {
+		digits = append(digits, encoded[i]&0x0f, encoded[i]>>4)
+	}
+	return digits
+}
+
+// VerifyRevocable checks a group signature and that its signer is not on the revocation list.
+//
+// Returns:
+//   - boolean: True if the signature is valid and the signer is not revoked.
+//   - error: ErrRevoked if the signer has been revoked, or an error if the signature is malformed.
+func VerifyRevocable(publicKey PublicKey, message string, signature Signature, tokens []RevocationToken) (bool, error) {
+	return verifyRevocable(publicKey, message, signature, func(signature Signature) (int, bool, error) {
+		return CheckRevocation(signature, tokens)
+	})
+}
+
+// VerifyRevocableWithIndex is VerifyRevocable for long revocation lists: it checks the signer
+// against a revocation index built with NewRevocationIndex instead of scanning the tokens.
+//
+// Returns:
+//   - boolean: True if the signature is valid and the signer is not revoked.
+//   - error: ErrRevoked if the signer has been revoked, or an error if the signature is malformed.
+func VerifyRevocableWithIndex(publicKey PublicKey, message string, signature Signature, index *RevocationIndex) (bool, error) {
+	if index == nil {
+		return false, errors.New("revocation index is missing")
+	}
+	return verifyRevocable(publicKey, message, signature, index.Check)
+}
+
+// verifyRevocable checks a group signature and then its signer with the given revocation check.
+func verifyRevocable(publicKey PublicKey, message string, signature Signature, check func(Signature) (int, bool, error)) (bool, error) {
+	isValid, err := Verify(publicKey, message, signature)
+	if err != nil || !isValid {
+		return false, err
+	}
+	_, revoked, err := check(signature)
+	if err != nil {
+		return false, err
+	}
+	if revoked {
+		return false, ErrRevoked
+	}
+	return true, nil
+}
@@ -0,0 +1,319 @@
+package issuance
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/aniagut/msc-bbs-plus-plus/models"
+	"github.com/aniagut/msc-bbs-plus-plus/sigma"
+	"github.com/aniagut/msc-bbs-plus-plus/sign"
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	"github.com/aniagut/msc-bbs-plus-plus/verify"
+	e "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+// requestDomain separates the well-formedness proofs of batch requests from other proofs.
+const requestDomain = "BBS++-BATCH-BLIND-ISSUANCE-V1"
+
+// Request asks the issuer to blindly sign N copies of a credential. Copy k has the commitment
+// C_k = ∏_{j ∈ Indexes} h₁[j]^{m_{k,j}} to its hidden messages. Proof is one proof of knowledge of
+// all openings, in which the messages at the Common indexes share one witness, so the issuer
+// knows that every copy carries the same value there (for example the holder secret).
+//
+// The hidden message at the Blinding index is a fresh random value in every copy that is never
+// disclosed. It hides the other messages in C_k, so that a copy shown later cannot be linked to
+// the request or to its sibling copies, even when its other hidden messages are guessable.
+type Request struct {
+	Indexes     []int
+	Common      []int
+	Blinding    int
+	Commitments []*e.G1
+	Proof       sigma.Proof
+}
+
+// Pending is the holder's state between the request and the issuer's response.
+type Pending struct {
+	Request Request
+	copies  []map[int]string
+}
+
+// NewRequest creates a request for one copy per entry of copies.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - copies: The hidden messages of every copy, indexed by their position; all copies must hide the same positions.
+//   - common: The hidden positions whose value is the same in all copies and proved to be so.
+//   - blinding: The position of the random blinding message, which NewRequest fills in for every copy.
+//   - nonce: An issuer-supplied nonce bound to the request.
+//
+// Returns:
+//   - *Pending: The holder's state, which contains the request to be sent.
+//   - error: An error if the copies are inconsistent.
+func NewRequest(publicParams models.PublicParameters, copies []map[int]string, common []int, blinding int, nonce []byte) (*Pending, error) {
+	if len(copies) == 0 {
+		return nil, errors.New("request needs at least one copy")
+	}
+	if blinding < 0 || blinding >= len(publicParams.H1) {
+		return nil, errors.New("blinding index out of range")
+	}
+	if _, ok := copies[0][blinding]; ok {
+		return nil, errors.New("blinding position must not be set by the caller")
+	}
+	copies, err := withBlinding(copies, blinding)
+	if err != nil {
+		return nil, err
+	}
+	indexes := make([]int, 0, len(copies[0]))
+	for j := range copies[0] {
+		if j < 0 || j >= len(publicParams.H1) {
+			return nil, errors.New("hidden index out of range")
+		}
+		indexes = append(indexes, j)
+	}
+	sort.Ints(indexes)
+	for k, hidden := range copies {
+		if len(hidden) != len(indexes) {
+			return nil, fmt.Errorf("copy %d hides other positions", k)
+		}
+		for _, j := range indexes {
+			if _, ok := hidden[j]; !ok {
+				return nil, fmt.Errorf("copy %d hides other positions", k)
+			}
+		}
+	}
+	common = append([]int(nil), common...)
+	sort.Ints(common)
+	for i, j := range common {
+		if _, ok := copies[0][j]; !ok || j == blinding {
+			return nil, errors.New("common position is not hidden")
+		}
+		if i > 0 && common[i-1] == j {
+			return nil, errors.New("duplicate common position")
+		}
+		for k := range copies {
+			if copies[k][j] != copies[0][j] {
+				return nil, fmt.Errorf("copy %d differs at common position %d", k, j)
+			}
+		}
+	}
+
+	// Step 1: Commit to the hidden messages of every copy
+	request := Request{Indexes: indexes, Common: common, Blinding: blinding, Commitments: make([]*e.G1, len(copies))}
+	w := sigma.Witness{}
+	for k, hidden := range copies {
+		points := make([]*e.G1, len(indexes))
+		scalars := make([]*e.Scalar, len(indexes))
+		for t, j := range indexes {
+			points[t] = &publicParams.H1[j]
+			scalars[t] = utils.MessageToScalar(hidden[j])
+			w[witnessName(request, k, j)] = scalars[t]
+		}
+		request.Commitments[k] = utils.MultiExpG1(points, scalars)
+	}
+
+	// Step 2: Prove knowledge of all openings at once
+	statement, err := wellFormedness(publicParams, request)
+	if err != nil {
+		return nil, err
+	}
+	request.Proof, err = sigma.Prove(requestTranscript(request, nonce), statement, w)
+	if err != nil {
+		return nil, err
+	}
+	return &Pending{Request: request, copies: copies}, nil
+}
+
+// Issue checks a request and blindly signs every copy together with the disclosed messages.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - signingKey: The signing key of the issuer.
+//   - request: The holder's request.
+//   - disclosed: The messages chosen by the issuer, indexed by their position; with the hidden
+//     positions they must cover the message vector.
+//   - nonce: The nonce the request was bound to.
+//
+// Returns:
+//   - []models.Signature: One signature per copy.
+//   - error: An error if the request is malformed or its proof is invalid.
+func Issue(publicParams models.PublicParameters, signingKey models.SigningKey, request Request, disclosed map[int]string, nonce []byte) ([]models.Signature, error) {
+	if len(request.Commitments) == 0 {
+		return nil, errors.New("request needs at least one copy")
+	}
+	if err := checkLayout(publicParams, request, disclosed); err != nil {
+		return nil, err
+	}
+
+	// Step 1: Check the well-formedness proof of all commitments
+	statement, err := wellFormedness(publicParams, request)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := sigma.Verify(requestTranscript(request, nonce), statement, request.Proof)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("request proof is invalid")
+	}
+
+	// Step 2: Sign C = g1 · C_k · ∏_{disclosed} h₁[i]^{m_i} for every copy
+	base := disclosedCommitment(publicParams, disclosed)
+	signatures := make([]models.Signature, len(request.Commitments))
+	for k, commitment := range request.Commitments {
+		c := new(e.G1)
+		c.Add(base, commitment)
+		elem, err := sign.RandomE(signingKey)
+		if err != nil {
+			return nil, err
+		}
+		signatures[k] = models.Signature{A: sign.ComputeA(signingKey.X, elem, c), E: elem}
+	}
+	return signatures, nil
+}
+
+// Finalize batch-verifies the issued signatures and returns the full message vector of every copy.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - verificationKey: The verification key of the issuer.
+//   - disclosed: The messages chosen by the issuer.
+//   - signatures: The signatures returned by Issue.
+//
+// Returns:
+//   - [][]string: The message vector of every copy, in the order of the signatures.
+//   - error: An error if the signatures do not verify.
+func (p *Pending) Finalize(publicParams models.PublicParameters, verificationKey models.VerificationKey, disclosed map[int]string, signatures []models.Signature) ([][]string, error) {
+	if len(signatures) != len(p.copies) {
+		return nil, errors.New("number of signatures does not match the request")
+	}
+	if err := checkLayout(publicParams, p.Request, disclosed); err != nil {
+		return nil, err
+	}
+	messages := make([][]string, len(p.copies))
+	for k, hidden := range p.copies {
+		messages[k] = make([]string, len(publicParams.H1))
+		for i, message := range disclosed {
+			messages[k][i] = message
+		}
+		for j, message := range hidden {
+			messages[k][j] = message
+		}
+	}
+	ok, err := verify.BatchVerify(publicParams, verificationKey, messages, signatures)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("issued signatures are invalid")
+	}
+	return messages, nil
+}
+
+// wellFormedness builds the statement C_k = ∏_{j ∈ Indexes} h₁[j]^{m_{k,j}} for every copy.
+func wellFormedness(publicParams models.PublicParameters, request Request) (*sigma.Statement, error) {
+	statement := sigma.NewStatement()
+	for k, commitment := range request.Commitments {
+		if commitment == nil {
+			return nil, errors.New("request is missing a commitment")
+		}
+		terms := make([]sigma.Term, len(request.Indexes))
+		for t, j := range request.Indexes {
+			if j < 0 || j >= len(publicParams.H1) {
+				return nil, errors.New("hidden index out of range")
+			}
+			terms[t] = sigma.Term{Base: sigma.G1(&publicParams.H1[j]), Var: witnessName(request, k, j)}
+		}
+		statement.Relation(sigma.G1(commitment), terms...)
+	}
+	return statement, statement.Err()
+}
+
+// witnessName names the witness of hidden message j in copy k; common positions share one name.
+func witnessName(request Request, k, j int) string {
+	i := sort.SearchInts(request.Common, j)
+	if i < len(request.Common) && request.Common[i] == j {
+		return fmt.Sprintf("m%d", j)
+	}
+	return fmt.Sprintf("m%d_%d", j, k)
+}
+
+// requestTranscript returns the transcript of a request's proof, bound to its layout and the nonce.
+func requestTranscript(request Request, nonce []byte) *sigma.Transcript {
+	t := sigma.NewTranscript(requestDomain)
+	t.AppendMessage("nonce", nonce)
+	t.AppendUint64("copies", uint64(len(request.Commitments)))
+	t.AppendUint64("hidden", uint64(len(request.Indexes)))
+	for _, j := range request.Indexes {
+		t.AppendUint64("index", uint64(j))
+	}
+	t.AppendUint64("common", uint64(len(request.Common)))
+	for _, j := range request.Common {
+		t.AppendUint64("index", uint64(j))
+	}
+	t.AppendUint64("blinding", uint64(request.Blinding))
+	return t
+}
+
+// checkLayout checks that the hidden and disclosed positions partition the message vector, that
+// the common positions are hidden and strictly increasing, as witnessName requires, and that the
+// blinding position is hidden and not common.
+func checkLayout(publicParams models.PublicParameters, request Request, disclosed map[int]string) error {
+	if len(request.Indexes)+len(disclosed) != len(publicParams.H1) {
+		return errors.New("hidden and disclosed messages do not cover the message vector")
+	}
+	hidden := make(map[int]bool, len(request.Indexes))
+	for _, j := range request.Indexes {
+		if j < 0 || j >= len(publicParams.H1) || hidden[j] {
+			return errors.New("invalid hidden index")
+		}
+		hidden[j] = true
+	}
+	for i := range disclosed {
+		if hidden[i] || i < 0 || i >= len(publicParams.H1) {
+			return errors.New("invalid disclosed index")
+		}
+	}
+	for i, j := range request.Common {
+		if !hidden[j] || j == request.Blinding {
+			return errors.New("common position is not hidden")
+		}
+		if i > 0 && request.Common[i-1] >= j {
+			return errors.New("common positions are not sorted")
+		}
+	}
+	if !hidden[request.Blinding] {
+		return errors.New("blinding position is not hidden")
+	}
+	return nil
+}
+
+// withBlinding returns copies of the hidden messages with a fresh random message at the blinding position.
+func withBlinding(copies []map[int]string, blinding int) ([]map[int]string, error) {
+	blinded := make([]map[int]string, len(copies))
+	for k, hidden := range copies {
+		blinded[k] = make(map[int]string, len(hidden)+1)
+		for j, message := range hidden {
+			blinded[k][j] = message
+		}
+		random, err := utils.RandomMessage()
+		if err != nil {
+			return nil, err
+		}
+		blinded[k][blinding] = random
+	}
+	return blinded, nil
+}
+
+// disclosedCommitment computes g1 · ∏_{disclosed} h₁[i]^{m_i}.
+func disclosedCommitment(publicParams models.PublicParameters, disclosed map[int]string) *e.G1 {
+	c := new(e.G1)
+	*c = *publicParams.G1
+	for i, message := range disclosed {
+		term := new(e.G1)
+		term.ScalarMult(utils.MessageToScalar(message), &publicParams.H1[i])
+		c.Add(c, term)
+	}
+	return c
+}
@@ -0,0 +1,132 @@
+package issuance
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aniagut/msc-bbs-plus-plus/keygen"
+	"github.com/aniagut/msc-bbs-plus-plus/sigma"
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	"github.com/aniagut/msc-bbs-plus-plus/verify"
+	e "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/stretchr/testify/assert"
+)
+
+// copiesWithSerials returns n copies with the same holder secret and different serials.
+func copiesWithSerials(n int) []map[int]string {
+	copies := make([]map[int]string, n)
+	for k := range copies {
+		copies[k] = map[int]string{0: "holder-secret", 1: fmt.Sprintf("serial-%d", k)}
+	}
+	return copies
+}
+
+// TestBatchIssuance tests the full flow: request, issue and batch verification of N copies.
+func TestBatchIssuance(t *testing.T) {
+	result, err := keygen.KeyGen(5)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	publicParams := result.PublicParameters
+	disclosed := map[int]string{3: "student", 4: "2026"}
+	nonce := []byte("nonce")
+
+	pending, err := NewRequest(publicParams, copiesWithSerials(5), []int{0}, 2, nonce)
+	assert.NoError(t, err, "NewRequest should not return an error")
+	assert.Len(t, pending.Request.Proof.Responses, 11, "The common secret should share one response")
+
+	signatures, err := Issue(publicParams, result.SigningKey, pending.Request, disclosed, nonce)
+	assert.NoError(t, err, "Issue should not return an error")
+	assert.Len(t, signatures, 5, "Issue should return one signature per copy")
+
+	messages, err := pending.Finalize(publicParams, result.VerificationKey, disclosed, signatures)
+	assert.NoError(t, err, "Finalize should not return an error")
+	for k := range messages {
+		assert.Equal(t, fmt.Sprintf("serial-%d", k), messages[k][1], "Finalize should return the copy's serial")
+		isValid, err := verify.Verify(publicParams, result.VerificationKey, messages[k], signatures[k])
+		assert.NoError(t, err, "Verify should not return an error")
+		assert.True(t, isValid, "Every copy should be an ordinary signature")
+	}
+
+	// With the holder secret guessed and a serial shown, the commitments still do not link the copies
+	unblinded := make(map[string]bool)
+	for k, commitment := range pending.Request.Commitments {
+		c := new(e.G1)
+		c.ScalarMult(utils.Neg(utils.MessageToScalar(messages[k][1])), &publicParams.H1[1])
+		c.Add(c, commitment)
+		unblinded[string(c.BytesCompressed())] = true
+	}
+	assert.Len(t, unblinded, 5, "Removing the serials should not make the commitments equal")
+
+	signatures[2], signatures[3] = signatures[3], signatures[2]
+	_, err = pending.Finalize(publicParams, result.VerificationKey, disclosed, signatures)
+	assert.Error(t, err, "Finalize should reject invalid signatures")
+}
+
+// TestInvalidRequest tests that the issuer rejects requests it cannot trust.
+func TestInvalidRequest(t *testing.T) {
+	result, err := keygen.KeyGen(4)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	publicParams := result.PublicParameters
+	disclosed := map[int]string{3: "student"}
+	nonce := []byte("nonce")
+
+	copies := copiesWithSerials(3)
+	copies[1][0] = "other-secret"
+	_, err = NewRequest(publicParams, copies, []int{0}, 2, nonce)
+	assert.Error(t, err, "NewRequest should reject copies with different common values")
+
+	_, err = NewRequest(publicParams, copiesWithSerials(3), []int{0}, 1, nonce)
+	assert.Error(t, err, "NewRequest should reject a blinding position set by the caller")
+
+	pending, err := NewRequest(publicParams, copiesWithSerials(3), []int{0}, 2, nonce)
+	assert.NoError(t, err, "NewRequest should not return an error")
+
+	_, err = Issue(publicParams, result.SigningKey, pending.Request, disclosed, []byte("other"))
+	assert.Error(t, err, "Issue should reject a request bound to another nonce")
+
+	_, err = Issue(publicParams, result.SigningKey, pending.Request, map[int]string{}, nonce)
+	assert.Error(t, err, "Issue should reject an incomplete message vector")
+
+	// A copy with another secret, proved without the common constraint, cannot be substituted
+	unrelated, err := NewRequest(publicParams, []map[int]string{{0: "other-secret", 1: "serial-9"}}, nil, 2, nonce)
+	assert.NoError(t, err, "NewRequest should not return an error")
+	forged := pending.Request
+	forged.Commitments = append(forged.Commitments[:0:0], pending.Request.Commitments...)
+	forged.Commitments[1] = unrelated.Request.Commitments[0]
+	_, err = Issue(publicParams, result.SigningKey, forged, disclosed, nonce)
+	assert.Error(t, err, "Issue should reject a commitment outside the proof")
+}
+
+// TestUnsortedCommon tests that the issuer rejects a request whose common positions are not
+// sorted, which would let the proof leave some of them unconstrained.
+func TestUnsortedCommon(t *testing.T) {
+	result, err := keygen.KeyGen(4)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	publicParams := result.PublicParameters
+	disclosed := map[int]string{3: "student"}
+	nonce := []byte("nonce")
+
+	// The copies share the serial but not the holder secret, and the proof only covers the serial
+	copies := []map[int]string{{0: "holder-secret", 1: "serial"}, {0: "other-secret", 1: "serial"}}
+	pending, err := NewRequest(publicParams, copies, []int{1}, 2, nonce)
+	assert.NoError(t, err, "NewRequest should not return an error")
+
+	// Claim both positions as common in an order under which the holder secret gets one witness per copy
+	forged := pending.Request
+	forged.Common = []int{1, 0}
+	w := sigma.Witness{}
+	for k, hidden := range pending.copies {
+		for _, j := range forged.Indexes {
+			w[witnessName(forged, k, j)] = utils.MessageToScalar(hidden[j])
+		}
+	}
+	statement, err := wellFormedness(publicParams, forged)
+	assert.NoError(t, err, "wellFormedness should not return an error")
+	forged.Proof, err = sigma.Prove(requestTranscript(forged, nonce), statement, w)
+	assert.NoError(t, err, "Prove should not return an error")
+
+	_, err = Issue(publicParams, result.SigningKey, forged, disclosed, nonce)
+	assert.Error(t, err, "Issue should reject unsorted common positions")
+
+	_, err = NewRequest(publicParams, copiesWithSerials(2), []int{0, 0}, 2, nonce)
+	assert.Error(t, err, "NewRequest should reject duplicate common positions")
+}
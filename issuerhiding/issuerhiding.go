@@ -0,0 +1,239 @@
+package issuerhiding
+
+import (
+	"errors"
+
+	"github.com/aniagut/msc-bbs-plus-plus/models"
+	"github.com/aniagut/msc-bbs-plus-plus/proof"
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	e "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+const (
+	// presentationDomain separates issuer-hiding presentation challenges from other hashes.
+	presentationDomain = "BBS++-ISSUER-HIDING-PRESENTATION-V1"
+	// randomizerDomain is the domain separation tag used to derive the key randomization base k2.
+	randomizerDomain = "BBS++-ISSUER-HIDING-KEY-RANDOMIZER-V1"
+)
+
+// Branch is one branch of the 1-out-of-k proof, with its share of the challenge and its response.
+type Branch struct {
+	Challenge *e.Scalar
+	Response  *e.Scalar
+}
+
+// Presentation proves possession of a credential issued under one of the verification keys of a
+// policy without revealing which. The holder publishes the randomized key X' = X_i · k2^ρ and proves,
+// for some j in the policy, knowledge of ρ with
+//   - X' · X_j^{-1} = k2^ρ, and
+//   - e(Ā, X') / e(B̄, g2) = e(Ā, k2)^ρ,
+//
+// which together imply e(Ā, X_j) = e(B̄, g2). The branches are composed with an OR proof, so the
+// presentation has one branch per accepted issuer and its cost grows linearly with the policy size:
+// each branch costs two G2 and two G_T exponentiations for both prover and verifier.
+type Presentation struct {
+	Proof    models.Proof
+	XPrime   *e.G2
+	Branches []Branch
+}
+
+// Present generates an issuer-hiding presentation for a verifier-supplied policy.
+//
+// All issuers of the policy must share the same public parameters, which would otherwise reveal the issuer.
+//
+// Parameters:
+//   - publicParams: The public parameters shared by the issuers.
+//   - policy: The verification keys of the accepted issuers.
+//   - issuerKey: The verification key of the issuer of the credential; it must be in the policy.
+//   - signature: The signature on the messages.
+//   - m: The full message vector.
+//   - disclosed: The indexes of the messages revealed to the verifier.
+//   - nonce: A verifier-supplied nonce bound to the presentation.
+//
+// Returns:
+//   - Presentation: The generated presentation.
+//   - error: An error if the issuer is not in the policy or the proof cannot be generated.
+func Present(publicParams models.PublicParameters, policy []models.VerificationKey, issuerKey models.VerificationKey, signature models.Signature, m []string, disclosed []int, nonce []byte) (Presentation, error) {
+	if issuerKey.X2 == nil {
+		return Presentation{}, errors.New("issuer key is invalid")
+	}
+	issuer := -1
+	for j, key := range policy {
+		if key.X2 == nil {
+			return Presentation{}, errors.New("policy has an invalid verification key")
+		}
+		if issuer < 0 && key.X2.IsEqual(issuerKey.X2) {
+			issuer = j
+		}
+	}
+	if issuer < 0 {
+		return Presentation{}, errors.New("issuer is not in the policy")
+	}
+	k2 := randomizer()
+
+	// Step 1: Commitment phase of the credential proof
+	prover, err := proof.NewProver(publicParams, signature, m, disclosed, nil)
+	if err != nil {
+		return Presentation{}, err
+	}
+	aBar, bBar := prover.Randomized()
+
+	// Step 2: Randomize the issuer key X' = X_i · k2^ρ
+	random, err := utils.RandomScalars(2)
+	if err != nil {
+		return Presentation{}, err
+	}
+	rho, rhoTilde := random[0], random[1]
+	xPrime := new(e.G2)
+	xPrime.ScalarMult(rho, k2)
+	xPrime.Add(xPrime, issuerKey.X2)
+	base, y := pairingStatement(publicParams, aBar, bBar, xPrime, k2)
+
+	// Step 3: Simulate the branches of the other issuers and commit to the real one
+	branches := make([]Branch, len(policy))
+	us := make([]*e.G2, len(policy))
+	vs := make([]*e.Gt, len(policy))
+	for j := range policy {
+		if j == issuer {
+			us[j] = new(e.G2)
+			us[j].ScalarMult(rhoTilde, k2)
+			vs[j] = new(e.Gt)
+			vs[j].Exp(base, rhoTilde)
+			continue
+		}
+		simulated, err := utils.RandomScalars(2)
+		if err != nil {
+			return Presentation{}, err
+		}
+		branches[j] = Branch{Challenge: simulated[0], Response: simulated[1]}
+		us[j], vs[j] = branchCommitments(policy[j], xPrime, k2, base, y, branches[j])
+	}
+
+	// Step 4: Derive the challenge and split it: c_i = c - Σ_{j≠i} c_j
+	challenge := presentationChallenge(prover.Bytes(), policy, xPrime, us, vs, nonce)
+	real := new(e.Scalar)
+	real.Set(challenge)
+	for j, branch := range branches {
+		if j != issuer {
+			real.Sub(real, branch.Challenge)
+		}
+	}
+	branches[issuer] = Branch{Challenge: real, Response: proof.Response(rhoTilde, real, rho)}
+
+	return Presentation{
+		Proof:    prover.Respond(challenge),
+		XPrime:   xPrime,
+		Branches: branches,
+	}, nil
+}
+
+// Verify checks an issuer-hiding presentation against a policy.
+//
+// Parameters:
+//   - publicParams: The public parameters shared by the issuers.
+//   - policy: The verification keys of the accepted issuers, in the order used by the holder.
+//   - presentation: The presentation to be verified.
+//   - revealed: The disclosed messages, indexed by their position in the message vector.
+//   - nonce: The nonce the presentation was bound to.
+//
+// Returns:
+//   - boolean: True if the presentation is valid, false otherwise.
+//   - error: An error if the presentation is malformed.
+func Verify(publicParams models.PublicParameters, policy []models.VerificationKey, presentation Presentation, revealed map[int]string, nonce []byte) (bool, error) {
+	if len(policy) == 0 {
+		return false, errors.New("policy is empty")
+	}
+	if presentation.XPrime == nil || len(presentation.Branches) != len(policy) {
+		return false, errors.New("presentation does not match the policy")
+	}
+	for _, branch := range presentation.Branches {
+		if branch.Challenge == nil || branch.Response == nil {
+			return false, errors.New("presentation has a malformed branch")
+		}
+	}
+	k2 := randomizer()
+
+	// Step 1: Recompute the credential proof transcript
+	transcript, err := proof.TranscriptBytes(publicParams, presentation.Proof, revealed)
+	if err != nil {
+		return false, err
+	}
+	aBar, bBar := presentation.Proof.ABar, presentation.Proof.BBar
+	if aBar.IsIdentity() {
+		return false, nil
+	}
+
+	// Step 2: Check that the branch challenges add up to the challenge
+	sum := new(e.Scalar)
+	for _, branch := range presentation.Branches {
+		sum.Add(sum, branch.Challenge)
+	}
+	if sum.IsEqual(presentation.Proof.Challenge) != 1 {
+		return false, nil
+	}
+
+	// Step 3: Recompute the commitments of every branch
+	base, y := pairingStatement(publicParams, aBar, bBar, presentation.XPrime, k2)
+	us := make([]*e.G2, len(policy))
+	vs := make([]*e.Gt, len(policy))
+	for j := range policy {
+		if policy[j].X2 == nil {
+			return false, errors.New("policy has an invalid verification key")
+		}
+		us[j], vs[j] = branchCommitments(policy[j], presentation.XPrime, k2, base, y, presentation.Branches[j])
+	}
+
+	// Step 4: Check the challenge
+	expected := presentationChallenge(transcript, policy, presentation.XPrime, us, vs, nonce)
+	return expected.IsEqual(presentation.Proof.Challenge) == 1, nil
+}
+
+// pairingStatement returns the base e(Ā, k2) and the value Y = e(Ā, X') / e(B̄, g2) of the pairing statement.
+func pairingStatement(publicParams models.PublicParameters, aBar, bBar *e.G1, xPrime, k2 *e.G2) (*e.Gt, *e.Gt) {
+	base := e.Pair(aBar, k2)
+	y := e.ProdPair([]*e.G1{aBar, bBar}, []*e.G2{xPrime, publicParams.G2}, []*e.Scalar{utils.One(), utils.Neg(utils.One())})
+	return base, y
+}
+
+// branchCommitments recomputes the commitments of a branch from its challenge and response:
+// U_j = k2^{s_j} · (X' · X_j^{-1})^{-c_j} and V_j = e(Ā, k2)^{s_j} · Y^{-c_j}.
+func branchCommitments(key models.VerificationKey, xPrime, k2 *e.G2, base, y *e.Gt, branch Branch) (*e.G2, *e.Gt) {
+	negC := utils.Neg(branch.Challenge)
+
+	// (X' · X_j^{-1})^{-c_j} = X'^{-c_j} · X_j^{c_j}
+	u := new(e.G2)
+	u.ScalarMult(branch.Response, k2)
+	term := new(e.G2)
+	term.ScalarMult(negC, xPrime)
+	u.Add(u, term)
+	term.ScalarMult(branch.Challenge, key.X2)
+	u.Add(u, term)
+
+	v := new(e.Gt)
+	v.Exp(base, branch.Response)
+	yc := new(e.Gt)
+	yc.Exp(y, negC)
+	v.Mul(v, yc)
+	return u, v
+}
+
+// randomizer returns the key randomization base k2.
+func randomizer() *e.G2 {
+	return utils.HashToG2([]byte("k2"), randomizerDomain)
+}
+
+// presentationChallenge hashes the credential proof transcript, the policy, the randomized key,
+// the branch commitments and the nonce.
+func presentationChallenge(transcript []byte, policy []models.VerificationKey, xPrime *e.G2, us []*e.G2, vs []*e.Gt, nonce []byte) *e.Scalar {
+	inputs := [][]byte{
+		[]byte(presentationDomain),
+		transcript,
+		xPrime.BytesCompressed(),
+	}
+	for j := range policy {
+		v, _ := vs[j].MarshalBinary()
+		inputs = append(inputs, policy[j].X2.BytesCompressed(), us[j].BytesCompressed(), v)
+	}
+	inputs = append(inputs, nonce)
+	return utils.HashToScalar(inputs...)
+}
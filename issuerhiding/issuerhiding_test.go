@@ -0,0 +1,77 @@
+package issuerhiding
+
+import (
+	"testing"
+
+	"github.com/aniagut/msc-bbs-plus-plus/keygen"
+	"github.com/aniagut/msc-bbs-plus-plus/models"
+	"github.com/aniagut/msc-bbs-plus-plus/sign"
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	e "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/stretchr/testify/assert"
+)
+
+// issuers creates count issuers sharing the same public parameters.
+func issuers(t *testing.T, count, l int) (models.PublicParameters, []models.SigningKey, []models.VerificationKey) {
+	result, err := keygen.KeyGen(l)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	signingKeys := []models.SigningKey{result.SigningKey}
+	verificationKeys := []models.VerificationKey{result.VerificationKey}
+	for len(signingKeys) < count {
+		x, err := utils.RandomScalar()
+		assert.NoError(t, err, "RandomScalar should not return an error")
+		X2 := new(e.G2)
+		X2.ScalarMult(&x, result.PublicParameters.G2)
+		signingKeys = append(signingKeys, models.SigningKey{X: &x})
+		verificationKeys = append(verificationKeys, models.VerificationKey{X2: X2})
+	}
+	return result.PublicParameters, signingKeys, verificationKeys
+}
+
+// TestPresentVerify tests that a credential of every issuer in the policy is accepted.
+func TestPresentVerify(t *testing.T) {
+	publicParams, signingKeys, policy := issuers(t, 4, 2)
+	messages := []string{"alice", "student"}
+	nonce := []byte("nonce")
+
+	for i := range policy {
+		signature, err := sign.Sign(publicParams, signingKeys[i], messages)
+		assert.NoError(t, err, "Sign should not return an error")
+		presentation, err := Present(publicParams, policy, policy[i], signature, messages, []int{1}, nonce)
+		assert.NoError(t, err, "Present should not return an error")
+
+		isValid, err := Verify(publicParams, policy, presentation, map[int]string{1: "student"}, nonce)
+		assert.NoError(t, err, "Verify should not return an error")
+		assert.True(t, isValid, "Verify should accept a credential of issuer %d", i)
+
+		isValid, err = Verify(publicParams, policy, presentation, map[int]string{1: "student"}, []byte("other"))
+		assert.NoError(t, err, "Verify should not return an error")
+		assert.False(t, isValid, "Verify should reject a presentation for another nonce")
+	}
+}
+
+// TestIssuerOutsidePolicy tests that credentials of issuers outside the policy are rejected.
+func TestIssuerOutsidePolicy(t *testing.T) {
+	publicParams, signingKeys, keys := issuers(t, 3, 1)
+	policy := keys[:2]
+	messages := []string{"alice"}
+	signature, err := sign.Sign(publicParams, signingKeys[2], messages)
+	assert.NoError(t, err, "Sign should not return an error")
+
+	_, err = Present(publicParams, policy, keys[2], signature, messages, nil, nil)
+	assert.Error(t, err, "Present should reject an issuer outside the policy")
+
+	// A holder claiming a policy issuer for a credential of another issuer must fail verification.
+	presentation, err := Present(publicParams, policy, policy[0], signature, messages, nil, nil)
+	assert.NoError(t, err, "Present should not return an error")
+	isValid, err := Verify(publicParams, policy, presentation, map[int]string{}, nil)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.False(t, isValid, "Verify should reject a credential of an issuer outside the policy")
+
+	_, err = Verify(publicParams, keys, presentation, map[int]string{}, nil)
+	assert.Error(t, err, "Verify should reject a presentation for another policy size")
+
+	withNil := []models.VerificationKey{keys[2], {}}
+	_, err = Present(publicParams, withNil, keys[2], signature, messages, nil, nil)
+	assert.Error(t, err, "Present should reject a policy with a missing key")
+}
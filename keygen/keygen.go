@@ -1,6 +1,8 @@
 package keygen
 
 import (
+	"errors"
+
 	"github.com/aniagut/msc-bbs-plus-plus/models"
 	"github.com/aniagut/msc-bbs-plus-plus/utils"
 	e "github.com/cloudflare/circl/ecc/bls12381"
@@ -13,7 +15,8 @@ import (
 //   - l - length of the messages vector
 //
 // Returns:
-//   - KeyGenResult: A struct containing the keys for signing and verifying messages.
+//   - KeyGenResult: A struct containing the keys for signing and verifying messages
+//     and a proof of possession of the signing key.
 //   - error: An error if key generation fails.
 func KeyGen(l int) (models.KeyGenResult, error) {
 	
@@ -27,28 +30,125 @@ func KeyGen(l int) (models.KeyGenResult, error) {
 		return models.KeyGenResult{}, err
 	}
 
-	// 3. Select random x ∈ Zp*
+	// 3. Select random h0 ← generator of G1 for the blinding scalar of legacy BBS+ signatures
+	h0, err := utils.RandomG1Element()
+	if err != nil {
+		return models.KeyGenResult{}, err
+	}
+
+	// 4. Select random x ∈ Zp*
 	x, err := utils.RandomScalar()
 	if err != nil {
 		return models.KeyGenResult{}, err
 	}
 
-	// 4. Compute verification key vk = X₂ ← g₂^x
+	// 5. Compute verification key vk = X₂ ← g₂^x
 	X2 := new(e.G2)
 	X2.ScalarMult(&x, g2)
 
+	signingKey := models.SigningKey{
+		X: &x,
+	}
+	verificationKey := models.VerificationKey{
+		X2: X2,
+	}
+	publicParams := models.PublicParameters{
+		G1: g1,
+		G2: g2,
+		H1: h1,
+		H0: &h0,
+	}
+
+	// 6. Prove possession of x for X₂
+	pop, err := ProvePossession(publicParams, signingKey, verificationKey)
+	if err != nil {
+		return models.KeyGenResult{}, err
+	}
+
 	// Return the result
 	return models.KeyGenResult{
-		SigningKey: models.SigningKey{
-			X: &x,
-		},
-		VerificationKey: models.VerificationKey{
-			X2: X2,
-		},
-		PublicParameters: models.PublicParameters{
-			G1: g1,
-			G2: g2,
-			H1: h1,
-		},
+		SigningKey:        signingKey,
+		VerificationKey:   verificationKey,
+		PublicParameters:  publicParams,
+		ProofOfPossession: pop,
 	}, nil
+}
+
+// popDomain separates proof-of-possession challenges from other hashes in the library.
+const popDomain = "BBS++-PROOF-OF-POSSESSION-V1"
+
+// ProvePossession generates a non-interactive Schnorr proof of knowledge of x such that X₂ = g₂^x.
+// The proof is bound to the identifier of the public parameters, so it cannot be replayed for
+// another parameter set.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - signingKey: The signing key x.
+//   - verificationKey: The verification key X₂.
+//
+// Returns:
+//   - ProofOfPossession: The proof (c, s).
+//   - error: An error if randomness generation fails.
+func ProvePossession(publicParams models.PublicParameters, signingKey models.SigningKey, verificationKey models.VerificationKey) (models.ProofOfPossession, error) {
+	// 1. Select random k and compute T = g₂^k
+	k, err := utils.RandomScalar()
+	if err != nil {
+		return models.ProofOfPossession{}, err
+	}
+	T := new(e.G2)
+	T.ScalarMult(&k, publicParams.G2)
+
+	// 2. Compute the challenge c = H(params, X₂, T)
+	c := possessionChallenge(publicParams, verificationKey, T)
+
+	// 3. Compute the response s = k + c·x
+	s := new(e.Scalar)
+	s.Mul(c, signingKey.X)
+	s.Add(s, &k)
+
+	return models.ProofOfPossession{
+		Challenge: c,
+		Response:  s,
+	}, nil
+}
+
+// VerifyPossession checks a proof of possession for a verification key. Key registries should
+// run it before accepting an issuer key.
+//
+// Parameters:
+//   - publicParams: The public parameters the key is registered for.
+//   - verificationKey: The verification key X₂.
+//   - pop: The proof of possession.
+//
+// Returns:
+//   - boolean: True if the proof is valid, false otherwise.
+//   - error: An error if the inputs are malformed.
+func VerifyPossession(publicParams models.PublicParameters, verificationKey models.VerificationKey, pop models.ProofOfPossession) (bool, error) {
+	if verificationKey.X2 == nil || pop.Challenge == nil || pop.Response == nil {
+		return false, errors.New("missing verification key or proof components")
+	}
+	if verificationKey.X2.IsIdentity() || !verificationKey.X2.IsOnG2() {
+		return false, nil
+	}
+
+	// 1. Recompute T = g₂^s · X₂^{-c}
+	T := new(e.G2)
+	T.ScalarMult(pop.Response, publicParams.G2)
+	xc := new(e.G2)
+	xc.ScalarMult(utils.Neg(pop.Challenge), verificationKey.X2)
+	T.Add(T, xc)
+
+	// 2. Check the challenge
+	c := possessionChallenge(publicParams, verificationKey, T)
+	return c.IsEqual(pop.Challenge) == 1, nil
+}
+
+// possessionChallenge computes c = H(domain, params identifier, X₂, T).
+func possessionChallenge(publicParams models.PublicParameters, verificationKey models.VerificationKey, T *e.G2) *e.Scalar {
+	return utils.HashToScalar(
+		[]byte(popDomain),
+		utils.ParametersID(publicParams),
+		verificationKey.X2.BytesCompressed(),
+		T.BytesCompressed(),
+	)
 }
\ No newline at end of file
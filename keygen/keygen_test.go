@@ -77,4 +77,52 @@ func TestKeyGenH1Generators(t *testing.T) {
     for i, h1 := range result.PublicParameters.H1 {
         assert.False(t, h1.IsIdentity(), "h1[%d] should not be the identity element", i)
     }
-}
\ No newline at end of file
+}
+
+// TestKeyGenProofOfPossession tests that KeyGen produces a valid proof of possession.
+func TestKeyGenProofOfPossession(t *testing.T) {
+    result, err := KeyGen(5)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    isValid, err := VerifyPossession(result.PublicParameters, result.VerificationKey, result.ProofOfPossession)
+    assert.NoError(t, err, "VerifyPossession should not return an error")
+    assert.True(t, isValid, "Proof of possession generated by KeyGen should be valid")
+}
+
+// TestProofOfPossessionWrongKey tests that a proof of possession cannot be reused for another key.
+func TestProofOfPossessionWrongKey(t *testing.T) {
+    result1, err1 := KeyGen(5)
+    result2, err2 := KeyGen(5)
+    assert.NoError(t, err1, "First KeyGen call should not return an error")
+    assert.NoError(t, err2, "Second KeyGen call should not return an error")
+
+    // A rogue key X₂' = X₂ · g₂ for which the attacker does not know the discrete logarithm
+    rogue := new(bls12381.G2)
+    rogue.Add(result1.VerificationKey.X2, result1.PublicParameters.G2)
+    result1.VerificationKey.X2 = rogue
+
+    isValid, err := VerifyPossession(result1.PublicParameters, result1.VerificationKey, result2.ProofOfPossession)
+    assert.NoError(t, err, "VerifyPossession should not return an error")
+    assert.False(t, isValid, "Proof of possession should not verify for another key")
+}
+
+// TestProofOfPossessionWrongParameters tests that a proof of possession is bound to the public parameters.
+func TestProofOfPossessionWrongParameters(t *testing.T) {
+    result, err := KeyGen(5)
+    assert.NoError(t, err, "KeyGen should not return an error")
+    other, err := KeyGen(5)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    isValid, err := VerifyPossession(other.PublicParameters, result.VerificationKey, result.ProofOfPossession)
+    assert.NoError(t, err, "VerifyPossession should not return an error")
+    assert.False(t, isValid, "Proof of possession should not verify under other public parameters")
+}
+
+// TestKeyGenH0Generator tests that the legacy blinding generator h0 is a valid element in G1.
+func TestKeyGenH0Generator(t *testing.T) {
+    result, err := KeyGen(5)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    assert.NotNil(t, result.PublicParameters.H0, "h0 should be generated")
+    assert.False(t, result.PublicParameters.H0.IsIdentity(), "h0 should not be the identity element")
+}
@@ -0,0 +1,86 @@
+package kvac
+
+import (
+	"errors"
+
+	"github.com/aniagut/msc-bbs-plus-plus/models"
+	"github.com/aniagut/msc-bbs-plus-plus/proof"
+	"github.com/aniagut/msc-bbs-plus-plus/sign"
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	e "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+// Keyed-verification mode: the tag (A, e) with A = c^{1/(x+e)} is an algebraic MAC that the
+// holder of x checks as A^{x+e} = c, without pairings. Tags are computed exactly like
+// signatures, so the issuer uses sign.Sign and holders present them with proof.Prove.
+// In this mode the issuer does not publish its VerificationKey; if it did, tags would
+// also be publicly verifiable signatures.
+
+// Issue computes a MAC on the message vector. It is the same computation as sign.Sign.
+func Issue(publicParams models.PublicParameters, signingKey models.SigningKey, m []string) (models.Signature, error) {
+	return sign.Sign(publicParams, signingKey, m)
+}
+
+// Verify checks a MAC with the secret key.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - signingKey: The issuer's secret key x.
+//   - m: The message vector.
+//   - mac: The MAC (A, e) to be verified.
+//
+// Returns:
+//   - boolean: True if A^{x+e} = g1 · ∏_i h₁[i]^m[i], false otherwise.
+//   - error: An error if the verification process fails.
+func Verify(publicParams models.PublicParameters, signingKey models.SigningKey, m []string, mac models.Signature) (bool, error) {
+	if mac.A == nil || mac.E == nil {
+		return false, errors.New("MAC is missing components")
+	}
+	if mac.A.IsIdentity() {
+		return false, nil
+	}
+
+	// Step 1: Compute commitment c ← g1 * ∏_i h₁[i]^m[i]
+	c, err := utils.ComputeCommitment(m, publicParams.H1, publicParams.G1)
+	if err != nil {
+		return false, err
+	}
+
+	// Step 2: Check A^{x+e} ?= c
+	xPlusE := new(e.Scalar)
+	xPlusE.Add(signingKey.X, mac.E)
+	lhs := new(e.G1)
+	lhs.ScalarMult(xPlusE, mac.A)
+	return lhs.IsEqual(c), nil
+}
+
+// VerifyPresentation checks a presentation produced with proof.Prove using the secret key.
+// The pairing check e(Ā, X₂) = e(B̄, g₂) of proof.Verify is replaced by Ā^x = B̄.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - signingKey: The issuer's secret key x.
+//   - presentation: The proof of knowledge of a MAC.
+//   - revealed: The disclosed messages, indexed by their position in the message vector.
+//   - nonce: The nonce the presentation was bound to.
+//
+// Returns:
+//   - boolean: True if the presentation is valid, false otherwise.
+//   - error: An error if the presentation is malformed.
+func VerifyPresentation(publicParams models.PublicParameters, signingKey models.SigningKey, presentation models.Proof, revealed map[int]string, nonce []byte) (bool, error) {
+	transcript, err := proof.TranscriptBytes(publicParams, presentation, revealed)
+	if err != nil {
+		return false, err
+	}
+	if presentation.ABar.IsIdentity() {
+		return false, nil
+	}
+
+	// Check Ā^x ?= B̄
+	aBarX := new(e.G1)
+	aBarX.ScalarMult(signingKey.X, presentation.ABar)
+	if !aBarX.IsEqual(presentation.BBar) {
+		return false, nil
+	}
+	return proof.Challenge(transcript, nonce).IsEqual(presentation.Challenge) == 1, nil
+}
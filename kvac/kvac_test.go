@@ -0,0 +1,86 @@
+package kvac
+
+import (
+	"testing"
+
+	"github.com/aniagut/msc-bbs-plus-plus/keygen"
+	"github.com/aniagut/msc-bbs-plus-plus/proof"
+	"github.com/aniagut/msc-bbs-plus-plus/verify"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestVerifyMAC tests that an issued MAC verifies under the secret key.
+func TestVerifyMAC(t *testing.T) {
+	result, err := keygen.KeyGen(3)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	messages := []string{"message1", "message2", "message3"}
+
+	mac, err := Issue(result.PublicParameters, result.SigningKey, messages)
+	assert.NoError(t, err, "Issue should not return an error")
+
+	isValid, err := Verify(result.PublicParameters, result.SigningKey, messages, mac)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.True(t, isValid, "Verify should accept a valid MAC")
+
+	isValid, err = Verify(result.PublicParameters, result.SigningKey, []string{"message1", "message2", "other"}, mac)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.False(t, isValid, "Verify should reject a MAC on other messages")
+}
+
+// TestVerifyMACWrongKey tests that a MAC does not verify under another secret key.
+func TestVerifyMACWrongKey(t *testing.T) {
+	result, _ := keygen.KeyGen(3)
+	other, _ := keygen.KeyGen(3)
+	messages := []string{"message1", "message2", "message3"}
+
+	mac, err := Issue(result.PublicParameters, result.SigningKey, messages)
+	assert.NoError(t, err, "Issue should not return an error")
+
+	isValid, err := Verify(result.PublicParameters, other.SigningKey, messages, mac)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.False(t, isValid, "Verify should reject a MAC under another key")
+}
+
+// TestVerifyPresentation tests keyed verification of a selective disclosure presentation.
+func TestVerifyPresentation(t *testing.T) {
+	result, _ := keygen.KeyGen(3)
+	other, _ := keygen.KeyGen(3)
+	messages := []string{"message1", "message2", "message3"}
+	nonce := []byte("nonce")
+
+	mac, err := Issue(result.PublicParameters, result.SigningKey, messages)
+	assert.NoError(t, err, "Issue should not return an error")
+	presentation, err := proof.Prove(result.PublicParameters, mac, messages, []int{1}, nonce)
+	assert.NoError(t, err, "Prove should not return an error")
+
+	revealed := map[int]string{1: "message2"}
+	isValid, err := VerifyPresentation(result.PublicParameters, result.SigningKey, presentation, revealed, nonce)
+	assert.NoError(t, err, "VerifyPresentation should not return an error")
+	assert.True(t, isValid, "VerifyPresentation should accept a valid presentation")
+
+	isValid, err = VerifyPresentation(result.PublicParameters, other.SigningKey, presentation, revealed, nonce)
+	assert.NoError(t, err, "VerifyPresentation should not return an error")
+	assert.False(t, isValid, "VerifyPresentation should reject a presentation under another key")
+}
+
+// BenchmarkVerifyMAC measures keyed verification of a MAC.
+func BenchmarkVerifyMAC(b *testing.B) {
+	result, _ := keygen.KeyGen(10)
+	messages := make([]string, 10)
+	mac, _ := Issue(result.PublicParameters, result.SigningKey, messages)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Verify(result.PublicParameters, result.SigningKey, messages, mac)
+	}
+}
+
+// BenchmarkVerifyPairing measures pairing-based verification of the same tag for comparison.
+func BenchmarkVerifyPairing(b *testing.B) {
+	result, _ := keygen.KeyGen(10)
+	messages := make([]string, 10)
+	mac, _ := Issue(result.PublicParameters, result.SigningKey, messages)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = verify.Verify(result.PublicParameters, result.VerificationKey, messages, mac)
+	}
+}
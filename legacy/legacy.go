@@ -0,0 +1,131 @@
+package legacy
+
+import (
+	"errors"
+
+	"github.com/aniagut/msc-bbs-plus-plus/models"
+	"github.com/aniagut/msc-bbs-plus-plus/sign"
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	"github.com/aniagut/msc-bbs-plus-plus/verify"
+	e "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+// Sign generates a legacy BBS+ signature (A, e, s) for a given message vector.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system, including h0.
+//   - signingKey: The key used for signing the messages.
+//   - m: The messages to be signed.
+//
+// Returns:
+//   - signature: The generated signature.
+//   - error: An error if the signing process fails.
+func Sign(publicParams models.PublicParameters, signingKey models.SigningKey, m []string) (models.LegacySignature, error) {
+	// Step 1: Set random s ← Z_p*
+	s, err := utils.RandomScalar()
+	if err != nil {
+		return models.LegacySignature{}, errors.New("failed to generate random scalar s")
+	}
+
+	// Step 2: Compute commitment c ← g1 * h0^s * ∏_i h₁[i]^m[i]
+	c, err := ComputeCommitment(publicParams, m, &s)
+	if err != nil {
+		return models.LegacySignature{}, err
+	}
+
+	// Step 3: Set random elem ← Z_p* and ensure x + e ≠ 0
+	elem, err := sign.RandomE(signingKey)
+	if err != nil {
+		return models.LegacySignature{}, err
+	}
+
+	// Step 4: Compute signature component A <- c^{1 / (x + e)} ∈ G_1
+	A := sign.ComputeA(signingKey.X, elem, c)
+
+	// Step 5: Return the signature σ = (A, e, s)
+	return models.LegacySignature{
+		A: A,
+		E: elem,
+		S: &s,
+	}, nil
+}
+
+// Verify checks the validity of a legacy BBS+ signature.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system, including h0.
+//   - verificationKey: The verification key of the system.
+//   - m: The message to be verified.
+//   - signature: The signature to be verified.
+//
+// Returns:
+//   - boolean: True if the signature is valid, false otherwise.
+//   - error: An error if the verification process fails.
+func Verify(publicParams models.PublicParameters, verificationKey models.VerificationKey, m []string, signature models.LegacySignature) (bool, error) {
+	if signature.A == nil || signature.E == nil || signature.S == nil {
+		return false, errors.New("signature is missing components")
+	}
+
+	// Step 1: Compute commitment c ← g1 * h0^s * ∏_i h₁[i]^m[i]
+	c, err := ComputeCommitment(publicParams, m, signature.S)
+	if err != nil {
+		return false, err
+	}
+
+	// Step 2: Check pairing e(A, g2^e · vk) ?= e(c, g2)
+	g2e := new(e.G2)
+	g2e.ScalarMult(signature.E, publicParams.G2)
+	g2e.Add(g2e, verificationKey.X2)
+
+	e1 := e.Pair(signature.A, g2e)
+	e2 := e.Pair(c, publicParams.G2)
+	return e1.IsEqual(e2), nil
+}
+
+// ComputeCommitment computes the legacy commitment c = g1 · h0^s · ∏_i h₁[i]^m[i].
+func ComputeCommitment(publicParams models.PublicParameters, m []string, s *e.Scalar) (*e.G1, error) {
+	if publicParams.H0 == nil {
+		return nil, errors.New("public parameters do not include the generator h0")
+	}
+	c, err := utils.ComputeCommitment(m, publicParams.H1, publicParams.G1)
+	if err != nil {
+		return nil, err
+	}
+	h0s := new(e.G1)
+	h0s.ScalarMult(s, publicParams.H0)
+	c.Add(c, h0s)
+	return c, nil
+}
+
+// VerifyAny verifies a signature of either kind under one API: a models.Signature (A, e) is
+// checked with verify.Verify and a models.LegacySignature (A, e, s) with Verify.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - verificationKey: The verification key of the system.
+//   - m: The message to be verified.
+//   - signature: A models.Signature or models.LegacySignature, or a pointer to one.
+//
+// Returns:
+//   - boolean: True if the signature is valid, false otherwise.
+//   - error: An error if the signature type is unsupported or verification fails.
+func VerifyAny(publicParams models.PublicParameters, verificationKey models.VerificationKey, m []string, signature interface{}) (bool, error) {
+	switch sig := signature.(type) {
+	case models.Signature:
+		return verify.Verify(publicParams, verificationKey, m, sig)
+	case *models.Signature:
+		if sig == nil {
+			return false, errors.New("signature is nil")
+		}
+		return verify.Verify(publicParams, verificationKey, m, *sig)
+	case models.LegacySignature:
+		return Verify(publicParams, verificationKey, m, sig)
+	case *models.LegacySignature:
+		if sig == nil {
+			return false, errors.New("signature is nil")
+		}
+		return Verify(publicParams, verificationKey, m, *sig)
+	default:
+		return false, errors.New("unsupported signature type")
+	}
+}
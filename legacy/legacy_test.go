@@ -0,0 +1,82 @@
+package legacy
+
+import (
+	"testing"
+
+	"github.com/aniagut/msc-bbs-plus-plus/keygen"
+	"github.com/aniagut/msc-bbs-plus-plus/models"
+	"github.com/aniagut/msc-bbs-plus-plus/sign"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLegacySignVerify tests that a legacy BBS+ signature verifies.
+func TestLegacySignVerify(t *testing.T) {
+	result, err := keygen.KeyGen(3)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	messages := []string{"message1", "message2", "message3"}
+
+	signature, err := Sign(result.PublicParameters, result.SigningKey, messages)
+	assert.NoError(t, err, "Sign should not return an error")
+	assert.NotNil(t, signature.S, "Signature component S should not be nil")
+
+	isValid, err := Verify(result.PublicParameters, result.VerificationKey, messages, signature)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.True(t, isValid, "Verify should accept a valid legacy signature")
+}
+
+// TestLegacyVerifyInvalid tests that modified messages or blinding scalars are rejected.
+func TestLegacyVerifyInvalid(t *testing.T) {
+	result, err := keygen.KeyGen(3)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	messages := []string{"message1", "message2", "message3"}
+
+	signature, err := Sign(result.PublicParameters, result.SigningKey, messages)
+	assert.NoError(t, err, "Sign should not return an error")
+
+	isValid, err := Verify(result.PublicParameters, result.VerificationKey, []string{"message1", "message2", "other"}, signature)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.False(t, isValid, "Verify should reject a signature on other messages")
+
+	signature.S.SetUint64(1)
+	isValid, err = Verify(result.PublicParameters, result.VerificationKey, messages, signature)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.False(t, isValid, "Verify should reject a signature with a modified s")
+}
+
+// TestLegacyMissingH0 tests that parameters without h0 are rejected.
+func TestLegacyMissingH0(t *testing.T) {
+	result, err := keygen.KeyGen(3)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	result.PublicParameters.H0 = nil
+
+	_, err = Sign(result.PublicParameters, result.SigningKey, []string{"a", "b", "c"})
+	assert.Error(t, err, "Sign should fail without h0")
+}
+
+// TestVerifyAny tests that both signature kinds verify through the compatibility layer.
+func TestVerifyAny(t *testing.T) {
+	result, err := keygen.KeyGen(3)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	pp, vk := result.PublicParameters, result.VerificationKey
+	messages := []string{"message1", "message2", "message3"}
+
+	bbsSignature, err := sign.Sign(pp, result.SigningKey, messages)
+	assert.NoError(t, err, "Sign should not return an error")
+	legacySignature, err := Sign(pp, result.SigningKey, messages)
+	assert.NoError(t, err, "Sign should not return an error")
+
+	for _, signature := range []interface{}{bbsSignature, &bbsSignature, legacySignature, &legacySignature} {
+		isValid, err := VerifyAny(pp, vk, messages, signature)
+		assert.NoError(t, err, "VerifyAny should not return an error")
+		assert.True(t, isValid, "VerifyAny should accept a valid %T", signature)
+	}
+
+	// A legacy signature with s dropped is not a valid BBS++ signature
+	stripped := models.Signature{A: legacySignature.A, E: legacySignature.E}
+	isValid, err := VerifyAny(pp, vk, messages, stripped)
+	assert.NoError(t, err, "VerifyAny should not return an error")
+	assert.False(t, isValid, "VerifyAny should not accept a legacy signature without s")
+
+	_, err = VerifyAny(pp, vk, messages, "not a signature")
+	assert.Error(t, err, "VerifyAny should reject unsupported types")
+}
@@ -5,9 +5,10 @@ import (
 )
 
 type KeyGenResult struct {
-	SigningKey       SigningKey
-	VerificationKey  VerificationKey
-	PublicParameters PublicParameters
+	SigningKey        SigningKey
+	VerificationKey   VerificationKey
+	PublicParameters  PublicParameters
+	ProofOfPossession ProofOfPossession
 }
 
 type SigningKey struct {
@@ -18,14 +19,54 @@ type VerificationKey struct {
 	X2 *e.G2
 }
 
+// VerifierSecretKey is the secret key y of a designated verifier.
+type VerifierSecretKey struct {
+	Y *e.Scalar
+}
+
+// VerifierPublicKey is the public key Y = g1^y of a designated verifier. Presentations made for it
+// convince only the holder of y, who could have produced them alone.
+type VerifierPublicKey struct {
+	Y *e.G1
+}
+
 type PublicParameters struct {
 	G1 *e.G1
 	G2 *e.G2
 	H1 []e.G1
+	// H0 is the generator of the blinding scalar s of legacy BBS+ signatures.
+	H0 *e.G1
 }
 
 type Signature struct {
 	A *e.G1
 	E *e.Scalar
 }
-	
\ No newline at end of file
+
+// LegacySignature is a BBS+ signature (A, e, s) as defined by Au, Susilo and Mu (ASM06) and used
+// by Hyperledger-style implementations, where A = (g1 · h0^s · ∏_i h₁[i]^m[i])^{1/(x+e)}.
+type LegacySignature struct {
+	A *e.G1
+	E *e.Scalar
+	S *e.Scalar
+}
+
+// Proof is a zero-knowledge proof of knowledge of a BBS++ signature (A, e) on a message vector,
+// of which only a subset of the messages is disclosed. A, e and the hidden messages stay secret.
+type Proof struct {
+	ABar      *e.G1
+	BBar      *e.G1
+	D         *e.G1
+	Challenge *e.Scalar
+	EHat      *e.Scalar
+	R1Hat     *e.Scalar
+	R3Hat     *e.Scalar
+	MHat      map[int]*e.Scalar
+}
+
+// ProofOfPossession is a Schnorr proof of knowledge of the signing key x behind a
+// verification key X₂ = g₂^x, bound to the public parameters it was generated for.
+type ProofOfPossession struct {
+	Challenge *e.Scalar
+	Response  *e.Scalar
+}
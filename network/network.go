@@ -0,0 +1,154 @@
+package network
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrTimeout is returned by Receive when fewer messages than expected arrived in time.
+var ErrTimeout = errors.New("timed out waiting for messages")
+
+// Message is a protocol message delivered to a party.
+type Message struct {
+	From      int
+	Round     int
+	Broadcast bool
+	Payload   interface{}
+}
+
+// Transport connects one party of a multi-party protocol to the other parties.
+// Protocols are organized in numbered rounds; messages of a round are buffered until
+// the party asks for them, so parties may run ahead of each other.
+type Transport interface {
+	// ID returns the identifier of the local party.
+	ID() int
+	// Parties returns the identifiers of all parties, including the local one.
+	Parties() []int
+	// Send delivers a private message to one party.
+	Send(to int, round int, payload interface{}) error
+	// Broadcast delivers a message to every other party.
+	Broadcast(round int, payload interface{}) error
+	// Receive waits for count messages of the given round and returns them ordered by sender.
+	// If the messages do not arrive in time, it returns those that did together with ErrTimeout.
+	Receive(round int, count int) ([]Message, error)
+}
+
+// Memory is an in-process simulated network, used to run protocols on a single machine.
+type Memory struct {
+	parties []int
+	timeout time.Duration
+	inboxes map[int]*inbox
+}
+
+// inbox buffers the messages of one party by round.
+type inbox struct {
+	mu       sync.Mutex
+	messages map[int][]Message
+	notify   chan struct{}
+}
+
+// NewMemory creates a simulated network between the given parties. Receive gives up
+// after the given timeout, which simulates a party that stops responding.
+func NewMemory(parties []int, timeout time.Duration) *Memory {
+	m := &Memory{
+		parties: append([]int(nil), parties...),
+		timeout: timeout,
+		inboxes: make(map[int]*inbox, len(parties)),
+	}
+	sort.Ints(m.parties)
+	for _, id := range parties {
+		m.inboxes[id] = &inbox{
+			messages: make(map[int][]Message),
+			notify:   make(chan struct{}),
+		}
+	}
+	return m
+}
+
+// Endpoint returns the transport of the given party.
+func (m *Memory) Endpoint(id int) Transport {
+	return &memoryEndpoint{network: m, id: id}
+}
+
+// deliver appends a message to the inbox of a party and wakes up waiting receivers.
+func (m *Memory) deliver(to int, msg Message) error {
+	box, ok := m.inboxes[to]
+	if !ok {
+		return errors.New("unknown recipient")
+	}
+	box.mu.Lock()
+	box.messages[msg.Round] = append(box.messages[msg.Round], msg)
+	close(box.notify)
+	box.notify = make(chan struct{})
+	box.mu.Unlock()
+	return nil
+}
+
+// memoryEndpoint is the Transport of one party on a Memory network.
+type memoryEndpoint struct {
+	network *Memory
+	id      int
+}
+
+func (t *memoryEndpoint) ID() int {
+	return t.id
+}
+
+func (t *memoryEndpoint) Parties() []int {
+	return append([]int(nil), t.network.parties...)
+}
+
+func (t *memoryEndpoint) Send(to int, round int, payload interface{}) error {
+	return t.network.deliver(to, Message{From: t.id, Round: round, Payload: payload})
+}
+
+func (t *memoryEndpoint) Broadcast(round int, payload interface{}) error {
+	for _, to := range t.network.parties {
+		if to == t.id {
+			continue
+		}
+		if err := t.network.deliver(to, Message{From: t.id, Round: round, Broadcast: true, Payload: payload}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *memoryEndpoint) Receive(round int, count int) ([]Message, error) {
+	box := t.network.inboxes[t.id]
+	deadline := time.NewTimer(t.network.timeout)
+	defer deadline.Stop()
+
+	for {
+		box.mu.Lock()
+		if len(box.messages[round]) >= count {
+			messages := box.messages[round][:count]
+			box.messages[round] = box.messages[round][count:]
+			box.mu.Unlock()
+			sortBySender(messages)
+			return messages, nil
+		}
+		notify := box.notify
+		box.mu.Unlock()
+
+		select {
+		case <-notify:
+		case <-deadline.C:
+			box.mu.Lock()
+			messages := box.messages[round]
+			delete(box.messages, round)
+			box.mu.Unlock()
+			sortBySender(messages)
+			return messages, ErrTimeout
+		}
+	}
+}
+
+// sortBySender orders messages by the identifier of their sender.
+func sortBySender(messages []Message) {
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].From < messages[j].From
+	})
+}
@@ -0,0 +1,54 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSendReceive tests private and broadcast delivery between parties.
+func TestSendReceive(t *testing.T) {
+	net := NewMemory([]int{1, 2, 3}, time.Second)
+	p1, p2, p3 := net.Endpoint(1), net.Endpoint(2), net.Endpoint(3)
+
+	assert.NoError(t, p1.Broadcast(1, "hello"))
+	assert.NoError(t, p3.Send(2, 1, "private"))
+
+	messages, err := p2.Receive(1, 2)
+	assert.NoError(t, err, "Receive should not return an error")
+	assert.Equal(t, 1, messages[0].From, "Messages should be ordered by sender")
+	assert.True(t, messages[0].Broadcast, "Broadcast messages should be marked")
+	assert.Equal(t, "private", messages[1].Payload)
+	assert.False(t, messages[1].Broadcast, "Private messages should not be marked as broadcast")
+
+	messages, err = p3.Receive(1, 1)
+	assert.NoError(t, err, "Receive should not return an error")
+	assert.Equal(t, "hello", messages[0].Payload)
+}
+
+// TestRoundsBuffered tests that messages of later rounds are kept until requested.
+func TestRoundsBuffered(t *testing.T) {
+	net := NewMemory([]int{1, 2}, time.Second)
+	p1, p2 := net.Endpoint(1), net.Endpoint(2)
+
+	assert.NoError(t, p1.Send(2, 2, "second"))
+	assert.NoError(t, p1.Send(2, 1, "first"))
+
+	messages, err := p2.Receive(1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "first", messages[0].Payload)
+	messages, err = p2.Receive(2, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "second", messages[0].Payload)
+}
+
+// TestReceiveTimeout tests that missing messages result in ErrTimeout.
+func TestReceiveTimeout(t *testing.T) {
+	net := NewMemory([]int{1, 2, 3}, 50*time.Millisecond)
+	assert.NoError(t, net.Endpoint(1).Send(3, 1, "only"))
+
+	messages, err := net.Endpoint(3).Receive(1, 2)
+	assert.ErrorIs(t, err, ErrTimeout, "Receive should time out")
+	assert.Len(t, messages, 1, "Receive should return the messages that arrived")
+}
@@ -0,0 +1,183 @@
+package precompute
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aniagut/msc-bbs-plus-plus/models"
+	"github.com/aniagut/msc-bbs-plus-plus/proof"
+	"github.com/aniagut/msc-bbs-plus-plus/sign"
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	e "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+// pool is a bounded, concurrency-safe store of precomputed values. Every value is handed out at
+// most once; when the pool is empty, take computes a value on the request path instead.
+type pool[T any] struct {
+	entries chan T
+	produce func() (T, error)
+}
+
+// newPool creates a pool of the given capacity.
+func newPool[T any](capacity int, produce func() (T, error)) (*pool[T], error) {
+	if capacity <= 0 {
+		return nil, errors.New("pool capacity must be positive")
+	}
+	return &pool[T]{entries: make(chan T, capacity), produce: produce}, nil
+}
+
+// Fill precomputes values until the pool is full.
+func (p *pool[T]) Fill() error {
+	for len(p.entries) < cap(p.entries) {
+		entry, err := p.produce()
+		if err != nil {
+			return err
+		}
+		select {
+		case p.entries <- entry:
+		default:
+			// Filled concurrently by another goroutine
+			return nil
+		}
+	}
+	return nil
+}
+
+// Run keeps the pool full until the context is cancelled, and returns the context's error.
+func (p *pool[T]) Run(ctx context.Context) error {
+	for {
+		entry, err := p.produce()
+		if err != nil {
+			return err
+		}
+		select {
+		case p.entries <- entry:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Len returns the number of precomputed values available.
+func (p *pool[T]) Len() int {
+	return len(p.entries)
+}
+
+// take returns a precomputed value, or computes one if the pool is empty.
+func (p *pool[T]) take() (T, error) {
+	select {
+	case entry := <-p.entries:
+		return entry, nil
+	default:
+		return p.produce()
+	}
+}
+
+// issuerEntry is a signature randomness e with the inverse 1/(x+e).
+type issuerEntry struct {
+	e, inverse *e.Scalar
+}
+
+// IssuerPool precomputes the message-independent part of signing: sampling e and inverting x+e.
+// Signing with an entry costs the commitment and one scalar multiplication.
+type IssuerPool struct {
+	*pool[issuerEntry]
+}
+
+// NewIssuerPool creates an empty issuer pool; fill it with Fill or Run.
+//
+// Parameters:
+//   - signingKey: The signing key of the issuer.
+//   - capacity: The maximum number of precomputed entries.
+//
+// Returns:
+//   - *IssuerPool: The pool.
+//   - error: An error if the capacity is not positive.
+func NewIssuerPool(signingKey models.SigningKey, capacity int) (*IssuerPool, error) {
+	p, err := newPool(capacity, func() (issuerEntry, error) {
+		return newIssuerEntry(signingKey)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &IssuerPool{p}, nil
+}
+
+// Sign generates a BBS++ signature with precomputed randomness; it is equivalent to sign.Sign.
+func (p *IssuerPool) Sign(publicParams models.PublicParameters, m []string) (models.Signature, error) {
+	c, err := utils.ComputeCommitment(m, publicParams.H1, publicParams.G1)
+	if err != nil {
+		return models.Signature{}, err
+	}
+	return p.SignCommitment(c)
+}
+
+// SignCommitment generates a BBS++ signature on a commitment with precomputed randomness;
+// it is equivalent to sign.SignCommitment.
+func (p *IssuerPool) SignCommitment(c *e.G1) (models.Signature, error) {
+	entry, err := p.take()
+	if err != nil {
+		return models.Signature{}, err
+	}
+	A := new(e.G1)
+	A.ScalarMult(entry.inverse, c)
+	return models.Signature{A: A, E: entry.e}, nil
+}
+
+// newIssuerEntry samples e with x + e ≠ 0 and computes 1/(x+e).
+func newIssuerEntry(signingKey models.SigningKey) (issuerEntry, error) {
+	elem, err := sign.RandomE(signingKey)
+	if err != nil {
+		return issuerEntry{}, err
+	}
+	inverse := new(e.Scalar)
+	inverse.Add(signingKey.X, elem)
+	inverse.Inv(inverse)
+	return issuerEntry{e: elem, inverse: inverse}, nil
+}
+
+// HolderPool precomputes the commitment phase of proofs of knowledge of one credential for a
+// fixed set of disclosed messages. The commitment phase does not depend on the verifier's nonce,
+// so proving with an entry costs one hash and the responses. Every entry is used for one proof only.
+type HolderPool struct {
+	*pool[*proof.Prover]
+}
+
+// NewHolderPool creates a holder pool with one entry; fill it with Fill or Run.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - signature: The signature on the messages.
+//   - m: The full message vector.
+//   - disclosed: The indexes of the messages revealed in every proof.
+//   - capacity: The maximum number of precomputed entries.
+//
+// Returns:
+//   - *HolderPool: The pool.
+//   - error: An error if the capacity is not positive or the inputs are inconsistent.
+func NewHolderPool(publicParams models.PublicParameters, signature models.Signature, m []string, disclosed []int, capacity int) (*HolderPool, error) {
+	produce := func() (*proof.Prover, error) {
+		return proof.NewProver(publicParams, signature, m, disclosed, nil)
+	}
+	p, err := newPool(capacity, produce)
+	if err != nil {
+		return nil, err
+	}
+	// Compute the first entry now, so that inconsistent inputs fail at construction
+	first, err := produce()
+	if err != nil {
+		return nil, err
+	}
+	p.entries <- first
+	return &HolderPool{p}, nil
+}
+
+// Prove generates a proof bound to the nonce with a precomputed commitment phase; it is
+// equivalent to proof.Prove.
+func (p *HolderPool) Prove(nonce []byte) (models.Proof, error) {
+	prover, err := p.take()
+	if err != nil {
+		return models.Proof{}, err
+	}
+	return prover.Respond(proof.Challenge(prover.Bytes(), nonce)), nil
+}
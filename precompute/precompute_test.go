@@ -0,0 +1,115 @@
+package precompute
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aniagut/msc-bbs-plus-plus/keygen"
+	"github.com/aniagut/msc-bbs-plus-plus/models"
+	"github.com/aniagut/msc-bbs-plus-plus/proof"
+	"github.com/aniagut/msc-bbs-plus-plus/sign"
+	"github.com/aniagut/msc-bbs-plus-plus/verify"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestIssuerPool tests that signatures from a pool verify, also when it runs empty.
+func TestIssuerPool(t *testing.T) {
+	result, err := keygen.KeyGen(3)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	publicParams := result.PublicParameters
+	messages := []string{"a", "b", "c"}
+
+	_, err = NewIssuerPool(result.SigningKey, 0)
+	assert.Error(t, err, "NewIssuerPool should reject a zero capacity")
+
+	pool, err := NewIssuerPool(result.SigningKey, 4)
+	assert.NoError(t, err, "NewIssuerPool should not return an error")
+	assert.NoError(t, pool.Fill(), "Fill should not return an error")
+	assert.Equal(t, 4, pool.Len(), "Fill should fill the pool to its capacity")
+
+	for i := 0; i < 6; i++ {
+		signature, err := pool.Sign(publicParams, messages)
+		assert.NoError(t, err, "Sign should not return an error")
+		isValid, err := verify.Verify(publicParams, result.VerificationKey, messages, signature)
+		assert.NoError(t, err, "Verify should not return an error")
+		assert.True(t, isValid, "Verify should accept a signature from the pool")
+	}
+	assert.Equal(t, 0, pool.Len(), "Sign should consume the entries")
+}
+
+// TestIssuerPoolConcurrent tests concurrent signing while the pool is refilled in the background.
+func TestIssuerPoolConcurrent(t *testing.T) {
+	result, err := keygen.KeyGen(2)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	publicParams := result.PublicParameters
+	messages := []string{"a", "b"}
+	pool, err := NewIssuerPool(result.SigningKey, 8)
+	assert.NoError(t, err, "NewIssuerPool should not return an error")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- pool.Run(ctx) }()
+
+	var wg sync.WaitGroup
+	signatures := make(chan bool, 16)
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			signature, err := pool.Sign(publicParams, messages)
+			if err != nil {
+				signatures <- false
+				return
+			}
+			isValid, _ := verify.Verify(publicParams, result.VerificationKey, messages, signature)
+			signatures <- isValid
+		}()
+	}
+	wg.Wait()
+	close(signatures)
+	for isValid := range signatures {
+		assert.True(t, isValid, "Concurrent signatures should verify")
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled, "Run should stop when the context is cancelled")
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not stop after cancellation")
+	}
+}
+
+// TestHolderPool tests that proofs from a pool verify and never reuse a commitment phase.
+func TestHolderPool(t *testing.T) {
+	result, err := keygen.KeyGen(3)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	publicParams := result.PublicParameters
+	messages := []string{"a", "b", "c"}
+	signature, err := sign.Sign(publicParams, result.SigningKey, messages)
+	assert.NoError(t, err, "Sign should not return an error")
+	revealed := map[int]string{1: "b"}
+
+	_, err = NewHolderPool(publicParams, signature, messages[:2], []int{1}, 2)
+	assert.Error(t, err, "NewHolderPool should reject inconsistent inputs")
+
+	pool, err := NewHolderPool(publicParams, signature, messages, []int{1}, 2)
+	assert.NoError(t, err, "NewHolderPool should not return an error")
+	assert.NoError(t, pool.Fill(), "Fill should not return an error")
+
+	first, err := pool.Prove([]byte("nonce-1"))
+	assert.NoError(t, err, "Prove should not return an error")
+	second, err := pool.Prove([]byte("nonce-2"))
+	assert.NoError(t, err, "Prove should not return an error")
+	third, err := pool.Prove([]byte("nonce-3"))
+	assert.NoError(t, err, "Prove should not return an error")
+	assert.False(t, first.ABar.IsEqual(second.ABar), "Proofs should use different randomness")
+
+	for nonce, p := range map[string]models.Proof{"nonce-1": first, "nonce-2": second, "nonce-3": third} {
+		isValid, err := proof.Verify(publicParams, result.VerificationKey, p, revealed, []byte(nonce))
+		assert.NoError(t, err, "Verify should not return an error")
+		assert.True(t, isValid, "Verify should accept a proof from the pool")
+	}
+}
@@ -0,0 +1,373 @@
+package predicate
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+
+	"github.com/aniagut/msc-bbs-plus-plus/proof"
+	"github.com/aniagut/msc-bbs-plus-plus/sigma"
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	e "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+// quotientBits is the bit length of the quotient proved in MultipleOf, matching EncodeInt.
+const quotientBits = 64
+
+// errUnsatisfied is returned when the hidden messages do not satisfy a predicate.
+var errUnsatisfied = errors.New("predicate is not satisfied")
+
+// Expr is a linear expression Σ a_j · m_j + b over the scalars of the hidden messages.
+type Expr struct {
+	coefficients map[int]*e.Scalar
+	constant     *e.Scalar
+	text         string
+	compound     bool
+}
+
+// Predicate is a statement about hidden messages that a presentation proves in zero knowledge.
+type Predicate interface {
+	// String describes the predicate; it is bound to the proof and used in error reports.
+	String() string
+
+	// attributes returns the hidden messages the predicate refers to.
+	attributes() []int
+	// witness computes the public values, the bit openings and the witness of the predicate.
+	witness(g generators, prover *proof.Prover) (publics []*e.G1, bits []opening, w sigma.Witness, err error)
+	// statement builds the statement of the predicate from its public values and bit commitments.
+	statement(g generators, publics []*e.G1, bits []*e.G1) (*sigma.Statement, error)
+}
+
+// PredicateError reports which predicate of a presentation is not satisfied.
+type PredicateError struct {
+	Index     int
+	Predicate string
+}
+
+func (err *PredicateError) Error() string {
+	return fmt.Sprintf("predicate %d (%s) is not satisfied", err.Index, err.Predicate)
+}
+
+// opening is the opening (b, r) of a bit commitment g^b · h^r.
+type opening struct {
+	bit bool
+	r   *e.Scalar
+}
+
+// generators are the bases g = g1 and the Pedersen base h, whose discrete logarithm is unknown.
+type generators struct {
+	g, h *e.G1
+}
+
+// Attr returns the expression m_j for the hidden message at index j.
+func Attr(j int) Expr {
+	return Expr{coefficients: map[int]*e.Scalar{j: utils.One()}, constant: new(e.Scalar), text: fmt.Sprintf("m[%d]", j)}
+}
+
+// Int returns the constant expression v.
+func Int(v int64) Expr {
+	return Expr{coefficients: map[int]*e.Scalar{}, constant: intScalar(v), text: strconv.FormatInt(v, 10)}
+}
+
+// Value returns the constant expression of a message, as converted by utils.MessageToScalar.
+func Value(message string) Expr {
+	return Expr{coefficients: map[int]*e.Scalar{}, constant: utils.MessageToScalar(message), text: strconv.Quote(message)}
+}
+
+// Plus returns the expression x + y.
+func (x Expr) Plus(y Expr) Expr {
+	return x.combine(y, false)
+}
+
+// Minus returns the expression x - y.
+func (x Expr) Minus(y Expr) Expr {
+	return x.combine(y, true)
+}
+
+// Times returns the expression k · x.
+func (x Expr) Times(k int64) Expr {
+	scalar := intScalar(k)
+	result := Expr{coefficients: make(map[int]*e.Scalar, len(x.coefficients)), constant: new(e.Scalar), text: strconv.FormatInt(k, 10) + "·" + x.operand()}
+	for j, a := range x.coefficients {
+		result.coefficients[j] = new(e.Scalar)
+		result.coefficients[j].Mul(a, scalar)
+	}
+	result.constant.Mul(x.constant, scalar)
+	return result
+}
+
+// String returns the expression as written.
+func (x Expr) String() string {
+	return x.text
+}
+
+// combine returns x + y, or x - y if subtract is set.
+func (x Expr) combine(y Expr, subtract bool) Expr {
+	op, operand := " + ", y.text
+	if subtract {
+		op, operand = " - ", y.operand()
+	}
+	result := Expr{coefficients: make(map[int]*e.Scalar), constant: new(e.Scalar), text: x.text + op + operand, compound: true}
+	for j, a := range x.coefficients {
+		result.coefficients[j] = new(e.Scalar)
+		result.coefficients[j].Set(a)
+	}
+	for j, a := range y.coefficients {
+		if result.coefficients[j] == nil {
+			result.coefficients[j] = new(e.Scalar)
+		}
+		if subtract {
+			result.coefficients[j].Sub(result.coefficients[j], a)
+		} else {
+			result.coefficients[j].Add(result.coefficients[j], a)
+		}
+	}
+	if subtract {
+		result.constant.Sub(x.constant, y.constant)
+	} else {
+		result.constant.Add(x.constant, y.constant)
+	}
+	return result
+}
+
+// operand returns the text of the expression, parenthesized if it is a sum.
+func (x Expr) operand() string {
+	if x.compound {
+		return "(" + x.text + ")"
+	}
+	return x.text
+}
+
+// linear proves left = right, i.e. Σ a_j · m_j = b for the normalized coefficients.
+type linear struct {
+	left, right Expr
+}
+
+// Equal returns the predicate left = right over the hidden messages, for example
+// Equal(Attr(3).Plus(Attr(4)), Attr(5)). Every attribute used must be hidden.
+func Equal(left, right Expr) Predicate {
+	return linear{left: left, right: right}
+}
+
+func (p linear) String() string {
+	return p.left.String() + " = " + p.right.String()
+}
+
+func (p linear) attributes() []int {
+	var indexes []int
+	x := p.normalized()
+	for _, j := range sortedKeys(x.coefficients) {
+		if x.coefficients[j].IsZero() == 0 {
+			indexes = append(indexes, j)
+		}
+	}
+	return indexes
+}
+
+// normalized returns left - right, whose value must be zero.
+func (p linear) normalized() Expr {
+	return p.left.Minus(p.right)
+}
+
+func (p linear) witness(g generators, prover *proof.Prover) ([]*e.G1, []opening, sigma.Witness, error) {
+	w := sigma.Witness{}
+	for _, j := range p.attributes() {
+		w[attributeVar(j)] = prover.Message(j)
+	}
+	return nil, nil, w, nil
+}
+
+// statement proves g^{-b} = ∏ (g^{a_j})^{m_j}, where Σ a_j · m_j + b = 0.
+func (p linear) statement(g generators, publics []*e.G1, bits []*e.G1) (*sigma.Statement, error) {
+	if len(publics) != 0 || len(bits) != 0 {
+		return nil, errors.New("linear predicate has no public values")
+	}
+	x := p.normalized()
+	var terms []sigma.Term
+	for _, j := range p.attributes() {
+		base := new(e.G1)
+		base.ScalarMult(x.coefficients[j], g.g)
+		terms = append(terms, sigma.Term{Base: sigma.G1(base), Var: attributeVar(j)})
+	}
+	if len(terms) == 0 {
+		return nil, errors.New("linear predicate does not refer to a hidden message")
+	}
+	y := new(e.G1)
+	y.ScalarMult(utils.Neg(x.constant), g.g)
+	return sigma.NewStatement().Relation(sigma.G1(y), terms...), nil
+}
+
+// notEqual proves m_j ≠ v.
+type notEqual struct {
+	index int
+	value string
+}
+
+// NotEqual returns the predicate m_j ≠ value for the hidden message at index j.
+func NotEqual(j int, value string) Predicate {
+	return notEqual{index: j, value: value}
+}
+
+func (p notEqual) String() string {
+	return fmt.Sprintf("m[%d] ≠ %s", p.index, strconv.Quote(p.value))
+}
+
+func (p notEqual) attributes() []int {
+	return []int{p.index}
+}
+
+// witness commits C = g^{m} · h^{r} and computes η = 1/(m - v) and ζ = -r·η.
+func (p notEqual) witness(g generators, prover *proof.Prover) ([]*e.G1, []opening, sigma.Witness, error) {
+	m := prover.Message(p.index)
+	delta := new(e.Scalar)
+	delta.Sub(m, utils.MessageToScalar(p.value))
+	if delta.IsZero() == 1 {
+		return nil, nil, nil, errUnsatisfied
+	}
+	r, err := utils.RandomScalar()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	eta := new(e.Scalar)
+	eta.Inv(delta)
+	zeta := new(e.Scalar)
+	zeta.Mul(&r, eta)
+	zeta.Neg()
+	c := utils.MultiExpG1([]*e.G1{g.g, g.h}, []*e.Scalar{m, &r})
+	return []*e.G1{c}, nil, sigma.Witness{attributeVar(p.index): m, "r": &r, "eta": eta, "zeta": zeta}, nil
+}
+
+// statement proves C = g^{m} · h^{r} and g = (C · g^{-v})^{η} · h^{ζ}. If m = v then
+// C · g^{-v} = h^{r} and the second relation would give log_h g, so m ≠ v.
+func (p notEqual) statement(g generators, publics []*e.G1, bits []*e.G1) (*sigma.Statement, error) {
+	if len(publics) != 1 || len(bits) != 0 || publics[0] == nil {
+		return nil, errors.New("inequality predicate needs one commitment")
+	}
+	c := publics[0]
+	shifted := new(e.G1)
+	shifted.ScalarMult(utils.Neg(utils.MessageToScalar(p.value)), g.g)
+	shifted.Add(shifted, c)
+	return sigma.NewStatement().
+		Relation(sigma.G1(c), sigma.Term{Base: sigma.G1(g.g), Var: attributeVar(p.index)}, sigma.Term{Base: sigma.G1(g.h), Var: "r"}).
+		Relation(sigma.G1(g.g), sigma.Term{Base: sigma.G1(shifted), Var: "eta"}, sigma.Term{Base: sigma.G1(g.h), Var: "zeta"}), nil
+}
+
+// multipleOf proves m_j = k · q for an integer q < 2^64.
+type multipleOf struct {
+	index int
+	k     uint64
+}
+
+// MultipleOf returns the predicate "m_j is a multiple of k" for a hidden message encoded with
+// EncodeInt. The quotient is proved to be below 2^64 with one bit commitment per bit, so the
+// predicate holds over the integers and not only modulo the group order.
+func MultipleOf(j int, k uint64) Predicate {
+	return multipleOf{index: j, k: k}
+}
+
+func (p multipleOf) String() string {
+	return fmt.Sprintf("m[%d] multiple of %d", p.index, p.k)
+}
+
+func (p multipleOf) attributes() []int {
+	return []int{p.index}
+}
+
+// witness commits to the bits of q = m/k as C_i = g^{q_i} · h^{r_i}.
+func (p multipleOf) witness(g generators, prover *proof.Prover) ([]*e.G1, []opening, sigma.Witness, error) {
+	if p.k == 0 {
+		return nil, nil, nil, errors.New("modulus must be positive")
+	}
+	m := prover.Message(p.index)
+	value := new(big.Int).SetBytes(utils.ScalarToBytes(m))
+	q, remainder := new(big.Int).QuoRem(value, new(big.Int).SetUint64(p.k), new(big.Int))
+	if remainder.Sign() != 0 || q.BitLen() > quotientBits {
+		return nil, nil, nil, errUnsatisfied
+	}
+	randoms, err := utils.RandomScalars(quotientBits)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	bits := make([]opening, quotientBits)
+	total := new(e.Scalar)
+	for i := quotientBits - 1; i >= 0; i-- {
+		bits[i] = opening{bit: q.Bit(i) == 1, r: randoms[i]}
+		total.Add(total, total)
+		total.Add(total, randoms[i])
+	}
+	return nil, bits, sigma.Witness{attributeVar(p.index): m, "r": total}, nil
+}
+
+// statement proves ∏ C_i^{2^i} = (g^{1/k})^{m} · h^{r}, so that g^{m/k} = g^{q} for the committed
+// q = Σ 2^i · q_i. That every C_i commits to a bit is proved separately.
+func (p multipleOf) statement(g generators, publics []*e.G1, bits []*e.G1) (*sigma.Statement, error) {
+	if p.k == 0 {
+		return nil, errors.New("modulus must be positive")
+	}
+	if len(publics) != 0 || len(bits) != quotientBits {
+		return nil, errors.New("multiple predicate needs one commitment per quotient bit")
+	}
+	y := new(e.G1)
+	y.SetIdentity()
+	for i := quotientBits - 1; i >= 0; i-- {
+		if bits[i] == nil {
+			return nil, errors.New("multiple predicate has a missing bit commitment")
+		}
+		y.Add(y, y)
+		y.Add(y, bits[i])
+	}
+	inverse := new(e.Scalar)
+	inverse.SetUint64(p.k)
+	inverse.Inv(inverse)
+	base := new(e.G1)
+	base.ScalarMult(inverse, g.g)
+	return sigma.NewStatement().Relation(sigma.G1(y),
+		sigma.Term{Base: sigma.G1(base), Var: attributeVar(p.index)},
+		sigma.Term{Base: sigma.G1(g.h), Var: "r"}), nil
+}
+
+// EncodeInt encodes an integer as a message whose scalar under utils.MessageToScalar is the integer
+// itself, so that linear predicates and MultipleOf apply to its numeric value.
+func EncodeInt(v uint64) string {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+	return string(buf[:])
+}
+
+// DecodeInt decodes a message produced by EncodeInt.
+func DecodeInt(message string) (uint64, error) {
+	if len(message) != 8 {
+		return 0, errors.New("message is not an encoded integer")
+	}
+	return binary.BigEndian.Uint64([]byte(message)), nil
+}
+
+// attributeVar names the witness of the hidden message j; predicates share it with the signature proof.
+func attributeVar(j int) string {
+	return "m" + strconv.Itoa(j)
+}
+
+// intScalar converts a signed integer to a scalar.
+func intScalar(v int64) *e.Scalar {
+	s := new(e.Scalar)
+	if v < 0 {
+		s.SetUint64(uint64(-v))
+		s.Neg()
+		return s
+	}
+	s.SetUint64(uint64(v))
+	return s
+}
+
+// sortedKeys returns the indexes of a coefficient map in increasing order.
+func sortedKeys(coefficients map[int]*e.Scalar) []int {
+	keys := make([]int, 0, len(coefficients))
+	for j := range coefficients {
+		keys = append(keys, j)
+	}
+	sort.Ints(keys)
+	return keys
+}
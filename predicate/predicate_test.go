@@ -0,0 +1,110 @@
+package predicate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aniagut/msc-bbs-plus-plus/keygen"
+	"github.com/aniagut/msc-bbs-plus-plus/sign"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPresentVerify tests a presentation with linear, inequality and multiple-of predicates.
+func TestPresentVerify(t *testing.T) {
+	result, err := keygen.KeyGen(6)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	publicParams := result.PublicParameters
+	messages := []string{"alice", "user-42", "student", EncodeInt(30), EncodeInt(12), EncodeInt(42)}
+	signature, err := sign.Sign(publicParams, result.SigningKey, messages)
+	assert.NoError(t, err, "Sign should not return an error")
+	nonce := []byte("nonce")
+	revealed := map[int]string{2: "student"}
+	predicates := []Predicate{
+		Equal(Attr(3).Plus(Attr(4)), Attr(5)),
+		Equal(Attr(5).Minus(Attr(3).Times(2)), Int(-18)),
+		NotEqual(1, "user-7"),
+		MultipleOf(3, 5),
+	}
+
+	presentation, err := Present(publicParams, signature, messages, []int{2}, predicates, nonce)
+	assert.NoError(t, err, "Present should not return an error")
+
+	isValid, err := Verify(publicParams, result.VerificationKey, presentation, revealed, predicates, nonce)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.True(t, isValid, "Verify should accept a valid presentation")
+
+	isValid, err = Verify(publicParams, result.VerificationKey, presentation, revealed, predicates, []byte("other"))
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.False(t, isValid, "Verify should reject another nonce")
+}
+
+// TestUnsatisfiedPredicate tests that the holder cannot prove false predicates.
+func TestUnsatisfiedPredicate(t *testing.T) {
+	result, err := keygen.KeyGen(3)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	publicParams := result.PublicParameters
+	messages := []string{"user-7", EncodeInt(10), EncodeInt(3)}
+	signature, err := sign.Sign(publicParams, result.SigningKey, messages)
+	assert.NoError(t, err, "Sign should not return an error")
+
+	for i, predicate := range []Predicate{
+		NotEqual(0, "user-7"),
+		Equal(Attr(1), Attr(2)),
+		MultipleOf(1, 3),
+	} {
+		_, err = Present(publicParams, signature, messages, nil, []Predicate{Equal(Attr(1), Int(10)), predicate}, nil)
+		var predicateErr *PredicateError
+		assert.True(t, errors.As(err, &predicateErr), "Present should report the unsatisfied predicate %d", i)
+		assert.Equal(t, 1, predicateErr.Index, "Present should report the index of the predicate")
+	}
+
+	_, err = Present(publicParams, signature, messages, []int{1}, []Predicate{MultipleOf(1, 5)}, nil)
+	assert.Error(t, err, "Present should refuse predicates over disclosed messages")
+}
+
+// TestVerifyReportsFailedPredicate tests that a verifier learns which predicate does not hold.
+func TestVerifyReportsFailedPredicate(t *testing.T) {
+	result, err := keygen.KeyGen(3)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	publicParams := result.PublicParameters
+	messages := []string{"user-7", EncodeInt(10), EncodeInt(3)}
+	signature, err := sign.Sign(publicParams, result.SigningKey, messages)
+	assert.NoError(t, err, "Sign should not return an error")
+	nonce := []byte("nonce")
+
+	presentation, err := Present(publicParams, signature, messages, nil, []Predicate{
+		NotEqual(0, "user-1"),
+		Equal(Attr(1).Minus(Attr(2)), Int(7)),
+	}, nonce)
+	assert.NoError(t, err, "Present should not return an error")
+
+	// The verifier asks for a different relation than the one proved
+	isValid, err := Verify(publicParams, result.VerificationKey, presentation, map[int]string{}, []Predicate{
+		NotEqual(0, "user-1"),
+		Equal(Attr(1).Minus(Attr(2)), Int(8)),
+	}, nonce)
+	assert.False(t, isValid, "Verify should reject a false predicate")
+	var predicateErr *PredicateError
+	assert.True(t, errors.As(err, &predicateErr), "Verify should return a PredicateError")
+	assert.Equal(t, 1, predicateErr.Index, "Verify should report the failing predicate")
+	assert.Equal(t, "m[1] - m[2] = 8", predicateErr.Predicate, "Verify should describe the failing predicate")
+
+	// The excluded value of the proof does not match the verifier's
+	isValid, err = Verify(publicParams, result.VerificationKey, presentation, map[int]string{}, []Predicate{
+		NotEqual(0, "user-2"),
+		Equal(Attr(1).Minus(Attr(2)), Int(7)),
+	}, nonce)
+	assert.False(t, isValid, "Verify should reject another excluded value")
+	assert.True(t, errors.As(err, &predicateErr), "Verify should return a PredicateError")
+	assert.Equal(t, 0, predicateErr.Index, "Verify should report the failing predicate")
+}
+
+// TestEncodeInt tests that encoded integers round-trip.
+func TestEncodeInt(t *testing.T) {
+	value, err := DecodeInt(EncodeInt(1234567890))
+	assert.NoError(t, err, "DecodeInt should not return an error")
+	assert.Equal(t, uint64(1234567890), value, "DecodeInt should return the encoded integer")
+
+	_, err = DecodeInt("abc")
+	assert.Error(t, err, "DecodeInt should reject other messages")
+}
@@ -0,0 +1,289 @@
+package predicate
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aniagut/msc-bbs-plus-plus/models"
+	"github.com/aniagut/msc-bbs-plus-plus/proof"
+	"github.com/aniagut/msc-bbs-plus-plus/sigma"
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	e "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+const (
+	// presentationDomain separates predicate presentation challenges from other hashes.
+	presentationDomain = "BBS++-PREDICATE-PRESENTATION-V1"
+	// bitDomain separates the transcripts of the bit proofs.
+	bitDomain = "BBS++-PREDICATE-BIT-V1"
+	// generatorDomain is the domain separation tag used to derive the Pedersen base h.
+	generatorDomain = "BBS++-PREDICATE-GENERATOR-V1"
+)
+
+// PredicateProof is the proof of one predicate. Its witnesses that are hidden messages are not
+// repeated: the verifier takes their responses from the signature proof, which links the predicate
+// to the signed messages. The commitments are sent so that a failing predicate can be identified.
+type PredicateProof struct {
+	Publics     []*e.G1
+	Bits        []*e.G1
+	Commitments []sigma.Element
+	Responses   []*e.Scalar
+	BitProofs   []sigma.OrProof
+}
+
+// Presentation is a proof of knowledge of a signature together with proofs of predicates over
+// the hidden messages, all bound to the same challenge.
+type Presentation struct {
+	Proof      models.Proof
+	Predicates []PredicateProof
+}
+
+// Present generates a presentation that proves the predicates over the hidden messages.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - signature: The signature on the messages.
+//   - m: The full message vector.
+//   - disclosed: The indexes of the messages revealed to the verifier.
+//   - predicates: The predicates to be proved; they may only refer to hidden messages.
+//   - nonce: A verifier-supplied nonce bound to the presentation.
+//
+// Returns:
+//   - Presentation: The generated presentation.
+//   - error: A *PredicateError if the messages do not satisfy a predicate, or another error if the
+//     proof cannot be generated.
+func Present(publicParams models.PublicParameters, signature models.Signature, m []string, disclosed []int, predicates []Predicate, nonce []byte) (Presentation, error) {
+	// Step 1: Commitment phase of the signature proof
+	prover, err := proof.NewProver(publicParams, signature, m, disclosed, nil)
+	if err != nil {
+		return Presentation{}, err
+	}
+	g := newGenerators(publicParams)
+
+	// Step 2: Commit to every predicate, reusing the blindings of the hidden messages
+	proofs := make([]PredicateProof, len(predicates))
+	provers := make([]*sigma.Prover, len(predicates))
+	statements := make([]*sigma.Statement, len(predicates))
+	openings := make([][]opening, len(predicates))
+	for i, predicate := range predicates {
+		blindings := sigma.Witness{}
+		for _, j := range predicate.attributes() {
+			if prover.Message(j) == nil {
+				return Presentation{}, fmt.Errorf("predicate %d refers to a disclosed message", i)
+			}
+			blindings[attributeVar(j)] = prover.Blinding(j)
+		}
+		publics, bits, w, err := predicate.witness(g, prover)
+		if errors.Is(err, errUnsatisfied) {
+			return Presentation{}, &PredicateError{Index: i, Predicate: predicate.String()}
+		}
+		if err != nil {
+			return Presentation{}, err
+		}
+		commitments := make([]*e.G1, len(bits))
+		for b, bit := range bits {
+			commitments[b] = bitCommitment(g, bit)
+		}
+		statements[i], err = predicate.statement(g, publics, commitments)
+		if err != nil {
+			return Presentation{}, err
+		}
+		provers[i], err = sigma.NewProver(statements[i], w, blindings)
+		if err != nil {
+			return Presentation{}, &PredicateError{Index: i, Predicate: predicate.String()}
+		}
+		proofs[i] = PredicateProof{Publics: publics, Bits: commitments, Commitments: provers[i].Commitments()}
+		openings[i] = bits
+	}
+
+	// Step 3: Derive the common challenge
+	t := sigma.NewTranscript(presentationDomain)
+	t.AppendMessage("proof", prover.Bytes())
+	for i, predicate := range predicates {
+		appendPredicate(t, predicate, statements[i], proofs[i])
+	}
+	t.AppendMessage("nonce", nonce)
+	challenge := t.ChallengeScalar("challenge")
+
+	// Step 4: Respond, keeping only the responses of the predicates' own witnesses
+	for i, predicate := range predicates {
+		responses := provers[i].Respond(challenge)
+		hidden := attributeVars(predicate)
+		for k, name := range statements[i].Vars() {
+			if _, ok := hidden[name]; !ok {
+				proofs[i].Responses = append(proofs[i].Responses, responses.Responses[k])
+			}
+		}
+
+		// Step 5: Prove that every bit commitment opens to 0 or 1
+		for b, bit := range openings[i] {
+			real := 0
+			if bit.bit {
+				real = 1
+			}
+			bitProof, err := sigma.ProveOr(bitTranscript(challenge, i, b), bitStatements(g, proofs[i].Bits[b]), real, sigma.Witness{"r": bit.r})
+			if err != nil {
+				return Presentation{}, err
+			}
+			proofs[i].BitProofs = append(proofs[i].BitProofs, bitProof)
+		}
+	}
+
+	return Presentation{Proof: prover.Respond(challenge), Predicates: proofs}, nil
+}
+
+// Verify checks a predicate presentation.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - verificationKey: The verification key of the issuer.
+//   - presentation: The presentation to be verified.
+//   - revealed: The disclosed messages, indexed by their position in the message vector.
+//   - predicates: The predicates the verifier requires, in the order used by the holder.
+//   - nonce: The nonce the presentation was bound to.
+//
+// Returns:
+//   - boolean: True if the presentation is valid, false otherwise.
+//   - error: A *PredicateError naming the first predicate whose proof fails, or another error if the
+//     presentation is malformed. An invalid signature proof returns false with no error.
+func Verify(publicParams models.PublicParameters, verificationKey models.VerificationKey, presentation Presentation, revealed map[int]string, predicates []Predicate, nonce []byte) (bool, error) {
+	if len(presentation.Predicates) != len(predicates) {
+		return false, errors.New("presentation does not match the predicates")
+	}
+
+	// Step 1: Recompute the signature proof transcript and check the pairing
+	transcript, err := proof.TranscriptBytes(publicParams, presentation.Proof, revealed)
+	if err != nil {
+		return false, err
+	}
+	if !proof.CheckPairing(publicParams, verificationKey, presentation.Proof) {
+		return false, nil
+	}
+	challenge := presentation.Proof.Challenge
+	g := newGenerators(publicParams)
+
+	// Step 2: Check every predicate on its own, so that a failure can be reported
+	statements := make([]*sigma.Statement, len(predicates))
+	for i, predicate := range predicates {
+		predicateProof := presentation.Predicates[i]
+		failed := &PredicateError{Index: i, Predicate: predicate.String()}
+		for _, j := range predicate.attributes() {
+			if presentation.Proof.MHat[j] == nil {
+				return false, fmt.Errorf("predicate %d refers to a disclosed message", i)
+			}
+		}
+		statements[i], err = predicate.statement(g, predicateProof.Publics, predicateProof.Bits)
+		if err != nil {
+			return false, err
+		}
+
+		// Take the responses of hidden messages from the signature proof
+		responses := sigma.Proof{Challenge: challenge}
+		hidden := attributeVars(predicate)
+		next := 0
+		for _, name := range statements[i].Vars() {
+			if j, ok := hidden[name]; ok {
+				responses.Responses = append(responses.Responses, presentation.Proof.MHat[j])
+				continue
+			}
+			if next >= len(predicateProof.Responses) {
+				return false, errors.New("predicate proof is missing responses")
+			}
+			responses.Responses = append(responses.Responses, predicateProof.Responses[next])
+			next++
+		}
+		if next != len(predicateProof.Responses) {
+			return false, errors.New("predicate proof has too many responses")
+		}
+		commitments, err := sigma.Commitments(statements[i], responses)
+		if err != nil {
+			return false, err
+		}
+		if len(commitments) != len(predicateProof.Commitments) {
+			return false, errors.New("predicate proof does not match the statement")
+		}
+		for k := range commitments {
+			if predicateProof.Commitments[k] == nil || !commitments[k].Equal(predicateProof.Commitments[k]) {
+				return false, failed
+			}
+		}
+
+		if len(predicateProof.BitProofs) != len(predicateProof.Bits) {
+			return false, errors.New("predicate proof does not match its bit commitments")
+		}
+		for b, bitProof := range predicateProof.BitProofs {
+			ok, err := sigma.VerifyOr(bitTranscript(challenge, i, b), bitStatements(g, predicateProof.Bits[b]), bitProof)
+			if err != nil {
+				return false, err
+			}
+			if !ok {
+				return false, failed
+			}
+		}
+	}
+
+	// Step 3: Check the common challenge
+	t := sigma.NewTranscript(presentationDomain)
+	t.AppendMessage("proof", transcript)
+	for i, predicate := range predicates {
+		appendPredicate(t, predicate, statements[i], presentation.Predicates[i])
+	}
+	t.AppendMessage("nonce", nonce)
+	return t.ChallengeScalar("challenge").IsEqual(challenge) == 1, nil
+}
+
+// newGenerators returns g1 and the Pedersen base h derived from the public parameters.
+func newGenerators(publicParams models.PublicParameters) generators {
+	return generators{g: publicParams.G1, h: utils.HashToG1(utils.ParametersID(publicParams), generatorDomain)}
+}
+
+// bitCommitment computes g^b · h^r.
+func bitCommitment(g generators, bit opening) *e.G1 {
+	c := new(e.G1)
+	c.ScalarMult(bit.r, g.h)
+	if bit.bit {
+		c.Add(c, g.g)
+	}
+	return c
+}
+
+// bitStatements returns the branches C = h^r and C · g^{-1} = h^r of a bit proof.
+func bitStatements(g generators, c *e.G1) []*sigma.Statement {
+	shifted := new(e.G1)
+	*shifted = *g.g
+	shifted.Neg()
+	shifted.Add(shifted, c)
+	return []*sigma.Statement{
+		sigma.NewStatement().Relation(sigma.G1(c), sigma.Term{Base: sigma.G1(g.h), Var: "r"}),
+		sigma.NewStatement().Relation(sigma.G1(shifted), sigma.Term{Base: sigma.G1(g.h), Var: "r"}),
+	}
+}
+
+// bitTranscript returns the transcript of a bit proof, bound to the presentation challenge.
+func bitTranscript(challenge *e.Scalar, predicate, bit int) *sigma.Transcript {
+	t := sigma.NewTranscript(bitDomain)
+	t.AppendScalar("challenge", challenge)
+	t.AppendUint64("predicate", uint64(predicate))
+	t.AppendUint64("bit", uint64(bit))
+	return t
+}
+
+// appendPredicate appends a predicate, its statement, its bit commitments and its commitments.
+func appendPredicate(t *sigma.Transcript, predicate Predicate, statement *sigma.Statement, predicateProof PredicateProof) {
+	t.AppendMessage("predicate", []byte(predicate.String()))
+	t.AppendStatement(statement)
+	t.AppendUint64("bits", uint64(len(predicateProof.Bits)))
+	for _, bit := range predicateProof.Bits {
+		t.AppendElement("bit", sigma.G1(bit))
+	}
+	t.AppendCommitments(predicateProof.Commitments)
+}
+
+// attributeVars maps the witness names of the hidden messages of a predicate to their indexes.
+func attributeVars(predicate Predicate) map[string]int {
+	vars := make(map[string]int)
+	for _, j := range predicate.attributes() {
+		vars[attributeVar(j)] = j
+	}
+	return vars
+}
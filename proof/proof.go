@@ -0,0 +1,317 @@
+package proof
+
+import (
+	"encoding/binary"
+	"errors"
+	"sort"
+
+	"github.com/aniagut/msc-bbs-plus-plus/models"
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	e "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+// proofDomain separates the challenges of signature proofs from other hashes in the library.
+const proofDomain = "BBS++-PROOF-OF-KNOWLEDGE-V1"
+
+// Prover holds the state of a proof of knowledge of a BBS++ signature between the
+// commitment phase and the response phase.
+//
+// The proof follows the BBS proof of knowledge: with random r1, r2 and r3 = 1/r2 the prover publishes
+//   - D = C^{r2}, Ā = A^{r1·r2}, B̄ = D^{r1} · Ā^{-e}
+//
+// so that e(Ā, X₂) = e(B̄, g₂), and proves knowledge of (r1, e, r3, hidden m_j) such that
+//   - B̄ = D^{r1} · Ā^{-e}
+//   - g1 · ∏_{disclosed} h_i^{m_i} = D^{r3} · ∏_{hidden} h_j^{-m_j}
+//
+// Other protocols can attach statements about hidden messages by reusing Blinding(j) in their
+// own commitments and including their commitments in the challenge.
+type Prover struct {
+	revealed map[int]string
+	hidden   []int
+
+	aBar, bBar, d *e.G1
+	t1, t2        *e.G1
+
+	e, r1, r3                *e.Scalar
+	eTilde, r1Tilde, r3Tilde *e.Scalar
+	m, mTilde                map[int]*e.Scalar
+}
+
+// NewProver runs the commitment phase of a proof of knowledge of a signature.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - signature: The signature on the messages.
+//   - m: The full message vector.
+//   - disclosed: The indexes of the messages revealed to the verifier.
+//   - blindings: Optional blinding values for hidden messages. Supplying the same blinding for
+//     hidden messages in several proofs links their responses; missing ones are sampled at random.
+//
+// Returns:
+//   - Prover: The prover state.
+//   - error: An error if the inputs are inconsistent or randomness generation fails.
+func NewProver(publicParams models.PublicParameters, signature models.Signature, m []string, disclosed []int, blindings map[int]*e.Scalar) (*Prover, error) {
+	if len(m) != len(publicParams.H1) {
+		return nil, errors.New("message vector length does not match h1 length")
+	}
+	revealed := make(map[int]string, len(disclosed))
+	for _, i := range disclosed {
+		if i < 0 || i >= len(m) {
+			return nil, errors.New("disclosed index out of range")
+		}
+		revealed[i] = m[i]
+	}
+
+	p := &Prover{
+		revealed: revealed,
+		m:        make(map[int]*e.Scalar),
+		mTilde:   make(map[int]*e.Scalar),
+		e:        signature.E,
+	}
+	for j := range m {
+		if _, ok := revealed[j]; ok {
+			continue
+		}
+		p.hidden = append(p.hidden, j)
+		p.m[j] = utils.MessageToScalar(m[j])
+		if b, ok := blindings[j]; ok {
+			p.mTilde[j] = b
+			continue
+		}
+		b, err := utils.RandomScalar()
+		if err != nil {
+			return nil, err
+		}
+		p.mTilde[j] = &b
+	}
+
+	// Step 1: Sample r1, r2 and the blinding values for r1, e and r3
+	random, err := utils.RandomScalars(5)
+	if err != nil {
+		return nil, err
+	}
+	r1, r2 := random[0], random[1]
+	p.r1 = r1
+	p.r3 = new(e.Scalar)
+	p.r3.Inv(r2)
+	p.r1Tilde, p.eTilde, p.r3Tilde = random[2], random[3], random[4]
+
+	// Step 2: Compute D = C^{r2}, Ā = A^{r1·r2}, B̄ = D^{r1} · Ā^{-e}
+	c, err := utils.ComputeCommitment(m, publicParams.H1, publicParams.G1)
+	if err != nil {
+		return nil, err
+	}
+	p.d = new(e.G1)
+	p.d.ScalarMult(r2, c)
+
+	r1r2 := new(e.Scalar)
+	r1r2.Mul(r1, r2)
+	p.aBar = new(e.G1)
+	p.aBar.ScalarMult(r1r2, signature.A)
+
+	p.bBar = utils.MultiExpG1([]*e.G1{p.d, p.aBar}, []*e.Scalar{r1, utils.Neg(signature.E)})
+
+	// Step 3: Commit T1 = D^{r̃1} · Ā^{-ẽ}
+	p.t1 = utils.MultiExpG1([]*e.G1{p.d, p.aBar}, []*e.Scalar{p.r1Tilde, utils.Neg(p.eTilde)})
+
+	// Step 4: Commit T2 = D^{r̃3} · ∏_{hidden} h_j^{-m̃_j}
+	points := []*e.G1{p.d}
+	scalars := []*e.Scalar{p.r3Tilde}
+	for _, j := range p.hidden {
+		points = append(points, &publicParams.H1[j])
+		scalars = append(scalars, utils.Neg(p.mTilde[j]))
+	}
+	p.t2 = utils.MultiExpG1(points, scalars)
+
+	return p, nil
+}
+
+// Blinding returns the blinding value m̃_j used for the hidden message j, or nil if j is disclosed.
+func (p *Prover) Blinding(j int) *e.Scalar {
+	return p.mTilde[j]
+}
+
+// Message returns the scalar of the hidden message j, or nil if j is disclosed.
+func (p *Prover) Message(j int) *e.Scalar {
+	return p.m[j]
+}
+
+// Randomized returns the randomized signature (Ā, B̄), for protocols that replace the pairing
+// check e(Ā, X₂) = e(B̄, g₂) with a statement about a hidden verification key.
+func (p *Prover) Randomized() (*e.G1, *e.G1) {
+	return p.aBar, p.bBar
+}
+
+// Commitments returns the commitments T1 and T2, for proof variants that send T2 instead of
+// the responses it is recomputed from.
+func (p *Prover) Commitments() (*e.G1, *e.G1) {
+	return p.t1, p.t2
+}
+
+// Bytes returns the transcript of the commitment phase that must be hashed into the challenge.
+func (p *Prover) Bytes() []byte {
+	return Transcript(p.revealed, p.aBar, p.bBar, p.d, p.t1, p.t2)
+}
+
+// Respond computes the responses for the given challenge and returns the proof.
+func (p *Prover) Respond(challenge *e.Scalar) models.Proof {
+	mHat := make(map[int]*e.Scalar, len(p.hidden))
+	for _, j := range p.hidden {
+		mHat[j] = Response(p.mTilde[j], challenge, p.m[j])
+	}
+	return models.Proof{
+		ABar:      p.aBar,
+		BBar:      p.bBar,
+		D:         p.d,
+		Challenge: challenge,
+		EHat:      Response(p.eTilde, challenge, p.e),
+		R1Hat:     Response(p.r1Tilde, challenge, p.r1),
+		R3Hat:     Response(p.r3Tilde, challenge, p.r3),
+		MHat:      mHat,
+	}
+}
+
+// Response computes the Schnorr response blinding + challenge · witness.
+func Response(blinding, challenge, witness *e.Scalar) *e.Scalar {
+	s := new(e.Scalar)
+	s.Mul(challenge, witness)
+	s.Add(s, blinding)
+	return s
+}
+
+// Challenge derives the Fiat–Shamir challenge of a signature proof from its transcript and a nonce.
+func Challenge(transcript []byte, nonce []byte) *e.Scalar {
+	return utils.HashToScalar([]byte(proofDomain), transcript, nonce)
+}
+
+// Prove generates a proof of knowledge of a signature on m that discloses the messages in disclosed.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - signature: The signature on the messages.
+//   - m: The full message vector.
+//   - disclosed: The indexes of the messages revealed to the verifier.
+//   - nonce: A verifier-supplied nonce (or presentation context) bound to the proof.
+//
+// Returns:
+//   - proof: The generated proof.
+//   - error: An error if the proof cannot be generated.
+func Prove(publicParams models.PublicParameters, signature models.Signature, m []string, disclosed []int, nonce []byte) (models.Proof, error) {
+	prover, err := NewProver(publicParams, signature, m, disclosed, nil)
+	if err != nil {
+		return models.Proof{}, err
+	}
+	return prover.Respond(Challenge(prover.Bytes(), nonce)), nil
+}
+
+// Verify checks a proof of knowledge of a signature on a message vector with the given disclosed messages.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - verificationKey: The verification key of the issuer.
+//   - proof: The proof to be verified.
+//   - revealed: The disclosed messages, indexed by their position in the message vector.
+//   - nonce: The nonce the proof was bound to.
+//
+// Returns:
+//   - boolean: True if the proof is valid, false otherwise.
+//   - error: An error if the proof is malformed.
+func Verify(publicParams models.PublicParameters, verificationKey models.VerificationKey, proof models.Proof, revealed map[int]string, nonce []byte) (bool, error) {
+	transcript, err := TranscriptBytes(publicParams, proof, revealed)
+	if err != nil {
+		return false, err
+	}
+	if !CheckPairing(publicParams, verificationKey, proof) {
+		return false, nil
+	}
+	return Challenge(transcript, nonce).IsEqual(proof.Challenge) == 1, nil
+}
+
+// CheckPairing checks that Ā ≠ 1 and e(Ā, X₂) = e(B̄, g₂).
+func CheckPairing(publicParams models.PublicParameters, verificationKey models.VerificationKey, proof models.Proof) bool {
+	if proof.ABar.IsIdentity() {
+		return false
+	}
+	e1 := e.Pair(proof.ABar, verificationKey.X2)
+	e2 := e.Pair(proof.BBar, publicParams.G2)
+	return e1.IsEqual(e2)
+}
+
+// TranscriptBytes recomputes the commitments of a proof from its responses and returns the
+// transcript that the prover hashed into the challenge. Protocols extending the proof hash
+// this transcript together with their own commitments.
+func TranscriptBytes(publicParams models.PublicParameters, proof models.Proof, revealed map[int]string) ([]byte, error) {
+	if proof.ABar == nil || proof.BBar == nil || proof.D == nil || proof.Challenge == nil ||
+		proof.EHat == nil || proof.R1Hat == nil || proof.R3Hat == nil {
+		return nil, errors.New("proof is missing components")
+	}
+	l := len(publicParams.H1)
+	if len(revealed)+len(proof.MHat) != l {
+		return nil, errors.New("number of disclosed and hidden messages does not match h1 length")
+	}
+	for i := range revealed {
+		if i < 0 || i >= l {
+			return nil, errors.New("disclosed index out of range")
+		}
+		if _, ok := proof.MHat[i]; ok {
+			return nil, errors.New("message is both disclosed and hidden")
+		}
+	}
+
+	negC := utils.Neg(proof.Challenge)
+
+	// Step 1: Recompute T1 = D^{r̂1} · Ā^{-ê} · B̄^{-c}
+	t1 := utils.MultiExpG1([]*e.G1{proof.D, proof.ABar, proof.BBar}, []*e.Scalar{proof.R1Hat, utils.Neg(proof.EHat), negC})
+
+	// Step 2: Recompute T2 = D^{r̂3} · ∏_{hidden} h_j^{-m̂_j} · (g1 · ∏_{disclosed} h_i^{m_i})^{-c}
+	points := []*e.G1{proof.D}
+	scalars := []*e.Scalar{proof.R3Hat}
+	cDisclosed := new(e.G1)
+	*cDisclosed = *publicParams.G1
+	for j := 0; j < l; j++ {
+		if message, ok := revealed[j]; ok {
+			term := new(e.G1)
+			term.ScalarMult(utils.MessageToScalar(message), &publicParams.H1[j])
+			cDisclosed.Add(cDisclosed, term)
+			continue
+		}
+		mHat, ok := proof.MHat[j]
+		if !ok || mHat == nil {
+			return nil, errors.New("missing response for hidden message")
+		}
+		points = append(points, &publicParams.H1[j])
+		scalars = append(scalars, utils.Neg(mHat))
+	}
+	points = append(points, cDisclosed)
+	scalars = append(scalars, negC)
+	t2 := utils.MultiExpG1(points, scalars)
+
+	return Transcript(revealed, proof.ABar, proof.BBar, proof.D, t1, t2), nil
+}
+
+// Transcript serializes the disclosed messages and the group elements of a proof. Protocols that
+// compute the commitments themselves, for example when a hidden message is held by another device,
+// use it to produce the same transcript as Prover.Bytes.
+func Transcript(revealed map[int]string, aBar, bBar, d, t1, t2 *e.G1) []byte {
+	indexes := make([]int, 0, len(revealed))
+	for i := range revealed {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+
+	var out []byte
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(len(indexes)))
+	out = append(out, buf[:]...)
+	for _, i := range indexes {
+		binary.BigEndian.PutUint64(buf[:], uint64(i))
+		out = append(out, buf[:]...)
+		binary.BigEndian.PutUint64(buf[:], uint64(len(revealed[i])))
+		out = append(out, buf[:]...)
+		out = append(out, utils.SerializeString(revealed[i])...)
+	}
+	for _, point := range []*e.G1{aBar, bBar, d, t1, t2} {
+		out = append(out, point.BytesCompressed()...)
+	}
+	return out
+}
@@ -0,0 +1,88 @@
+package proof
+
+import (
+	"testing"
+
+	"github.com/aniagut/msc-bbs-plus-plus/keygen"
+	"github.com/aniagut/msc-bbs-plus-plus/models"
+	"github.com/aniagut/msc-bbs-plus-plus/sign"
+	"github.com/stretchr/testify/assert"
+)
+
+// setup generates keys and a signature on a 5-message vector.
+func setup(t *testing.T) (models.KeyGenResult, []string, models.Signature) {
+	result, err := keygen.KeyGen(5)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	messages := []string{"alice", "1990-01-01", "student", "secret", "42"}
+	signature, err := sign.Sign(result.PublicParameters, result.SigningKey, messages)
+	assert.NoError(t, err, "Sign should not return an error")
+	return result, messages, signature
+}
+
+// TestProveVerify tests that a valid proof with selective disclosure is accepted.
+func TestProveVerify(t *testing.T) {
+	result, messages, signature := setup(t)
+	nonce := []byte("nonce")
+
+	proof, err := Prove(result.PublicParameters, signature, messages, []int{0, 2}, nonce)
+	assert.NoError(t, err, "Prove should not return an error")
+	assert.Len(t, proof.MHat, 3, "Proof should contain a response for every hidden message")
+
+	revealed := map[int]string{0: "alice", 2: "student"}
+	isValid, err := Verify(result.PublicParameters, result.VerificationKey, proof, revealed, nonce)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.True(t, isValid, "Verify should accept a valid proof")
+}
+
+// TestVerifyWrongDisclosure tests that a proof is rejected for different disclosed messages.
+func TestVerifyWrongDisclosure(t *testing.T) {
+	result, messages, signature := setup(t)
+	nonce := []byte("nonce")
+
+	proof, err := Prove(result.PublicParameters, signature, messages, []int{0, 2}, nonce)
+	assert.NoError(t, err, "Prove should not return an error")
+
+	revealed := map[int]string{0: "mallory", 2: "student"}
+	isValid, err := Verify(result.PublicParameters, result.VerificationKey, proof, revealed, nonce)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.False(t, isValid, "Verify should reject a proof for a different disclosed message")
+}
+
+// TestVerifyWrongNonce tests that a proof cannot be replayed under a different nonce.
+func TestVerifyWrongNonce(t *testing.T) {
+	result, messages, signature := setup(t)
+
+	proof, err := Prove(result.PublicParameters, signature, messages, []int{1}, []byte("nonce-1"))
+	assert.NoError(t, err, "Prove should not return an error")
+
+	isValid, err := Verify(result.PublicParameters, result.VerificationKey, proof, map[int]string{1: "1990-01-01"}, []byte("nonce-2"))
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.False(t, isValid, "Verify should reject a proof bound to another nonce")
+}
+
+// TestVerifyWrongKey tests that a proof is rejected under another issuer key.
+func TestVerifyWrongKey(t *testing.T) {
+	result, messages, signature := setup(t)
+	other, err := keygen.KeyGen(5)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	nonce := []byte("nonce")
+
+	proof, err := Prove(result.PublicParameters, signature, messages, nil, nonce)
+	assert.NoError(t, err, "Prove should not return an error")
+
+	isValid, err := Verify(result.PublicParameters, other.VerificationKey, proof, map[int]string{}, nonce)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.False(t, isValid, "Verify should reject a proof under another verification key")
+}
+
+// TestVerifyMalformed tests that proofs with an inconsistent message layout return an error.
+func TestVerifyMalformed(t *testing.T) {
+	result, messages, signature := setup(t)
+	nonce := []byte("nonce")
+
+	proof, err := Prove(result.PublicParameters, signature, messages, []int{0}, nonce)
+	assert.NoError(t, err, "Prove should not return an error")
+
+	_, err = Verify(result.PublicParameters, result.VerificationKey, proof, map[int]string{0: "alice", 1: "1990-01-01"}, nonce)
+	assert.Error(t, err, "Verify should return an error when a hidden message is also disclosed")
+}
@@ -0,0 +1,329 @@
+package ps
+
+import (
+	"encoding/binary"
+	"errors"
+	"sort"
+
+	"github.com/aniagut/msc-bbs-plus-plus/proof"
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	e "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+// showDomain separates the challenges of PS presentations from other hashes in the library.
+const showDomain = "PS-SHOW-V1"
+
+// SecretKey is a Pointcheval–Sanders secret key (x, y_1, ..., y_l).
+type SecretKey struct {
+	X *e.Scalar
+	Y []*e.Scalar
+}
+
+// PublicKey is a Pointcheval–Sanders public key (g̃, X̃ = g̃^x, Ỹ_i = g̃^{y_i}).
+type PublicKey struct {
+	G2 *e.G2
+	X2 *e.G2
+	Y2 []e.G2
+}
+
+// Signature is a Pointcheval–Sanders signature (σ1, σ2) with σ2 = σ1^{x + Σ y_i·m_i}.
+type Signature struct {
+	Sigma1 *e.G1
+	Sigma2 *e.G1
+}
+
+// KeyGenResult holds the key material of the PS signature scheme.
+type KeyGenResult struct {
+	SecretKey SecretKey
+	PublicKey PublicKey
+}
+
+// ShowProof is an unlinkable presentation of a PS signature: a randomized and blinded signature
+// together with a proof of knowledge of the blinding factor t and the hidden messages in G_T.
+type ShowProof struct {
+	Signature Signature
+	Challenge *e.Scalar
+	THat      *e.Scalar
+	MHat      map[int]*e.Scalar
+}
+
+// KeyGen generates the key material for the PS signature scheme.
+//
+// Parameters:
+//   - l - length of the messages vector
+//
+// Returns:
+//   - KeyGenResult: A struct containing the keys for signing and verifying messages.
+//   - error: An error if key generation fails.
+func KeyGen(l int) (KeyGenResult, error) {
+	// 1. Select generator g̃ ∈ G2
+	g2 := e.G2Generator()
+
+	// 2. Select random x, y_1..y_l ∈ Zp*
+	scalars, err := utils.RandomScalars(l + 1)
+	if err != nil {
+		return KeyGenResult{}, err
+	}
+	x, y := scalars[0], scalars[1:]
+
+	// 3. Compute X̃ = g̃^x and Ỹ_i = g̃^{y_i}
+	X2 := new(e.G2)
+	X2.ScalarMult(x, g2)
+	Y2 := make([]e.G2, l)
+	for i := range y {
+		Y2[i].ScalarMult(y[i], g2)
+	}
+
+	return KeyGenResult{
+		SecretKey: SecretKey{X: x, Y: y},
+		PublicKey: PublicKey{G2: g2, X2: X2, Y2: Y2},
+	}, nil
+}
+
+// Sign generates a PS signature for a given message vector.
+//
+// Parameters:
+//   - secretKey: The key used for signing the messages.
+//   - m: The messages to be signed.
+//
+// Returns:
+//   - signature: The generated signature.
+//   - error: An error if the signing process fails.
+func Sign(secretKey SecretKey, m []string) (Signature, error) {
+	if len(m) != len(secretKey.Y) {
+		return Signature{}, errors.New("message vector length does not match the key length")
+	}
+
+	// Step 1: Select random σ1 = g1^r ≠ 1
+	r, err := utils.RandomScalar()
+	if err != nil {
+		return Signature{}, err
+	}
+	sigma1 := new(e.G1)
+	sigma1.ScalarMult(&r, e.G1Generator())
+
+	// Step 2: Compute σ2 = σ1^{x + Σ y_i·m_i}
+	exponent := new(e.Scalar)
+	exponent.Set(secretKey.X)
+	term := new(e.Scalar)
+	for i, message := range m {
+		term.Mul(secretKey.Y[i], utils.MessageToScalar(message))
+		exponent.Add(exponent, term)
+	}
+	sigma2 := new(e.G1)
+	sigma2.ScalarMult(exponent, sigma1)
+
+	return Signature{Sigma1: sigma1, Sigma2: sigma2}, nil
+}
+
+// Verify checks the validity of a PS signature: σ1 ≠ 1 and e(σ1, X̃ · ∏ Ỹ_i^{m_i}) = e(σ2, g̃).
+//
+// Parameters:
+//   - publicKey: The public key of the signer.
+//   - m: The messages to be verified.
+//   - signature: The signature to be verified.
+//
+// Returns:
+//   - boolean: True if the signature is valid, false otherwise.
+//   - error: An error if the verification process fails.
+func Verify(publicKey PublicKey, m []string, signature Signature) (bool, error) {
+	if len(m) != len(publicKey.Y2) {
+		return false, errors.New("message vector length does not match the key length")
+	}
+	if signature.Sigma1 == nil || signature.Sigma2 == nil {
+		return false, errors.New("signature is missing components")
+	}
+	if signature.Sigma1.IsIdentity() {
+		return false, nil
+	}
+
+	q := new(e.G2)
+	*q = *publicKey.X2
+	term := new(e.G2)
+	for i, message := range m {
+		term.ScalarMult(utils.MessageToScalar(message), &publicKey.Y2[i])
+		q.Add(q, term)
+	}
+	e1 := e.Pair(signature.Sigma1, q)
+	e2 := e.Pair(signature.Sigma2, publicKey.G2)
+	return e1.IsEqual(e2), nil
+}
+
+// Randomize returns a fresh, unlinkable signature (σ1^r, σ2^r) on the same messages.
+func Randomize(signature Signature) (Signature, error) {
+	r, err := utils.RandomScalar()
+	if err != nil {
+		return Signature{}, err
+	}
+	sigma1 := new(e.G1)
+	sigma1.ScalarMult(&r, signature.Sigma1)
+	sigma2 := new(e.G1)
+	sigma2.ScalarMult(&r, signature.Sigma2)
+	return Signature{Sigma1: sigma1, Sigma2: sigma2}, nil
+}
+
+// Show generates an unlinkable presentation of a signature that discloses the messages in disclosed.
+// The signature is randomized and blinded as (σ1^r, (σ2 · σ1^t)^r), so that
+//
+//	e(σ2', g̃) / e(σ1', X̃ · ∏_{disclosed} Ỹ_i^{m_i}) = e(σ1', g̃^t · ∏_{hidden} Ỹ_j^{m_j})
+//
+// and the holder proves knowledge of t and the hidden messages.
+//
+// Parameters:
+//   - publicKey: The public key of the signer.
+//   - signature: The signature on the messages.
+//   - m: The full message vector.
+//   - disclosed: The indexes of the messages revealed to the verifier.
+//   - nonce: A verifier-supplied nonce bound to the presentation.
+//
+// Returns:
+//   - ShowProof: The generated presentation.
+//   - error: An error if the presentation cannot be generated.
+func Show(publicKey PublicKey, signature Signature, m []string, disclosed []int, nonce []byte) (ShowProof, error) {
+	if len(m) != len(publicKey.Y2) {
+		return ShowProof{}, errors.New("message vector length does not match the key length")
+	}
+	revealed := make(map[int]string, len(disclosed))
+	for _, i := range disclosed {
+		if i < 0 || i >= len(m) {
+			return ShowProof{}, errors.New("disclosed index out of range")
+		}
+		revealed[i] = m[i]
+	}
+
+	// Step 1: Randomize and blind the signature
+	random, err := utils.RandomScalars(3)
+	if err != nil {
+		return ShowProof{}, err
+	}
+	r, t, tTilde := random[0], random[1], random[2]
+	sigma1 := new(e.G1)
+	sigma1.ScalarMult(r, signature.Sigma1)
+	blinded := new(e.G1)
+	blinded.ScalarMult(t, signature.Sigma1)
+	blinded.Add(blinded, signature.Sigma2)
+	sigma2 := new(e.G1)
+	sigma2.ScalarMult(r, blinded)
+	randomized := Signature{Sigma1: sigma1, Sigma2: sigma2}
+
+	// Step 2: Commit K = e(σ1', g̃^{t̃} · ∏_{hidden} Ỹ_j^{m̃_j})
+	w := new(e.G2)
+	w.ScalarMult(tTilde, publicKey.G2)
+	mTilde := make(map[int]*e.Scalar)
+	term := new(e.G2)
+	for j := range m {
+		if _, ok := revealed[j]; ok {
+			continue
+		}
+		b, err := utils.RandomScalar()
+		if err != nil {
+			return ShowProof{}, err
+		}
+		mTilde[j] = &b
+		term.ScalarMult(&b, &publicKey.Y2[j])
+		w.Add(w, term)
+	}
+	k := e.Pair(sigma1, w)
+
+	// Step 3: Compute the challenge and the responses
+	challenge, err := showChallenge(publicKey, randomized, revealed, k, nonce)
+	if err != nil {
+		return ShowProof{}, err
+	}
+	mHat := make(map[int]*e.Scalar, len(mTilde))
+	for j, b := range mTilde {
+		mHat[j] = proof.Response(b, challenge, utils.MessageToScalar(m[j]))
+	}
+	return ShowProof{
+		Signature: randomized,
+		Challenge: challenge,
+		THat:      proof.Response(tTilde, challenge, t),
+		MHat:      mHat,
+	}, nil
+}
+
+// VerifyShow checks an unlinkable presentation of a PS signature.
+//
+// Parameters:
+//   - publicKey: The public key of the signer.
+//   - proof: The presentation to be verified.
+//   - revealed: The disclosed messages, indexed by their position in the message vector.
+//   - nonce: The nonce the presentation was bound to.
+//
+// Returns:
+//   - boolean: True if the presentation is valid, false otherwise.
+//   - error: An error if the presentation is malformed.
+func VerifyShow(publicKey PublicKey, proof ShowProof, revealed map[int]string, nonce []byte) (bool, error) {
+	l := len(publicKey.Y2)
+	if proof.Signature.Sigma1 == nil || proof.Signature.Sigma2 == nil || proof.Challenge == nil || proof.THat == nil {
+		return false, errors.New("presentation is missing components")
+	}
+	if len(revealed)+len(proof.MHat) != l {
+		return false, errors.New("number of disclosed and hidden messages does not match the key length")
+	}
+	if proof.Signature.Sigma1.IsIdentity() {
+		return false, nil
+	}
+
+	// Recompute K = e(σ1', g̃^{t̂} · ∏_{hidden} Ỹ_j^{m̂_j} · (X̃ · ∏_{disclosed} Ỹ_i^{m_i})^c) · e(σ2'^{-c}, g̃)
+	w := new(e.G2)
+	w.ScalarMult(proof.THat, publicKey.G2)
+	v := new(e.G2)
+	*v = *publicKey.X2
+	term := new(e.G2)
+	for j := 0; j < l; j++ {
+		if message, ok := revealed[j]; ok {
+			if _, hidden := proof.MHat[j]; hidden {
+				return false, errors.New("message is both disclosed and hidden")
+			}
+			term.ScalarMult(utils.MessageToScalar(message), &publicKey.Y2[j])
+			v.Add(v, term)
+			continue
+		}
+		mHat, ok := proof.MHat[j]
+		if !ok || mHat == nil {
+			return false, errors.New("missing response for hidden message")
+		}
+		term.ScalarMult(mHat, &publicKey.Y2[j])
+		w.Add(w, term)
+	}
+	v.ScalarMult(proof.Challenge, v)
+	w.Add(w, v)
+
+	sigma2 := new(e.G1)
+	sigma2.ScalarMult(utils.Neg(proof.Challenge), proof.Signature.Sigma2)
+
+	k := e.ProdPair([]*e.G1{proof.Signature.Sigma1, sigma2}, []*e.G2{w, publicKey.G2}, []*e.Scalar{utils.One(), utils.One()})
+
+	challenge, err := showChallenge(publicKey, proof.Signature, revealed, k, nonce)
+	if err != nil {
+		return false, err
+	}
+	return challenge.IsEqual(proof.Challenge) == 1, nil
+}
+
+// showChallenge hashes the public key, the randomized signature, the disclosed messages and the commitment.
+func showChallenge(publicKey PublicKey, signature Signature, revealed map[int]string, k *e.Gt, nonce []byte) (*e.Scalar, error) {
+	kBytes, err := k.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	inputs := [][]byte{
+		[]byte(showDomain),
+		publicKey.X2.BytesCompressed(),
+		signature.Sigma1.BytesCompressed(),
+		signature.Sigma2.BytesCompressed(),
+	}
+	indexes := make([]int, 0, len(revealed))
+	for i := range revealed {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	var buf [8]byte
+	for _, i := range indexes {
+		binary.BigEndian.PutUint64(buf[:], uint64(i))
+		inputs = append(inputs, append([]byte(nil), buf[:]...), utils.SerializeString(revealed[i]))
+	}
+	inputs = append(inputs, kBytes, nonce)
+	return utils.HashToScalar(inputs...), nil
+}
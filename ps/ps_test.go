@@ -0,0 +1,84 @@
+package ps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSignVerify tests that a valid PS signature is accepted and a modified one is rejected.
+func TestSignVerify(t *testing.T) {
+	result, err := KeyGen(3)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	messages := []string{"message1", "message2", "message3"}
+
+	signature, err := Sign(result.SecretKey, messages)
+	assert.NoError(t, err, "Sign should not return an error")
+
+	isValid, err := Verify(result.PublicKey, messages, signature)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.True(t, isValid, "Verify should accept a valid signature")
+
+	isValid, err = Verify(result.PublicKey, []string{"message1", "other", "message3"}, signature)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.False(t, isValid, "Verify should reject a signature on other messages")
+}
+
+// TestRandomize tests that a randomized signature is still valid and differs from the original.
+func TestRandomize(t *testing.T) {
+	result, err := KeyGen(2)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	messages := []string{"message1", "message2"}
+
+	signature, err := Sign(result.SecretKey, messages)
+	assert.NoError(t, err, "Sign should not return an error")
+	randomized, err := Randomize(signature)
+	assert.NoError(t, err, "Randomize should not return an error")
+
+	assert.False(t, randomized.Sigma1.IsEqual(signature.Sigma1), "Randomized signature should differ")
+	isValid, err := Verify(result.PublicKey, messages, randomized)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.True(t, isValid, "Randomized signature should be valid")
+}
+
+// TestShowVerify tests unlinkable selective disclosure presentations.
+func TestShowVerify(t *testing.T) {
+	result, err := KeyGen(4)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	messages := []string{"alice", "student", "secret", "42"}
+	nonce := []byte("nonce")
+
+	signature, err := Sign(result.SecretKey, messages)
+	assert.NoError(t, err, "Sign should not return an error")
+	proof, err := Show(result.PublicKey, signature, messages, []int{1, 3}, nonce)
+	assert.NoError(t, err, "Show should not return an error")
+
+	revealed := map[int]string{1: "student", 3: "42"}
+	isValid, err := VerifyShow(result.PublicKey, proof, revealed, nonce)
+	assert.NoError(t, err, "VerifyShow should not return an error")
+	assert.True(t, isValid, "VerifyShow should accept a valid presentation")
+
+	isValid, err = VerifyShow(result.PublicKey, proof, map[int]string{1: "teacher", 3: "42"}, nonce)
+	assert.NoError(t, err, "VerifyShow should not return an error")
+	assert.False(t, isValid, "VerifyShow should reject wrong disclosed messages")
+
+	isValid, err = VerifyShow(result.PublicKey, proof, revealed, []byte("other"))
+	assert.NoError(t, err, "VerifyShow should not return an error")
+	assert.False(t, isValid, "VerifyShow should reject a presentation bound to another nonce")
+}
+
+// TestShowWrongKey tests that a presentation does not verify under another key.
+func TestShowWrongKey(t *testing.T) {
+	result, _ := KeyGen(2)
+	other, _ := KeyGen(2)
+	messages := []string{"message1", "message2"}
+
+	signature, err := Sign(result.SecretKey, messages)
+	assert.NoError(t, err, "Sign should not return an error")
+	proof, err := Show(result.PublicKey, signature, messages, nil, nil)
+	assert.NoError(t, err, "Show should not return an error")
+
+	isValid, err := VerifyShow(other.PublicKey, proof, map[int]string{}, nil)
+	assert.NoError(t, err, "VerifyShow should not return an error")
+	assert.False(t, isValid, "VerifyShow should reject a presentation under another key")
+}
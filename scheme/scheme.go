@@ -0,0 +1,173 @@
+package scheme
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aniagut/msc-bbs-plus-plus/keygen"
+	"github.com/aniagut/msc-bbs-plus-plus/models"
+	"github.com/aniagut/msc-bbs-plus-plus/proof"
+	"github.com/aniagut/msc-bbs-plus-plus/ps"
+	"github.com/aniagut/msc-bbs-plus-plus/sign"
+	"github.com/aniagut/msc-bbs-plus-plus/verify"
+)
+
+// Names of the supported schemes, as used in configuration.
+const (
+	NameBBS = "bbs++"
+	NamePS  = "ps"
+)
+
+// SigningKey, PublicKey, Signature and Presentation are opaque values whose concrete type
+// depends on the scheme that produced them.
+type (
+	SigningKey   interface{}
+	PublicKey    interface{}
+	Signature    interface{}
+	Presentation interface{}
+)
+
+// Scheme is a multi-message signature scheme with selective disclosure presentations.
+type Scheme interface {
+	// Name returns the configuration name of the scheme.
+	Name() string
+	// KeyGen generates keys for message vectors of length l.
+	KeyGen(l int) (SigningKey, PublicKey, error)
+	// Sign signs a message vector.
+	Sign(signingKey SigningKey, m []string) (Signature, error)
+	// Verify checks a signature on a message vector.
+	Verify(publicKey PublicKey, m []string, signature Signature) (bool, error)
+	// Present proves possession of a signature, disclosing the messages in disclosed.
+	Present(publicKey PublicKey, signature Signature, m []string, disclosed []int, nonce []byte) (Presentation, error)
+	// VerifyPresentation checks a presentation against the disclosed messages.
+	VerifyPresentation(publicKey PublicKey, presentation Presentation, revealed map[int]string, nonce []byte) (bool, error)
+}
+
+// New returns the scheme with the given configuration name.
+func New(name string) (Scheme, error) {
+	switch name {
+	case NameBBS:
+		return BBS{}, nil
+	case NamePS:
+		return PS{}, nil
+	default:
+		return nil, fmt.Errorf("unknown signature scheme %q", name)
+	}
+}
+
+// errKeyType is returned when a key or signature of another scheme is passed in.
+var errKeyType = errors.New("key or signature does not belong to this scheme")
+
+// BBSSigningKey is the signing key of the BBS++ scheme.
+type BBSSigningKey struct {
+	PublicParameters models.PublicParameters
+	SigningKey       models.SigningKey
+}
+
+// BBSPublicKey is the public key of the BBS++ scheme.
+type BBSPublicKey struct {
+	PublicParameters models.PublicParameters
+	VerificationKey  models.VerificationKey
+}
+
+// BBS is the BBS++ scheme implemented by the keygen, sign, verify and proof packages.
+type BBS struct{}
+
+func (BBS) Name() string {
+	return NameBBS
+}
+
+func (BBS) KeyGen(l int) (SigningKey, PublicKey, error) {
+	result, err := keygen.KeyGen(l)
+	if err != nil {
+		return nil, nil, err
+	}
+	return BBSSigningKey{PublicParameters: result.PublicParameters, SigningKey: result.SigningKey},
+		BBSPublicKey{PublicParameters: result.PublicParameters, VerificationKey: result.VerificationKey},
+		nil
+}
+
+func (BBS) Sign(signingKey SigningKey, m []string) (Signature, error) {
+	sk, ok := signingKey.(BBSSigningKey)
+	if !ok {
+		return nil, errKeyType
+	}
+	return sign.Sign(sk.PublicParameters, sk.SigningKey, m)
+}
+
+func (BBS) Verify(publicKey PublicKey, m []string, signature Signature) (bool, error) {
+	pk, ok := publicKey.(BBSPublicKey)
+	sig, sigOk := signature.(models.Signature)
+	if !ok || !sigOk {
+		return false, errKeyType
+	}
+	return verify.Verify(pk.PublicParameters, pk.VerificationKey, m, sig)
+}
+
+func (BBS) Present(publicKey PublicKey, signature Signature, m []string, disclosed []int, nonce []byte) (Presentation, error) {
+	pk, ok := publicKey.(BBSPublicKey)
+	sig, sigOk := signature.(models.Signature)
+	if !ok || !sigOk {
+		return nil, errKeyType
+	}
+	return proof.Prove(pk.PublicParameters, sig, m, disclosed, nonce)
+}
+
+func (BBS) VerifyPresentation(publicKey PublicKey, presentation Presentation, revealed map[int]string, nonce []byte) (bool, error) {
+	pk, ok := publicKey.(BBSPublicKey)
+	p, pOk := presentation.(models.Proof)
+	if !ok || !pOk {
+		return false, errKeyType
+	}
+	return proof.Verify(pk.PublicParameters, pk.VerificationKey, p, revealed, nonce)
+}
+
+// PS is the Pointcheval–Sanders scheme implemented by the ps package.
+type PS struct{}
+
+func (PS) Name() string {
+	return NamePS
+}
+
+func (PS) KeyGen(l int) (SigningKey, PublicKey, error) {
+	result, err := ps.KeyGen(l)
+	if err != nil {
+		return nil, nil, err
+	}
+	return result.SecretKey, result.PublicKey, nil
+}
+
+func (PS) Sign(signingKey SigningKey, m []string) (Signature, error) {
+	sk, ok := signingKey.(ps.SecretKey)
+	if !ok {
+		return nil, errKeyType
+	}
+	return ps.Sign(sk, m)
+}
+
+func (PS) Verify(publicKey PublicKey, m []string, signature Signature) (bool, error) {
+	pk, ok := publicKey.(ps.PublicKey)
+	sig, sigOk := signature.(ps.Signature)
+	if !ok || !sigOk {
+		return false, errKeyType
+	}
+	return ps.Verify(pk, m, sig)
+}
+
+func (PS) Present(publicKey PublicKey, signature Signature, m []string, disclosed []int, nonce []byte) (Presentation, error) {
+	pk, ok := publicKey.(ps.PublicKey)
+	sig, sigOk := signature.(ps.Signature)
+	if !ok || !sigOk {
+		return nil, errKeyType
+	}
+	return ps.Show(pk, sig, m, disclosed, nonce)
+}
+
+func (PS) VerifyPresentation(publicKey PublicKey, presentation Presentation, revealed map[int]string, nonce []byte) (bool, error) {
+	pk, ok := publicKey.(ps.PublicKey)
+	p, pOk := presentation.(ps.ShowProof)
+	if !ok || !pOk {
+		return false, errKeyType
+	}
+	return ps.VerifyShow(pk, p, revealed, nonce)
+}
@@ -0,0 +1,51 @@
+package scheme
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSchemes tests the full flow of every scheme through the common interface.
+func TestSchemes(t *testing.T) {
+	messages := []string{"alice", "student", "secret"}
+	nonce := []byte("nonce")
+
+	for _, name := range []string{NameBBS, NamePS} {
+		s, err := New(name)
+		assert.NoError(t, err, "New should not return an error")
+		assert.Equal(t, name, s.Name())
+
+		sk, pk, err := s.KeyGen(len(messages))
+		assert.NoError(t, err, "%s: KeyGen should not return an error", name)
+
+		signature, err := s.Sign(sk, messages)
+		assert.NoError(t, err, "%s: Sign should not return an error", name)
+		isValid, err := s.Verify(pk, messages, signature)
+		assert.NoError(t, err, "%s: Verify should not return an error", name)
+		assert.True(t, isValid, "%s: Verify should accept a valid signature", name)
+
+		presentation, err := s.Present(pk, signature, messages, []int{1}, nonce)
+		assert.NoError(t, err, "%s: Present should not return an error", name)
+		isValid, err = s.VerifyPresentation(pk, presentation, map[int]string{1: "student"}, nonce)
+		assert.NoError(t, err, "%s: VerifyPresentation should not return an error", name)
+		assert.True(t, isValid, "%s: VerifyPresentation should accept a valid presentation", name)
+	}
+}
+
+// TestMixedSchemes tests that keys of one scheme are rejected by the other.
+func TestMixedSchemes(t *testing.T) {
+	bbs, _ := New(NameBBS)
+	ps, _ := New(NamePS)
+
+	sk, _, err := bbs.KeyGen(2)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	_, err = ps.Sign(sk, []string{"a", "b"})
+	assert.Error(t, err, "PS should reject a BBS++ signing key")
+}
+
+// TestUnknownScheme tests that unknown configuration names are rejected.
+func TestUnknownScheme(t *testing.T) {
+	_, err := New("rsa")
+	assert.Error(t, err, "New should reject an unknown scheme")
+}
@@ -0,0 +1,163 @@
+package shamir
+
+import (
+	"errors"
+
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	e "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+// Share is the evaluation f(Index) of a secret-sharing polynomial f with f(0) = secret.
+type Share struct {
+	Index int
+	Value *e.Scalar
+}
+
+// Polynomial is a polynomial over Z_p given by its coefficients a_0, ..., a_{t-1}.
+type Polynomial []*e.Scalar
+
+// RandomPolynomial returns a random polynomial of the given degree with constant term secret.
+func RandomPolynomial(secret *e.Scalar, degree int) (Polynomial, error) {
+	if degree < 0 {
+		return nil, errors.New("polynomial degree must not be negative")
+	}
+	coefficients, err := utils.RandomScalars(degree)
+	if err != nil {
+		return nil, err
+	}
+	a0 := new(e.Scalar)
+	a0.Set(secret)
+	return append(Polynomial{a0}, coefficients...), nil
+}
+
+// Evaluate computes f(x) using Horner's rule.
+func (p Polynomial) Evaluate(x int) *e.Scalar {
+	xs := new(e.Scalar)
+	xs.SetUint64(uint64(x))
+	result := new(e.Scalar)
+	for i := len(p) - 1; i >= 0; i-- {
+		result.Mul(result, xs)
+		result.Add(result, p[i])
+	}
+	return result
+}
+
+// Split shares a secret among n parties so that any t of them can reconstruct it.
+//
+// Parameters:
+//   - secret: The secret to share.
+//   - n: The number of shares (indexes 1..n).
+//   - t: The threshold, i.e. the number of shares needed for reconstruction.
+//
+// Returns:
+//   - []Share: The shares.
+//   - Polynomial: The sharing polynomial, e.g. for computing Feldman commitments.
+//   - error: An error if the parameters are invalid.
+func Split(secret *e.Scalar, n, t int) ([]Share, Polynomial, error) {
+	if t < 1 || t > n {
+		return nil, nil, errors.New("threshold must satisfy 1 <= t <= n")
+	}
+	f, err := RandomPolynomial(secret, t-1)
+	if err != nil {
+		return nil, nil, err
+	}
+	shares := make([]Share, n)
+	for i := 0; i < n; i++ {
+		shares[i] = Share{Index: i + 1, Value: f.Evaluate(i + 1)}
+	}
+	return shares, f, nil
+}
+
+// Combine reconstructs the secret f(0) from shares with distinct indexes by Lagrange interpolation.
+// The caller is responsible for supplying at least t shares.
+func Combine(shares []Share) (*e.Scalar, error) {
+	return Interpolate(shares, 0)
+}
+
+// Interpolate evaluates at x the polynomial of degree len(shares)-1 through the given shares.
+func Interpolate(shares []Share, x int) (*e.Scalar, error) {
+	if len(shares) == 0 {
+		return nil, errors.New("no shares to combine")
+	}
+	if x < 0 {
+		return nil, errors.New("evaluation point must not be negative")
+	}
+
+	xs := new(e.Scalar)
+	xs.SetUint64(uint64(x))
+	result := new(e.Scalar)
+	for k, share := range shares {
+		if share.Index <= 0 {
+			return nil, errors.New("share indexes must be positive")
+		}
+		// L_k(x) = ∏_{m ≠ k} (x - i_m) / (i_k - i_m)
+		num := new(e.Scalar)
+		num.SetOne()
+		den := new(e.Scalar)
+		den.SetOne()
+		ik := new(e.Scalar)
+		ik.SetUint64(uint64(share.Index))
+		im := new(e.Scalar)
+		diff := new(e.Scalar)
+		for m, other := range shares {
+			if m == k {
+				continue
+			}
+			if other.Index == share.Index {
+				return nil, errors.New("duplicate share index")
+			}
+			im.SetUint64(uint64(other.Index))
+			diff.Sub(xs, im)
+			num.Mul(num, diff)
+			diff.Sub(ik, im)
+			den.Mul(den, diff)
+		}
+		den.Inv(den)
+		num.Mul(num, den)
+		num.Mul(num, share.Value)
+		result.Add(result, num)
+	}
+	return result, nil
+}
+
+// LagrangeCoefficient computes λ_i = ∏_{j ≠ i} j / (j - i), the coefficient of share i when
+// interpolating at 0 from the shares with the given indexes.
+func LagrangeCoefficient(i int, indexes []int) (*e.Scalar, error) {
+	if i <= 0 {
+		return nil, errors.New("share indexes must be positive")
+	}
+	num := new(e.Scalar)
+	num.SetOne()
+	den := new(e.Scalar)
+	den.SetOne()
+
+	found := false
+	seen := make(map[int]bool, len(indexes))
+	js := new(e.Scalar)
+	is := new(e.Scalar)
+	is.SetUint64(uint64(i))
+	diff := new(e.Scalar)
+	for _, j := range indexes {
+		if j <= 0 {
+			return nil, errors.New("share indexes must be positive")
+		}
+		if seen[j] {
+			return nil, errors.New("duplicate share index")
+		}
+		seen[j] = true
+		if j == i {
+			found = true
+			continue
+		}
+		js.SetUint64(uint64(j))
+		num.Mul(num, js)
+		diff.Sub(js, is)
+		den.Mul(den, diff)
+	}
+	if !found {
+		return nil, errors.New("share index is not in the index set")
+	}
+	den.Inv(den)
+	num.Mul(num, den)
+	return num, nil
+}
@@ -0,0 +1,69 @@
+package shamir
+
+import (
+	"testing"
+
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSplitCombine tests that any t shares reconstruct the secret.
+func TestSplitCombine(t *testing.T) {
+	secret, err := utils.RandomScalar()
+	assert.NoError(t, err, "RandomScalar should not return an error")
+
+	shares, _, err := Split(&secret, 5, 3)
+	assert.NoError(t, err, "Split should not return an error")
+	assert.Len(t, shares, 5, "Split should return n shares")
+
+	for _, subset := range [][]int{{0, 1, 2}, {0, 2, 4}, {4, 3, 1}, {0, 1, 2, 3, 4}} {
+		selected := make([]Share, len(subset))
+		for i, k := range subset {
+			selected[i] = shares[k]
+		}
+		recovered, err := Combine(selected)
+		assert.NoError(t, err, "Combine should not return an error")
+		assert.Equal(t, 1, recovered.IsEqual(&secret), "Combine should recover the secret from %v", subset)
+	}
+}
+
+// TestCombineTooFewShares tests that fewer than t shares do not reveal the secret.
+func TestCombineTooFewShares(t *testing.T) {
+	secret, err := utils.RandomScalar()
+	assert.NoError(t, err, "RandomScalar should not return an error")
+
+	shares, _, err := Split(&secret, 5, 3)
+	assert.NoError(t, err, "Split should not return an error")
+
+	recovered, err := Combine(shares[:2])
+	assert.NoError(t, err, "Combine should not return an error")
+	assert.Equal(t, 0, recovered.IsEqual(&secret), "Two shares should not recover the secret")
+}
+
+// TestSplitInvalidThreshold tests that invalid thresholds are rejected.
+func TestSplitInvalidThreshold(t *testing.T) {
+	secret, _ := utils.RandomScalar()
+	_, _, err := Split(&secret, 3, 4)
+	assert.Error(t, err, "Split should reject t > n")
+	_, _, err = Split(&secret, 3, 0)
+	assert.Error(t, err, "Split should reject t = 0")
+}
+
+// TestLagrangeDuplicateIndex tests that duplicate indexes are rejected.
+func TestLagrangeDuplicateIndex(t *testing.T) {
+	_, err := LagrangeCoefficient(1, []int{1, 2, 2})
+	assert.Error(t, err, "LagrangeCoefficient should reject duplicate indexes")
+}
+
+// TestInterpolate tests that interpolation recovers every point of the polynomial.
+func TestInterpolate(t *testing.T) {
+	secret, _ := utils.RandomScalar()
+	shares, f, err := Split(&secret, 6, 3)
+	assert.NoError(t, err, "Split should not return an error")
+
+	for x := 0; x <= 6; x++ {
+		value, err := Interpolate(shares[2:5], x)
+		assert.NoError(t, err, "Interpolate should not return an error")
+		assert.Equal(t, 1, value.IsEqual(f.Evaluate(x)), "Interpolate should recover f(%d)", x)
+	}
+}
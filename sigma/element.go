@@ -0,0 +1,134 @@
+package sigma
+
+import (
+	e "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+// Group identifies the group of an element.
+type Group byte
+
+const (
+	GroupG1 Group = iota + 1
+	GroupG2
+	GroupGT
+)
+
+// Element is an element of G1, G2 or G_T written multiplicatively: Exp is scalar multiplication
+// in G1 and G2 and exponentiation in G_T, and Op is the group operation.
+type Element interface {
+	// Group returns the group of the element.
+	Group() Group
+	// Exp returns the element raised to s.
+	Exp(s *e.Scalar) Element
+	// Op returns the product of the element and other, which must be in the same group.
+	Op(other Element) Element
+	// Equal reports whether the element equals other.
+	Equal(other Element) bool
+	// Bytes returns the canonical encoding of the element.
+	Bytes() []byte
+}
+
+// G1 wraps a point of G1.
+func G1(p *e.G1) Element {
+	return g1Element{p}
+}
+
+// G2 wraps a point of G2.
+func G2(p *e.G2) Element {
+	return g2Element{p}
+}
+
+// GT wraps an element of G_T.
+func GT(z *e.Gt) Element {
+	return gtElement{z}
+}
+
+// identity returns the identity of a group.
+func identity(group Group) Element {
+	switch group {
+	case GroupG1:
+		p := new(e.G1)
+		p.SetIdentity()
+		return g1Element{p}
+	case GroupG2:
+		p := new(e.G2)
+		p.SetIdentity()
+		return g2Element{p}
+	default:
+		z := new(e.Gt)
+		z.SetIdentity()
+		return gtElement{z}
+	}
+}
+
+type g1Element struct{ p *e.G1 }
+
+func (a g1Element) Group() Group { return GroupG1 }
+
+func (a g1Element) Exp(s *e.Scalar) Element {
+	r := new(e.G1)
+	r.ScalarMult(s, a.p)
+	return g1Element{r}
+}
+
+func (a g1Element) Op(other Element) Element {
+	r := new(e.G1)
+	r.Add(a.p, other.(g1Element).p)
+	return g1Element{r}
+}
+
+func (a g1Element) Equal(other Element) bool {
+	b, ok := other.(g1Element)
+	return ok && a.p.IsEqual(b.p)
+}
+
+func (a g1Element) Bytes() []byte { return a.p.BytesCompressed() }
+
+type g2Element struct{ p *e.G2 }
+
+func (a g2Element) Group() Group { return GroupG2 }
+
+func (a g2Element) Exp(s *e.Scalar) Element {
+	r := new(e.G2)
+	r.ScalarMult(s, a.p)
+	return g2Element{r}
+}
+
+func (a g2Element) Op(other Element) Element {
+	r := new(e.G2)
+	r.Add(a.p, other.(g2Element).p)
+	return g2Element{r}
+}
+
+func (a g2Element) Equal(other Element) bool {
+	b, ok := other.(g2Element)
+	return ok && a.p.IsEqual(b.p)
+}
+
+func (a g2Element) Bytes() []byte { return a.p.BytesCompressed() }
+
+type gtElement struct{ z *e.Gt }
+
+func (a gtElement) Group() Group { return GroupGT }
+
+func (a gtElement) Exp(s *e.Scalar) Element {
+	r := new(e.Gt)
+	r.Exp(a.z, s)
+	return gtElement{r}
+}
+
+func (a gtElement) Op(other Element) Element {
+	r := new(e.Gt)
+	r.Mul(a.z, other.(gtElement).z)
+	return gtElement{r}
+}
+
+func (a gtElement) Equal(other Element) bool {
+	b, ok := other.(gtElement)
+	return ok && a.z.IsEqual(b.z)
+}
+
+func (a gtElement) Bytes() []byte {
+	b, _ := a.z.MarshalBinary()
+	return b
+}
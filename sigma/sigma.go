@@ -0,0 +1,503 @@
+package sigma
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	e "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+// Term is the factor Base^Var of a relation, where Var names a secret witness.
+type Term struct {
+	Base Element
+	Var  string
+}
+
+// Equation is the linear relation Y = ∏ Base_i^{Var_i} in one group.
+type Equation struct {
+	Y     Element
+	Terms []Term
+}
+
+// Statement is a conjunction of linear relations over G1, G2 and G_T that share named witnesses.
+// Relations in different groups may use the same witness, which proves that the exponents are equal.
+type Statement struct {
+	Equations []Equation
+
+	vars  []string
+	index map[string]int
+	err   error
+}
+
+// Witness assigns values to the named witnesses of a statement.
+type Witness map[string]*e.Scalar
+
+// Proof is a non-interactive proof of a statement: the challenge and one response per witness,
+// in the order the witnesses first appear in the statement.
+type Proof struct {
+	Challenge *e.Scalar
+	Responses []*e.Scalar
+}
+
+// Prover is the state of a proof between the commitment and the response phase. Protocols that
+// hash the commitments together with other proofs, or that share blindings with other proofs to
+// link witnesses, use it instead of Prove.
+type Prover struct {
+	statement   *Statement
+	witness     Witness
+	blindings   []*e.Scalar
+	commitments []Element
+}
+
+// OrProof proves that at least one of several statements holds. Every branch is a proof of its
+// statement; the branch challenges add up to the challenge derived from the transcript.
+type OrProof struct {
+	Branches []Proof
+}
+
+// NewStatement creates an empty statement.
+func NewStatement() *Statement {
+	return &Statement{index: make(map[string]int)}
+}
+
+// Relation adds the relation y = ∏ base_i^{var_i}. All elements must be in the same group;
+// an inconsistent relation is reported by Prove and Verify.
+func (s *Statement) Relation(y Element, terms ...Term) *Statement {
+	if s.err != nil {
+		return s
+	}
+	if y == nil || len(terms) == 0 {
+		s.err = errors.New("relation needs a value and at least one term")
+		return s
+	}
+	for _, term := range terms {
+		if term.Base == nil || term.Base.Group() != y.Group() {
+			s.err = fmt.Errorf("relation for %q mixes groups", term.Var)
+			return s
+		}
+		if _, ok := s.index[term.Var]; !ok {
+			s.index[term.Var] = len(s.vars)
+			s.vars = append(s.vars, term.Var)
+		}
+	}
+	s.Equations = append(s.Equations, Equation{Y: y, Terms: append([]Term(nil), terms...)})
+	return s
+}
+
+// Vars returns the names of the witnesses in the order of the proof responses.
+func (s *Statement) Vars() []string {
+	return append([]string(nil), s.vars...)
+}
+
+// Err returns the first error found while building the statement.
+func (s *Statement) Err() error {
+	return s.err
+}
+
+// And returns the conjunction of the statements. Witnesses with the same name are shared.
+func And(statements ...*Statement) *Statement {
+	result := NewStatement()
+	for _, statement := range statements {
+		if statement.err != nil {
+			result.err = statement.err
+			return result
+		}
+		for _, equation := range statement.Equations {
+			result.Relation(equation.Y, equation.Terms...)
+		}
+	}
+	return result
+}
+
+// Holds reports whether the witness satisfies every relation of the statement.
+func (s *Statement) Holds(w Witness) bool {
+	if s.err != nil {
+		return false
+	}
+	for _, name := range s.vars {
+		if w[name] == nil {
+			return false
+		}
+	}
+	for _, equation := range s.Equations {
+		if !evaluate(equation, func(name string) *e.Scalar { return w[name] }).Equal(equation.Y) {
+			return false
+		}
+	}
+	return true
+}
+
+// Prove proves a statement with the given witness.
+//
+// Parameters:
+//   - t: The transcript; the verifier must have appended the same messages before calling Verify.
+//   - s: The statement to be proved.
+//   - w: The witness satisfying the statement.
+//
+// Returns:
+//   - Proof: The proof.
+//   - error: An error if the statement is malformed or the witness does not satisfy it.
+func Prove(t *Transcript, s *Statement, w Witness) (Proof, error) {
+	// Step 1: Commit T_i = ∏ base^{k_var} with fresh blindings
+	prover, err := NewProver(s, w, nil)
+	if err != nil {
+		return Proof{}, err
+	}
+
+	// Step 2: Derive the challenge from the statement and the commitments
+	t.AppendStatement(s)
+	t.AppendCommitments(prover.Commitments())
+	challenge := t.ChallengeScalar("challenge")
+
+	// Step 3: Compute the responses k_var + c·x_var
+	return prover.Respond(challenge), nil
+}
+
+// NewProver runs the commitment phase of a proof of a statement.
+//
+// Parameters:
+//   - s: The statement to be proved.
+//   - w: The witness satisfying the statement.
+//   - blindings: Optional blindings for some witnesses; supplying the blinding another proof uses
+//     for the same secret makes the responses equal under a common challenge. Missing ones are random.
+//
+// Returns:
+//   - *Prover: The prover state.
+//   - error: An error if the statement is malformed or the witness does not satisfy it.
+func NewProver(s *Statement, w Witness, blindings Witness) (*Prover, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	if !s.Holds(w) {
+		return nil, errors.New("witness does not satisfy the statement")
+	}
+	random, err := utils.RandomScalars(len(s.vars))
+	if err != nil {
+		return nil, err
+	}
+	for i, name := range s.vars {
+		if b := blindings[name]; b != nil {
+			random[i] = b
+		}
+	}
+	return &Prover{statement: s, witness: w, blindings: random, commitments: s.commit(random)}, nil
+}
+
+// Commitments returns the commitments of every relation, in the order of the statement.
+func (p *Prover) Commitments() []Element {
+	return append([]Element(nil), p.commitments...)
+}
+
+// Respond computes the responses for the given challenge.
+func (p *Prover) Respond(challenge *e.Scalar) Proof {
+	return Proof{Challenge: challenge, Responses: p.statement.respond(p.blindings, challenge, p.witness)}
+}
+
+// Commitments recomputes the commitments ∏ base^{s_var} · Y^{-c} of every relation from a proof.
+// A proof is valid when they equal the prover's commitments hashed into the challenge.
+func Commitments(s *Statement, p Proof) ([]Element, error) {
+	if err := s.checkProof(p); err != nil {
+		return nil, err
+	}
+	return s.recompute(p), nil
+}
+
+// Response returns the response of a named witness in a proof, or nil if the statement does not use it.
+func (s *Statement) Response(p Proof, name string) *e.Scalar {
+	i, ok := s.index[name]
+	if !ok || i >= len(p.Responses) {
+		return nil
+	}
+	return p.Responses[i]
+}
+
+// Verify checks a proof of a statement.
+//
+// Returns:
+//   - boolean: True if the proof is valid, false otherwise.
+//   - error: An error if the statement or the proof is malformed.
+func Verify(t *Transcript, s *Statement, p Proof) (bool, error) {
+	if err := s.checkProof(p); err != nil {
+		return false, err
+	}
+	commitments := s.recompute(p)
+	t.AppendStatement(s)
+	t.AppendCommitments(commitments)
+	expected := t.ChallengeScalar("challenge")
+	return expected.IsEqual(p.Challenge) == 1, nil
+}
+
+// Simulate produces an accepting proof for a given challenge without a witness, together with the
+// commitments it corresponds to. It is the honest-verifier zero-knowledge simulator of the protocol
+// and is used for the branches of OR proofs.
+func Simulate(s *Statement, challenge *e.Scalar) (Proof, []Element, error) {
+	if s.err != nil {
+		return Proof{}, nil, s.err
+	}
+	responses, err := utils.RandomScalars(len(s.vars))
+	if err != nil {
+		return Proof{}, nil, err
+	}
+	p := Proof{Challenge: challenge, Responses: responses}
+	return p, s.recompute(p), nil
+}
+
+// ProveOr proves that at least one of the statements holds, using the witness of the real branch.
+//
+// Parameters:
+//   - t: The transcript.
+//   - branches: The statements of the disjunction.
+//   - real: The index of the statement satisfied by the witness.
+//   - w: The witness of the real statement.
+//
+// Returns:
+//   - OrProof: The proof.
+//   - error: An error if a statement is malformed or the witness does not satisfy the real statement.
+func ProveOr(t *Transcript, branches []*Statement, real int, w Witness) (OrProof, error) {
+	if real < 0 || real >= len(branches) {
+		return OrProof{}, errors.New("real branch out of range")
+	}
+	for _, branch := range branches {
+		if branch.err != nil {
+			return OrProof{}, branch.err
+		}
+	}
+	if !branches[real].Holds(w) {
+		return OrProof{}, errors.New("witness does not satisfy the statement")
+	}
+
+	// Step 1: Simulate the other branches and commit to the real one
+	proofs := make([]Proof, len(branches))
+	commitments := make([][]Element, len(branches))
+	blindings, err := utils.RandomScalars(len(branches[real].vars))
+	if err != nil {
+		return OrProof{}, err
+	}
+	for i, branch := range branches {
+		if i == real {
+			commitments[i] = branch.commit(blindings)
+			continue
+		}
+		challenge, err := utils.RandomScalar()
+		if err != nil {
+			return OrProof{}, err
+		}
+		proofs[i], commitments[i], err = Simulate(branch, &challenge)
+		if err != nil {
+			return OrProof{}, err
+		}
+	}
+
+	// Step 2: Derive the challenge and split it: c_real = c - Σ_{i≠real} c_i
+	appendOr(t, branches, commitments)
+	challenge := t.ChallengeScalar("challenge")
+	realChallenge := new(e.Scalar)
+	realChallenge.Set(challenge)
+	for i := range branches {
+		if i != real {
+			realChallenge.Sub(realChallenge, proofs[i].Challenge)
+		}
+	}
+	proofs[real] = Proof{Challenge: realChallenge, Responses: branches[real].respond(blindings, realChallenge, w)}
+	return OrProof{Branches: proofs}, nil
+}
+
+// VerifyOr checks a proof that at least one of the statements holds.
+//
+// Returns:
+//   - boolean: True if the proof is valid, false otherwise.
+//   - error: An error if a statement or the proof is malformed.
+func VerifyOr(t *Transcript, branches []*Statement, p OrProof) (bool, error) {
+	if len(p.Branches) != len(branches) || len(branches) == 0 {
+		return false, errors.New("proof does not match the number of branches")
+	}
+	commitments := make([][]Element, len(branches))
+	sum := new(e.Scalar)
+	for i, branch := range branches {
+		if err := branch.checkProof(p.Branches[i]); err != nil {
+			return false, err
+		}
+		commitments[i] = branch.recompute(p.Branches[i])
+		sum.Add(sum, p.Branches[i].Challenge)
+	}
+	appendOr(t, branches, commitments)
+	expected := t.ChallengeScalar("challenge")
+	return expected.IsEqual(sum) == 1, nil
+}
+
+// commit computes the commitments ∏ base^{k_var} of every relation.
+func (s *Statement) commit(blindings []*e.Scalar) []Element {
+	commitments := make([]Element, len(s.Equations))
+	for i, equation := range s.Equations {
+		commitments[i] = evaluate(equation, func(name string) *e.Scalar { return blindings[s.index[name]] })
+	}
+	return commitments
+}
+
+// respond computes the responses k_var + c·x_var.
+func (s *Statement) respond(blindings []*e.Scalar, challenge *e.Scalar, w Witness) []*e.Scalar {
+	responses := make([]*e.Scalar, len(s.vars))
+	for i, name := range s.vars {
+		responses[i] = new(e.Scalar)
+		responses[i].Mul(challenge, w[name])
+		responses[i].Add(responses[i], blindings[i])
+	}
+	return responses
+}
+
+// recompute derives the commitments ∏ base^{s_var} · Y^{-c} from a proof.
+func (s *Statement) recompute(p Proof) []Element {
+	negC := utils.Neg(p.Challenge)
+	commitments := make([]Element, len(s.Equations))
+	for i, equation := range s.Equations {
+		value := evaluate(equation, func(name string) *e.Scalar { return p.Responses[s.index[name]] })
+		commitments[i] = value.Op(equation.Y.Exp(negC))
+	}
+	return commitments
+}
+
+// checkProof checks that a proof has the shape of the statement.
+func (s *Statement) checkProof(p Proof) error {
+	if s.err != nil {
+		return s.err
+	}
+	if p.Challenge == nil || len(p.Responses) != len(s.vars) {
+		return errors.New("proof does not match the statement")
+	}
+	for _, response := range p.Responses {
+		if response == nil {
+			return errors.New("proof is missing responses")
+		}
+	}
+	return nil
+}
+
+// evaluate computes ∏ base^{value(var)} for the terms of a relation.
+func evaluate(equation Equation, value func(name string) *e.Scalar) Element {
+	result := identity(equation.Y.Group())
+	for _, term := range equation.Terms {
+		result = result.Op(term.Base.Exp(value(term.Var)))
+	}
+	return result
+}
+
+// AppendStatement appends the structure and the public elements of a statement.
+func (t *Transcript) AppendStatement(s *Statement) {
+	t.AppendUint64("relations", uint64(len(s.Equations)))
+	for _, equation := range s.Equations {
+		t.AppendUint64("group", uint64(equation.Y.Group()))
+		t.AppendElement("value", equation.Y)
+		t.AppendUint64("terms", uint64(len(equation.Terms)))
+		for _, term := range equation.Terms {
+			t.AppendElement("base", term.Base)
+			t.AppendUint64("var", uint64(s.index[term.Var]))
+		}
+	}
+}
+
+// AppendCommitments appends the commitments of a proof.
+func (t *Transcript) AppendCommitments(commitments []Element) {
+	for _, commitment := range commitments {
+		t.AppendElement("commitment", commitment)
+	}
+}
+
+// appendOr appends all branches of a disjunction and their commitments.
+func appendOr(t *Transcript, branches []*Statement, commitments [][]Element) {
+	t.AppendUint64("branches", uint64(len(branches)))
+	for i, branch := range branches {
+		t.AppendStatement(branch)
+		t.AppendCommitments(commitments[i])
+	}
+}
+
+// MarshalBinary serializes the proof as the number of responses (2 bytes), the challenge and the responses.
+func (p Proof) MarshalBinary() ([]byte, error) {
+	if p.Challenge == nil || len(p.Responses) > 0xffff {
+		return nil, errors.New("proof cannot be serialized")
+	}
+	out := make([]byte, 2, 2+(len(p.Responses)+1)*e.ScalarSize)
+	binary.BigEndian.PutUint16(out, uint16(len(p.Responses)))
+	for _, scalar := range append([]*e.Scalar{p.Challenge}, p.Responses...) {
+		if scalar == nil {
+			return nil, errors.New("proof is missing responses")
+		}
+		b, err := scalar.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, b...)
+	}
+	return out, nil
+}
+
+// UnmarshalBinary parses a proof serialized with MarshalBinary.
+func (p *Proof) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 {
+		return errors.New("invalid proof length")
+	}
+	count := int(binary.BigEndian.Uint16(data))
+	if len(data) != 2+(count+1)*e.ScalarSize {
+		return errors.New("invalid proof length")
+	}
+	scalars := make([]*e.Scalar, count+1)
+	for i := range scalars {
+		scalars[i] = new(e.Scalar)
+		offset := 2 + i*e.ScalarSize
+		if err := scalars[i].UnmarshalBinary(data[offset : offset+e.ScalarSize]); err != nil {
+			return errors.New("invalid scalar")
+		}
+	}
+	*p = Proof{Challenge: scalars[0], Responses: scalars[1:]}
+	return nil
+}
+
+// MarshalBinary serializes the proof as the number of branches (2 bytes) followed by every branch
+// proof prefixed with its length (4 bytes).
+func (p OrProof) MarshalBinary() ([]byte, error) {
+	if len(p.Branches) > 0xffff {
+		return nil, errors.New("proof cannot be serialized")
+	}
+	out := make([]byte, 2)
+	binary.BigEndian.PutUint16(out, uint16(len(p.Branches)))
+	for _, branch := range p.Branches {
+		b, err := branch.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		var length [4]byte
+		binary.BigEndian.PutUint32(length[:], uint32(len(b)))
+		out = append(out, length[:]...)
+		out = append(out, b...)
+	}
+	return out, nil
+}
+
+// UnmarshalBinary parses a proof serialized with MarshalBinary.
+func (p *OrProof) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 {
+		return errors.New("invalid proof length")
+	}
+	branches := make([]Proof, binary.BigEndian.Uint16(data))
+	data = data[2:]
+	for i := range branches {
+		if len(data) < 4 {
+			return errors.New("invalid proof length")
+		}
+		length := int(binary.BigEndian.Uint32(data))
+		if len(data) < 4+length {
+			return errors.New("invalid proof length")
+		}
+		if err := branches[i].UnmarshalBinary(data[4 : 4+length]); err != nil {
+			return err
+		}
+		data = data[4+length:]
+	}
+	if len(data) != 0 {
+		return errors.New("invalid proof length")
+	}
+	*p = OrProof{Branches: branches}
+	return nil
+}
@@ -0,0 +1,136 @@
+package sigma
+
+import (
+	"testing"
+
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	e "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/stretchr/testify/assert"
+)
+
+// randomScalar returns a random scalar for testing.
+func randomScalar(t *testing.T) *e.Scalar {
+	s, err := utils.RandomScalar()
+	assert.NoError(t, err, "RandomScalar should not return an error")
+	return &s
+}
+
+// equalityStatement builds the statement Y1 = g1^x, Y2 = g2^x, Z = e(g1, g2)^x for the witness x.
+func equalityStatement(x *e.Scalar) *Statement {
+	g1, g2 := e.G1Generator(), e.G2Generator()
+	gt := GT(e.Pair(g1, g2))
+	return NewStatement().
+		Relation(G1(g1).Exp(x), Term{Base: G1(g1), Var: "x"}).
+		Relation(G2(g2).Exp(x), Term{Base: G2(g2), Var: "x"}).
+		Relation(gt.Exp(x), Term{Base: gt, Var: "x"})
+}
+
+// TestProveVerify tests a conjunction of relations over G1, G2 and G_T sharing a witness.
+func TestProveVerify(t *testing.T) {
+	x := randomScalar(t)
+	statement := equalityStatement(x)
+
+	proof, err := Prove(NewTranscript("test"), statement, Witness{"x": x})
+	assert.NoError(t, err, "Prove should not return an error")
+
+	isValid, err := Verify(NewTranscript("test"), statement, proof)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.True(t, isValid, "Verify should accept a valid proof")
+
+	isValid, err = Verify(NewTranscript("other domain"), statement, proof)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.False(t, isValid, "Verify should reject a proof for another domain")
+
+	_, err = Prove(NewTranscript("test"), statement, Witness{"x": randomScalar(t)})
+	assert.Error(t, err, "Prove should reject a wrong witness")
+}
+
+// TestAndLinearRelation tests a Pedersen-style relation combined with a discrete logarithm.
+func TestAndLinearRelation(t *testing.T) {
+	g, h := G1(e.G1Generator()), G1(utils.HashToG1([]byte("h"), "SIGMA-TEST"))
+	m, r := randomScalar(t), randomScalar(t)
+	commitment := g.Exp(m).Op(h.Exp(r))
+	statement := And(
+		NewStatement().Relation(commitment, Term{Base: g, Var: "m"}, Term{Base: h, Var: "r"}),
+		NewStatement().Relation(h.Exp(m), Term{Base: h, Var: "m"}),
+	)
+	assert.Equal(t, []string{"m", "r"}, statement.Vars())
+
+	proof, err := Prove(NewTranscript("test"), statement, Witness{"m": m, "r": r})
+	assert.NoError(t, err, "Prove should not return an error")
+	isValid, err := Verify(NewTranscript("test"), statement, proof)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.True(t, isValid, "Verify should accept a valid proof")
+}
+
+// TestOr tests that an OR proof verifies for every real branch and hides which one is real.
+func TestOr(t *testing.T) {
+	g := G1(e.G1Generator())
+	x := randomScalar(t)
+	branches := []*Statement{
+		NewStatement().Relation(g.Exp(randomScalar(t)), Term{Base: g, Var: "x"}),
+		NewStatement().Relation(g.Exp(x), Term{Base: g, Var: "x"}),
+		NewStatement().Relation(g.Exp(randomScalar(t)), Term{Base: g, Var: "x"}),
+	}
+
+	proof, err := ProveOr(NewTranscript("test"), branches, 1, Witness{"x": x})
+	assert.NoError(t, err, "ProveOr should not return an error")
+	isValid, err := VerifyOr(NewTranscript("test"), branches, proof)
+	assert.NoError(t, err, "VerifyOr should not return an error")
+	assert.True(t, isValid, "VerifyOr should accept a valid proof")
+
+	_, err = ProveOr(NewTranscript("test"), branches, 0, Witness{"x": x})
+	assert.Error(t, err, "ProveOr should reject a witness for another branch")
+
+	isValid, err = VerifyOr(NewTranscript("test"), branches[:2], OrProof{Branches: proof.Branches[:2]})
+	assert.NoError(t, err, "VerifyOr should not return an error")
+	assert.False(t, isValid, "VerifyOr should reject a proof with a dropped branch")
+}
+
+// TestSimulate tests that simulated proofs satisfy the verification equations for their challenge.
+func TestSimulate(t *testing.T) {
+	statement := equalityStatement(randomScalar(t))
+	challenge := randomScalar(t)
+	proof, commitments, err := Simulate(statement, challenge)
+	assert.NoError(t, err, "Simulate should not return an error")
+	assert.True(t, proof.Challenge.IsEqual(challenge) == 1)
+
+	recomputed := statement.recompute(proof)
+	for i := range commitments {
+		assert.True(t, recomputed[i].Equal(commitments[i]), "Simulated commitments should be consistent")
+	}
+}
+
+// TestMalformedStatement tests that relations mixing groups are rejected.
+func TestMalformedStatement(t *testing.T) {
+	statement := NewStatement().Relation(G1(e.G1Generator()), Term{Base: G2(e.G2Generator()), Var: "x"})
+	assert.Error(t, statement.Err(), "Relation should reject mixed groups")
+	_, err := Prove(NewTranscript("test"), statement, Witness{"x": randomScalar(t)})
+	assert.Error(t, err, "Prove should reject a malformed statement")
+}
+
+// TestSerialization tests the binary encoding of proofs.
+func TestSerialization(t *testing.T) {
+	g := G1(e.G1Generator())
+	x := randomScalar(t)
+	branches := []*Statement{
+		NewStatement().Relation(g.Exp(x), Term{Base: g, Var: "x"}),
+		NewStatement().Relation(g.Exp(randomScalar(t)), Term{Base: g, Var: "x"}),
+	}
+	orProof, err := ProveOr(NewTranscript("test"), branches, 0, Witness{"x": x})
+	assert.NoError(t, err, "ProveOr should not return an error")
+
+	data, err := orProof.MarshalBinary()
+	assert.NoError(t, err, "MarshalBinary should not return an error")
+	var decoded OrProof
+	assert.NoError(t, decoded.UnmarshalBinary(data), "UnmarshalBinary should not return an error")
+	isValid, err := VerifyOr(NewTranscript("test"), branches, decoded)
+	assert.NoError(t, err, "VerifyOr should not return an error")
+	assert.True(t, isValid, "VerifyOr should accept a decoded proof")
+
+	data, err = orProof.Branches[0].MarshalBinary()
+	assert.NoError(t, err, "MarshalBinary should not return an error")
+	var branch Proof
+	assert.NoError(t, branch.UnmarshalBinary(data), "UnmarshalBinary should not return an error")
+	assert.Error(t, branch.UnmarshalBinary(data[:len(data)-1]), "UnmarshalBinary should reject truncated data")
+}
@@ -0,0 +1,63 @@
+package sigma
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+	"hash"
+
+	e "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+// Transcript is a Fiat–Shamir transcript in the style of Merlin: the prover and the verifier
+// append the same labeled messages and derive challenges from everything appended so far.
+// Every challenge is appended back to the transcript, so later challenges depend on earlier ones.
+//
+// Labels and messages are length-prefixed, so different sequences of appends never collide.
+type Transcript struct {
+	h hash.Hash
+}
+
+// NewTranscript creates a transcript separated by the protocol's domain label.
+func NewTranscript(domain string) *Transcript {
+	t := &Transcript{h: sha512.New()}
+	t.AppendMessage("dom-sep", []byte(domain))
+	return t
+}
+
+// AppendMessage appends a labeled message.
+func (t *Transcript) AppendMessage(label string, message []byte) {
+	var length [8]byte
+	binary.BigEndian.PutUint64(length[:], uint64(len(label)))
+	t.h.Write(length[:])
+	t.h.Write([]byte(label))
+	binary.BigEndian.PutUint64(length[:], uint64(len(message)))
+	t.h.Write(length[:])
+	t.h.Write(message)
+}
+
+// AppendUint64 appends a labeled integer.
+func (t *Transcript) AppendUint64(label string, value uint64) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], value)
+	t.AppendMessage(label, buf[:])
+}
+
+// AppendScalar appends a labeled scalar.
+func (t *Transcript) AppendScalar(label string, s *e.Scalar) {
+	b, _ := s.MarshalBinary()
+	t.AppendMessage(label, b)
+}
+
+// AppendElement appends a labeled group element.
+func (t *Transcript) AppendElement(label string, element Element) {
+	t.AppendMessage(label, element.Bytes())
+}
+
+// ChallengeScalar derives a labeled challenge from the transcript and appends it.
+func (t *Transcript) ChallengeScalar(label string) *e.Scalar {
+	t.AppendMessage("challenge", []byte(label))
+	challenge := new(e.Scalar)
+	challenge.SetBytes(t.h.Sum(nil))
+	t.AppendScalar(label, challenge)
+	return challenge
+}
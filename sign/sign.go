@@ -25,19 +25,25 @@ func Sign(publicParams models.PublicParameters, signingKey models.SigningKey, m
         return models.Signature{}, err
     }
 
-    // Step 2: Set random elem ← Z_p* and ensure x + e ≠ 0
-    elem := new(e.Scalar)
-    for {
-        randomScalar, err := utils.RandomScalar()
-        if err != nil {
-            return models.Signature{}, errors.New("failed to generate random scalar e")
-        }
+    return SignCommitment(signingKey, c)
+}
 
-        // Check if x + e ≠ 0
-        elem.Add(signingKey.X, &randomScalar)
-        if elem.IsZero() == 0 {
-            break
-        }
+// SignCommitment generates a BBS++ signature on a precomputed commitment c = g1 * ∏_i h₁[i]^m[i].
+// It is used for blind issuance, where some messages are only known to the holder and
+// the issuer receives their part of the commitment.
+//
+// Parameters:
+//   - signingKey: The key used for signing the message.
+//   - c: The commitment to the messages.
+//
+// Returns:
+//   - signature: The generated signature.
+//   - error: An error if the signing process fails.
+func SignCommitment(signingKey models.SigningKey, c *e.G1) (models.Signature, error) {
+    // Step 2: Set random elem ← Z_p* and ensure x + e ≠ 0
+    elem, err := RandomE(signingKey)
+    if err != nil {
+        return models.Signature{}, err
     }
 
     // Step 3: Compute signature component A <- c^{1 / (x + e)} ∈ G_1
@@ -50,6 +56,23 @@ func Sign(publicParams models.PublicParameters, signingKey models.SigningKey, m
     }, nil
 }
 
+// RandomE samples the signature randomness elem ← Z_p* with x + e ≠ 0.
+func RandomE(signingKey models.SigningKey) (*e.Scalar, error) {
+    xPlusE := new(e.Scalar)
+    for {
+        randomScalar, err := utils.RandomScalar()
+        if err != nil {
+            return nil, errors.New("failed to generate random scalar e")
+        }
+
+        // Check if x + e ≠ 0
+        xPlusE.Add(signingKey.X, &randomScalar)
+        if xPlusE.IsZero() == 0 {
+            return &randomScalar, nil
+        }
+    }
+}
+
 // ComputeA computes the signature component A = c^{1 / (x + e)} ∈ G_1
 func ComputeA(x *e.Scalar, elem *e.Scalar, c *e.G1) *e.G1 {
     xPlusE := new(e.Scalar)
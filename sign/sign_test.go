@@ -68,6 +68,25 @@ func TestSignCorrectness(t *testing.T) {
     assert.Equal(t, expectedA, signature.A, "Signature component A should satisfy the expected mathematical property")
 }
 
+// TestSignCommitment tests that SignCommitment signs a precomputed commitment: A^{x+e} = c.
+func TestSignCommitment(t *testing.T) {
+    h1 := GenerateMockH1(2)
+    signingKey := models.SigningKey{
+        X: GenerateMockScalar(12345),
+    }
+    c, err := utils.ComputeCommitment([]string{"message1", "message2"}, h1, e.G1Generator())
+    assert.NoError(t, err, "ComputeCommitment should not return an error")
+
+    signature, err := SignCommitment(signingKey, c)
+    assert.NoError(t, err, "SignCommitment should not return an error")
+
+    exponent := new(e.Scalar)
+    exponent.Add(signingKey.X, signature.E)
+    check := new(e.G1)
+    check.ScalarMult(exponent, signature.A)
+    assert.True(t, check.IsEqual(c), "A^{x+e} should equal the commitment")
+}
+
 // GenerateMockH1 generates a slice of mock G1 elements for testing purposes.
 func GenerateMockH1(length int) []e.G1 {
     h1 := make([]e.G1, length)
@@ -0,0 +1,399 @@
+package statuslist
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"math/big"
+	"strconv"
+	"sync"
+
+	"github.com/aniagut/msc-bbs-plus-plus/models"
+	"github.com/aniagut/msc-bbs-plus-plus/sign"
+	"github.com/aniagut/msc-bbs-plus-plus/verify"
+)
+
+// DefaultSize is the default number of entries in a status list (16KB of bits), the minimum
+// recommended by the W3C Bitstring Status List specification for group privacy.
+const DefaultSize = 131072
+
+// MaxSize is the largest number of entries a status list may have (8MB of bits). Decode stops
+// decompressing beyond it, so that a small payload cannot expand into unbounded memory.
+const MaxSize = 1 << 26
+
+// listDomainTag is signed as the first message of every status list signature, so that a list
+// signature can never be confused with a credential signature issued under the same key.
+const listDomainTag = "bbs-status-list"
+
+// StatusList is a bitstring in which bit i holds the revocation status of the credential
+// that was issued with status index i.
+type StatusList struct {
+	mu   sync.RWMutex
+	bits []byte
+	size int
+}
+
+// SignedStatusList is a compressed status list published by the issuer together with a
+// BBS++ signature over its identifier and contents.
+type SignedStatusList struct {
+	ID          string
+	EncodedList string
+	Signature   models.Signature
+}
+
+// New creates a status list with the given number of entries, all set to "not revoked".
+func New(size int) (*StatusList, error) {
+	if size <= 0 {
+		return nil, errors.New("status list size must be positive")
+	}
+	if size > MaxSize {
+		return nil, errors.New("status list size exceeds the maximum")
+	}
+	return &StatusList{
+		bits: make([]byte, (size+7)/8),
+		size: size,
+	}, nil
+}
+
+// Size returns the number of entries in the status list.
+func (s *StatusList) Size() int {
+	return s.size
+}
+
+// SetRevoked sets the status bit for the given index.
+func (s *StatusList) SetRevoked(index int, revoked bool) error {
+	if index < 0 || index >= s.size {
+		return errors.New("status index out of range")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Bit 0 is the left-most bit of the first byte
+	mask := byte(0x80) >> uint(index%8)
+	if revoked {
+		s.bits[index/8] |= mask
+	} else {
+		s.bits[index/8] &^= mask
+	}
+	return nil
+}
+
+// IsRevoked returns the status bit for the given index.
+func (s *StatusList) IsRevoked(index int) (bool, error) {
+	if index < 0 || index >= s.size {
+		return false, errors.New("status index out of range")
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	mask := byte(0x80) >> uint(index%8)
+	return s.bits[index/8]&mask != 0, nil
+}
+
+// Encode compresses the bitstring with GZIP and encodes it as unpadded base64url.
+func (s *StatusList) Encode() (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(s.bits); err != nil {
+		return "", errors.New("failed to compress status list")
+	}
+	if err := w.Close(); err != nil {
+		return "", errors.New("failed to compress status list")
+	}
+	return base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// Decode reconstructs a status list from its encoded form produced by Encode.
+// The size of the decoded list is the number of bits in the decompressed bitstring, at most MaxSize.
+func Decode(encoded string) (*StatusList, error) {
+	compressed, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.New("status list is not valid base64url")
+	}
+	r, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, errors.New("status list is not valid gzip data")
+	}
+	defer r.Close()
+
+	// Read one byte past the maximum to detect lists that are too large
+	bits, err := io.ReadAll(io.LimitReader(r, MaxSize/8+1))
+	if err != nil {
+		return nil, errors.New("failed to decompress status list")
+	}
+	if len(bits) > MaxSize/8 {
+		return nil, errors.New("status list exceeds the maximum size")
+	}
+	if len(bits) == 0 {
+		return nil, errors.New("status list is empty")
+	}
+	return &StatusList{
+		bits: bits,
+		size: len(bits) * 8,
+	}, nil
+}
+
+// Allocator hands out unused status indexes of a status list at issuance time.
+// Indexes are chosen uniformly at random so that they do not reveal the issuance order.
+type Allocator struct {
+	mu        sync.Mutex
+	allocated []byte
+	count     int
+	size      int
+}
+
+// NewAllocator creates an allocator for a status list with the given number of entries.
+func NewAllocator(size int) (*Allocator, error) {
+	if size <= 0 {
+		return nil, errors.New("status list size must be positive")
+	}
+	if size > MaxSize {
+		return nil, errors.New("status list size exceeds the maximum")
+	}
+	return &Allocator{
+		allocated: make([]byte, (size+7)/8),
+		size:      size,
+	}, nil
+}
+
+// Allocate returns a fresh status index that has not been handed out before.
+func (a *Allocator) Allocate() (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.count == a.size {
+		return 0, errors.New("status list is full")
+	}
+
+	// Pick random indexes until an unused one is found; fall back to a linear
+	// scan from a random starting point once the list is mostly allocated
+	for attempt := 0; attempt < 64; attempt++ {
+		index, err := randomIndex(a.size)
+		if err != nil {
+			return 0, err
+		}
+		if a.tryMark(index) {
+			return index, nil
+		}
+	}
+	start, err := randomIndex(a.size)
+	if err != nil {
+		return 0, err
+	}
+	for i := 0; i < a.size; i++ {
+		index := (start + i) % a.size
+		if a.tryMark(index) {
+			return index, nil
+		}
+	}
+	return 0, errors.New("status list is full")
+}
+
+// MarkAllocated records an index as used, e.g. when restoring the allocator state from storage.
+func (a *Allocator) MarkAllocated(index int) error {
+	if index < 0 || index >= a.size {
+		return errors.New("status index out of range")
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.tryMark(index)
+	return nil
+}
+
+// tryMark marks the index as allocated and reports whether it was free.
+func (a *Allocator) tryMark(index int) bool {
+	mask := byte(0x80) >> uint(index%8)
+	if a.allocated[index/8]&mask != 0 {
+		return false
+	}
+	a.allocated[index/8] |= mask
+	a.count++
+	return true
+}
+
+// release returns an allocated index that was never used in a credential to the allocator.
+func (a *Allocator) release(index int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	mask := byte(0x80) >> uint(index%8)
+	if a.allocated[index/8]&mask != 0 {
+		a.allocated[index/8] &^= mask
+		a.count--
+	}
+}
+
+// randomIndex returns a uniformly random integer in [0, size).
+func randomIndex(size int) (int, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(size)))
+	if err != nil {
+		return 0, errors.New("failed to generate random status index")
+	}
+	return int(n.Int64()), nil
+}
+
+// EncodeIndex encodes a status index as the message that is signed in the credential.
+func EncodeIndex(index int) string {
+	return strconv.Itoa(index)
+}
+
+// DecodeIndex parses a status index from a signed (or disclosed) credential message.
+func DecodeIndex(message string) (int, error) {
+	index, err := strconv.Atoi(message)
+	if err != nil || index < 0 {
+		return 0, errors.New("message is not a valid status index")
+	}
+	return index, nil
+}
+
+// Issue allocates a status index, writes it into the message vector at the given position
+// and signs the resulting messages with sign.Sign.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - signingKey: The issuer's signing key.
+//   - m: The messages of the credential; the entry at position is overwritten.
+//   - position: The position of the status index in the message vector.
+//   - allocator: The allocator of the status list the credential is registered in.
+//
+// Returns:
+//   - signature: The credential signature.
+//   - messages: The signed messages, including the encoded status index.
+//   - error: An error if allocation or signing fails.
+func Issue(publicParams models.PublicParameters, signingKey models.SigningKey, m []string, position int, allocator *Allocator) (models.Signature, []string, error) {
+	if position < 0 || position >= len(m) {
+		return models.Signature{}, nil, errors.New("status index position out of range")
+	}
+	if len(m) != len(publicParams.H1) {
+		return models.Signature{}, nil, errors.New("message vector length does not match h1 length")
+	}
+
+	// Step 1: Allocate a fresh index in the status list
+	index, err := allocator.Allocate()
+	if err != nil {
+		return models.Signature{}, nil, err
+	}
+
+	// Step 2: Place the encoded index in the message vector
+	messages := make([]string, len(m))
+	copy(messages, m)
+	messages[position] = EncodeIndex(index)
+
+	// Step 3: Sign the messages; an index that ends up in no credential goes back to the allocator
+	signature, err := sign.Sign(publicParams, signingKey, messages)
+	if err != nil {
+		allocator.release(index)
+		return models.Signature{}, nil, err
+	}
+	return signature, messages, nil
+}
+
+// listMessages builds the message vector signed for a status list:
+// (domain tag, list identifier, SHA-256 of the encoded list, "", ..., "").
+func listMessages(id string, encodedList string, l int) ([]string, error) {
+	if l < 3 {
+		return nil, errors.New("public parameters must support at least 3 messages to sign a status list")
+	}
+	digest := sha256.Sum256([]byte(encodedList))
+	messages := make([]string, l)
+	messages[0] = listDomainTag
+	messages[1] = id
+	messages[2] = hex.EncodeToString(digest[:])
+	return messages, nil
+}
+
+// SignList compresses the status list and signs it with the issuer key.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system (at least 3 generators in H1).
+//   - signingKey: The issuer's signing key.
+//   - id: The identifier (e.g. URL) under which the list is published.
+//   - list: The status list to publish.
+//
+// Returns:
+//   - SignedStatusList: The encoded list together with its signature.
+//   - error: An error if encoding or signing fails.
+func SignList(publicParams models.PublicParameters, signingKey models.SigningKey, id string, list *StatusList) (SignedStatusList, error) {
+	encoded, err := list.Encode()
+	if err != nil {
+		return SignedStatusList{}, err
+	}
+	messages, err := listMessages(id, encoded, len(publicParams.H1))
+	if err != nil {
+		return SignedStatusList{}, err
+	}
+	signature, err := sign.Sign(publicParams, signingKey, messages)
+	if err != nil {
+		return SignedStatusList{}, err
+	}
+	return SignedStatusList{
+		ID:          id,
+		EncodedList: encoded,
+		Signature:   signature,
+	}, nil
+}
+
+// VerifyList checks the issuer signature on a published status list and decodes it.
+//
+// Returns:
+//   - StatusList: The decoded list, or nil if the signature is invalid.
+//   - error: An error if the list cannot be verified or decoded.
+func VerifyList(publicParams models.PublicParameters, verificationKey models.VerificationKey, signed SignedStatusList) (*StatusList, error) {
+	messages, err := listMessages(signed.ID, signed.EncodedList, len(publicParams.H1))
+	if err != nil {
+		return nil, err
+	}
+	isValid, err := verify.Verify(publicParams, verificationKey, messages, signed.Signature)
+	if err != nil {
+		return nil, err
+	}
+	if !isValid {
+		return nil, errors.New("invalid status list signature")
+	}
+	return Decode(signed.EncodedList)
+}
+
+// CheckStatus verifies a published status list and returns the status bit of the given index.
+func CheckStatus(publicParams models.PublicParameters, verificationKey models.VerificationKey, signed SignedStatusList, index int) (bool, error) {
+	list, err := VerifyList(publicParams, verificationKey, signed)
+	if err != nil {
+		return false, err
+	}
+	return list.IsRevoked(index)
+}
+
+// VerifyWithStatus verifies a credential signature and checks that the status index signed
+// at the given position is not revoked in the published status list.
+//
+// Returns:
+//   - boolean: True if the signature is valid and the credential is not revoked, false otherwise.
+//   - error: An error if the verification process fails.
+func VerifyWithStatus(publicParams models.PublicParameters, verificationKey models.VerificationKey, m []string, signature models.Signature, position int, signed SignedStatusList) (bool, error) {
+	if position < 0 || position >= len(m) {
+		return false, errors.New("status index position out of range")
+	}
+
+	// Step 1: Verify the credential signature
+	isValid, err := verify.Verify(publicParams, verificationKey, m, signature)
+	if err != nil || !isValid {
+		return false, err
+	}
+
+	// Step 2: Check the status bit of the signed index
+	index, err := DecodeIndex(m[position])
+	if err != nil {
+		return false, err
+	}
+	revoked, err := CheckStatus(publicParams, verificationKey, signed, index)
+	if err != nil {
+		return false, err
+	}
+	return !revoked, nil
+}
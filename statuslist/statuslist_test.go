@@ -0,0 +1,154 @@
+package statuslist
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"testing"
+
+	"github.com/aniagut/msc-bbs-plus-plus/keygen"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestStatusBits tests setting and reading status bits.
+func TestStatusBits(t *testing.T) {
+	list, err := New(100)
+	assert.NoError(t, err, "New should not return an error")
+
+	assert.NoError(t, list.SetRevoked(0, true))
+	assert.NoError(t, list.SetRevoked(42, true))
+	assert.NoError(t, list.SetRevoked(42, false))
+	assert.NoError(t, list.SetRevoked(99, true))
+
+	revoked, _ := list.IsRevoked(0)
+	assert.True(t, revoked, "Index 0 should be revoked")
+	revoked, _ = list.IsRevoked(42)
+	assert.False(t, revoked, "Index 42 should have been reinstated")
+	revoked, _ = list.IsRevoked(99)
+	assert.True(t, revoked, "Index 99 should be revoked")
+
+	_, err = list.IsRevoked(100)
+	assert.Error(t, err, "Out of range index should return an error")
+}
+
+// TestEncodeDecode tests that a list survives compression and encoding.
+func TestEncodeDecode(t *testing.T) {
+	list, err := New(DefaultSize)
+	assert.NoError(t, err, "New should not return an error")
+	assert.NoError(t, list.SetRevoked(12345, true))
+
+	encoded, err := list.Encode()
+	assert.NoError(t, err, "Encode should not return an error")
+	assert.Less(t, len(encoded), DefaultSize/8, "Encoded list should be compressed")
+
+	decoded, err := Decode(encoded)
+	assert.NoError(t, err, "Decode should not return an error")
+	assert.Equal(t, DefaultSize, decoded.Size(), "Decoded list should have the same size")
+
+	revoked, _ := decoded.IsRevoked(12345)
+	assert.True(t, revoked, "Revoked bit should survive encoding")
+	revoked, _ = decoded.IsRevoked(12346)
+	assert.False(t, revoked, "Other bits should not be set")
+}
+
+// TestDecodeTooLarge tests that a small payload that decompresses beyond MaxSize is rejected.
+func TestDecodeTooLarge(t *testing.T) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write(make([]byte, MaxSize/8+1))
+	assert.NoError(t, err, "Write should not return an error")
+	assert.NoError(t, w.Close(), "Close should not return an error")
+	assert.Less(t, buf.Len(), MaxSize/8/100, "The payload should be small")
+
+	_, err = Decode(base64.RawURLEncoding.EncodeToString(buf.Bytes()))
+	assert.Error(t, err, "Decode should reject a list larger than MaxSize")
+
+	_, err = New(MaxSize + 1)
+	assert.Error(t, err, "New should reject a list larger than MaxSize")
+}
+
+// TestAllocatorUnique tests that the allocator never hands out the same index twice.
+func TestAllocatorUnique(t *testing.T) {
+	size := 64
+	allocator, err := NewAllocator(size)
+	assert.NoError(t, err, "NewAllocator should not return an error")
+
+	seen := make(map[int]bool)
+	for i := 0; i < size; i++ {
+		index, err := allocator.Allocate()
+		assert.NoError(t, err, "Allocate should not return an error")
+		assert.False(t, seen[index], "Index %d should not be allocated twice", index)
+		seen[index] = true
+	}
+
+	_, err = allocator.Allocate()
+	assert.Error(t, err, "Allocate should fail once the list is full")
+
+	_, err = NewAllocator(MaxSize + 1)
+	assert.Error(t, err, "NewAllocator should reject a list larger than MaxSize")
+}
+
+// TestIssueKeepsIndexOnError tests that a failed issuance does not use up a status index.
+func TestIssueKeepsIndexOnError(t *testing.T) {
+	result, err := keygen.KeyGen(4)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	allocator, err := NewAllocator(1)
+	assert.NoError(t, err, "NewAllocator should not return an error")
+
+	_, _, err = Issue(result.PublicParameters, result.SigningKey, []string{"alice", ""}, 1, allocator)
+	assert.Error(t, err, "Issue should reject a message vector of the wrong length")
+
+	_, m, err := Issue(result.PublicParameters, result.SigningKey, []string{"alice", "student", "2025", ""}, 3, allocator)
+	assert.NoError(t, err, "Issue should still find the only index of the list")
+	assert.Equal(t, EncodeIndex(0), m[3], "Issue should use the index left by the failed issuance")
+}
+
+// TestIssueAndCheckStatus tests the issuance, publication and verification flow.
+func TestIssueAndCheckStatus(t *testing.T) {
+	result, err := keygen.KeyGen(4)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	pp, sk, vk := result.PublicParameters, result.SigningKey, result.VerificationKey
+
+	list, _ := New(1024)
+	allocator, _ := NewAllocator(1024)
+
+	// Issue two credentials with the status index in the last slot
+	sig1, m1, err := Issue(pp, sk, []string{"alice", "student", "2025", ""}, 3, allocator)
+	assert.NoError(t, err, "Issue should not return an error")
+	sig2, m2, err := Issue(pp, sk, []string{"bob", "student", "2025", ""}, 3, allocator)
+	assert.NoError(t, err, "Issue should not return an error")
+
+	// Revoke the second credential and publish the list
+	index2, err := DecodeIndex(m2[3])
+	assert.NoError(t, err, "DecodeIndex should not return an error")
+	assert.NoError(t, list.SetRevoked(index2, true))
+	signed, err := SignList(pp, sk, "https://issuer.example/status/1", list)
+	assert.NoError(t, err, "SignList should not return an error")
+
+	isValid, err := VerifyWithStatus(pp, vk, m1, sig1, 3, signed)
+	assert.NoError(t, err, "VerifyWithStatus should not return an error")
+	assert.True(t, isValid, "Unrevoked credential should be accepted")
+
+	isValid, err = VerifyWithStatus(pp, vk, m2, sig2, 3, signed)
+	assert.NoError(t, err, "VerifyWithStatus should not return an error")
+	assert.False(t, isValid, "Revoked credential should be rejected")
+}
+
+// TestTamperedList tests that a modified status list is rejected.
+func TestTamperedList(t *testing.T) {
+	result, err := keygen.KeyGen(3)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	pp, sk, vk := result.PublicParameters, result.SigningKey, result.VerificationKey
+
+	list, _ := New(1024)
+	assert.NoError(t, list.SetRevoked(7, true))
+	signed, err := SignList(pp, sk, "list-1", list)
+	assert.NoError(t, err, "SignList should not return an error")
+
+	// Replace the encoded list with one in which nothing is revoked
+	empty, _ := New(1024)
+	signed.EncodedList, _ = empty.Encode()
+
+	_, err = CheckStatus(pp, vk, signed, 7)
+	assert.Error(t, err, "CheckStatus should reject a tampered list")
+}
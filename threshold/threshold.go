@@ -0,0 +1,358 @@
+package threshold
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aniagut/msc-bbs-plus-plus/models"
+	"github.com/aniagut/msc-bbs-plus-plus/network"
+	"github.com/aniagut/msc-bbs-plus-plus/shamir"
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	"github.com/aniagut/msc-bbs-plus-plus/verify"
+	e "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+// The protocol computes A = C^{1/(x+e)} without reconstructing x, using a preprocessed
+// multiplication triple (a, b, c = a·b) that is Shamir-shared like x:
+//
+//  1. The signers open d = (x + e) - b.
+//  2. They open u = a·(x + e) = d·a + c and compute C^a from their shares of a.
+//  3. The combiner computes A = (C^a)^{1/u}.
+//
+// d and u are uniformly masked by b and a, so nothing about x is revealed. The protocol is
+// secure against semi-honest parties; the combiner verifies the final signature, so
+// misbehaving signers can make signing fail but cannot make it output an invalid signature.
+
+// Rounds of one signing session.
+const (
+	roundRequest = iota
+	roundMaskedKey
+	roundOpening
+	roundPartial
+	roundsPerSession
+)
+
+// KeyShare is a signer's Shamir share of the signing key x.
+type KeyShare struct {
+	Index int
+	X     *e.Scalar
+}
+
+// TripleShare is a signer's Shamir share of a preprocessed multiplication triple (a, b, c = a·b).
+// Every triple must be used for a single signature only.
+type TripleShare struct {
+	ID int
+	A  *e.Scalar
+	B  *e.Scalar
+	C  *e.Scalar
+}
+
+// Node is a signer node holding a key share and a pool of preprocessed triples.
+type Node struct {
+	share     KeyShare
+	threshold int
+
+	mu      sync.Mutex
+	triples map[int]TripleShare
+}
+
+// signRequest is sent by the combiner to the signers of a session.
+type signRequest struct {
+	Session  int
+	Signers  []int
+	E        *e.Scalar
+	Messages []string
+}
+
+// partialSignature is the second contribution of a signer: u_i and R_i = C^{λ_i·a_i}.
+type partialSignature struct {
+	U *e.Scalar
+	R *e.G1
+}
+
+// Deal splits a signing key into n key shares with threshold t (trusted dealer setup).
+func Deal(signingKey models.SigningKey, n, t int) ([]KeyShare, error) {
+	shares, _, err := shamir.Split(signingKey.X, n, t)
+	if err != nil {
+		return nil, err
+	}
+	keyShares := make([]KeyShare, n)
+	for i, share := range shares {
+		keyShares[i] = KeyShare{Index: share.Index, X: share.Value}
+	}
+	return keyShares, nil
+}
+
+// Preprocess generates count multiplication triples shared among n signers with threshold t.
+// It runs in a trusted preprocessing phase, independent of the key and of the messages.
+//
+// Returns:
+//   - map[int][]TripleShare: The triple shares of each signer, indexed by signer index (1..n).
+//   - error: An error if the parameters are invalid or randomness generation fails.
+func Preprocess(n, t, count int) (map[int][]TripleShare, error) {
+	triples := make(map[int][]TripleShare, n)
+	for id := 0; id < count; id++ {
+		random, err := utils.RandomScalars(2)
+		if err != nil {
+			return nil, err
+		}
+		c := new(e.Scalar)
+		c.Mul(random[0], random[1])
+
+		aShares, _, err := shamir.Split(random[0], n, t)
+		if err != nil {
+			return nil, err
+		}
+		bShares, _, err := shamir.Split(random[1], n, t)
+		if err != nil {
+			return nil, err
+		}
+		cShares, _, err := shamir.Split(c, n, t)
+		if err != nil {
+			return nil, err
+		}
+		for k := 0; k < n; k++ {
+			index := aShares[k].Index
+			triples[index] = append(triples[index], TripleShare{
+				ID: id,
+				A:  aShares[k].Value,
+				B:  bShares[k].Value,
+				C:  cShares[k].Value,
+			})
+		}
+	}
+	return triples, nil
+}
+
+// NewNode creates a signer node.
+//
+// Parameters:
+//   - share: The node's share of the signing key.
+//   - t: The signing threshold.
+//   - triples: The node's shares of the preprocessed triples.
+func NewNode(share KeyShare, t int, triples []TripleShare) *Node {
+	pool := make(map[int]TripleShare, len(triples))
+	for _, triple := range triples {
+		pool[triple.ID] = triple
+	}
+	return &Node{share: share, threshold: t, triples: pool}
+}
+
+// Index returns the share index of the node.
+func (n *Node) Index() int {
+	return n.share.Index
+}
+
+// takeTriple removes the triple with the given identifier from the pool.
+func (n *Node) takeTriple(id int) (TripleShare, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	triple, ok := n.triples[id]
+	if !ok {
+		return TripleShare{}, errors.New("preprocessed triple is not available")
+	}
+	delete(n.triples, id)
+	return triple, nil
+}
+
+// HandleRequest waits for a signing request from the combiner and runs the signer side of one session.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - transport: The node's connection to the combiner.
+//   - combiner: The identifier of the combiner on the network.
+//   - session: The session to serve; it selects the preprocessed triple.
+//
+// Returns:
+//   - error: An error if the request is invalid or the protocol fails.
+func (n *Node) HandleRequest(publicParams models.PublicParameters, transport network.Transport, combiner int, session int) error {
+	base := session * roundsPerSession
+
+	// Round 1: Receive the request
+	messages, err := transport.Receive(base+roundRequest, 1)
+	if err != nil {
+		return err
+	}
+	request, ok := messages[0].Payload.(signRequest)
+	if !ok || messages[0].From != combiner || request.Session != session {
+		return errors.New("invalid signing request")
+	}
+	if len(request.Signers) != n.threshold {
+		return errors.New("signing set does not match the threshold")
+	}
+	lambda, err := shamir.LagrangeCoefficient(n.share.Index, request.Signers)
+	if err != nil {
+		return err
+	}
+	triple, err := n.takeTriple(session)
+	if err != nil {
+		return err
+	}
+	c, err := utils.ComputeCommitment(request.Messages, publicParams.H1, publicParams.G1)
+	if err != nil {
+		return err
+	}
+
+	// Round 2: Send the additive share λ_i·(x_i - b_i) of d - e
+	masked := new(e.Scalar)
+	masked.Sub(n.share.X, triple.B)
+	masked.Mul(masked, lambda)
+	if err := transport.Send(combiner, base+roundMaskedKey, masked); err != nil {
+		return err
+	}
+
+	// Round 3: Receive the opened d = (x + e) - b
+	messages, err = transport.Receive(base+roundOpening, 1)
+	if err != nil {
+		return err
+	}
+	d, ok := messages[0].Payload.(*e.Scalar)
+	if !ok || messages[0].From != combiner {
+		return errors.New("invalid opening")
+	}
+
+	// Round 4: Send u_i = λ_i·(d·a_i + c_i) and R_i = C^{λ_i·a_i}
+	u := new(e.Scalar)
+	u.Mul(d, triple.A)
+	u.Add(u, triple.C)
+	u.Mul(u, lambda)
+	aLambda := new(e.Scalar)
+	aLambda.Mul(triple.A, lambda)
+	r := new(e.G1)
+	r.ScalarMult(aLambda, c)
+	return transport.Send(combiner, base+roundPartial, partialSignature{U: u, R: r})
+}
+
+// Combine runs the combiner side of a signing session and outputs an ordinary BBS++ signature.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - verificationKey: The (unchanged) verification key of the issuer.
+//   - transport: The combiner's connection to the signers.
+//   - signers: The indexes of the t signers taking part in the session.
+//   - m: The messages to be signed.
+//   - session: The session identifier; it selects the preprocessed triple.
+//
+// Returns:
+//   - signature: The generated signature, verified under verificationKey.
+//   - error: An error if a signer does not respond or the result is invalid.
+func Combine(publicParams models.PublicParameters, verificationKey models.VerificationKey, transport network.Transport, signers []int, m []string, session int) (models.Signature, error) {
+	base := session * roundsPerSession
+
+	// Step 1: Choose e and send the request to the signers
+	elem, err := utils.RandomScalar()
+	if err != nil {
+		return models.Signature{}, err
+	}
+	request := signRequest{Session: session, Signers: signers, E: &elem, Messages: m}
+	for _, signer := range signers {
+		if err := transport.Send(signer, base+roundRequest, request); err != nil {
+			return models.Signature{}, err
+		}
+	}
+
+	// Step 2: Open d = Σ λ_i·(x_i - b_i) + e = (x + e) - b
+	messages, err := receiveFromSigners(transport, base+roundMaskedKey, signers)
+	if err != nil {
+		return models.Signature{}, err
+	}
+	d := new(e.Scalar)
+	d.Set(&elem)
+	for _, msg := range messages {
+		share, ok := msg.Payload.(*e.Scalar)
+		if !ok {
+			return models.Signature{}, fmt.Errorf("invalid masked key share from signer %d", msg.From)
+		}
+		d.Add(d, share)
+	}
+	for _, signer := range signers {
+		if err := transport.Send(signer, base+roundOpening, d); err != nil {
+			return models.Signature{}, err
+		}
+	}
+
+	// Step 3: Open u = a·(x + e) and compute R = C^a
+	messages, err = receiveFromSigners(transport, base+roundPartial, signers)
+	if err != nil {
+		return models.Signature{}, err
+	}
+	u := new(e.Scalar)
+	r := new(e.G1)
+	r.SetIdentity()
+	for _, msg := range messages {
+		partial, ok := msg.Payload.(partialSignature)
+		if !ok {
+			return models.Signature{}, fmt.Errorf("invalid partial signature from signer %d", msg.From)
+		}
+		u.Add(u, partial.U)
+		r.Add(r, partial.R)
+	}
+	if u.IsZero() == 1 {
+		return models.Signature{}, errors.New("x + e is zero, retry with a new session")
+	}
+
+	// Step 4: Compute A = R^{1/u} = C^{1/(x+e)} and verify the result
+	u.Inv(u)
+	A := new(e.G1)
+	A.ScalarMult(u, r)
+	signature := models.Signature{A: A, E: &elem}
+
+	isValid, err := verify.Verify(publicParams, verificationKey, m, signature)
+	if err != nil {
+		return models.Signature{}, err
+	}
+	if !isValid {
+		return models.Signature{}, errors.New("threshold signature is invalid")
+	}
+	return signature, nil
+}
+
+// receiveFromSigners receives exactly one message of the round from each signer.
+func receiveFromSigners(transport network.Transport, round int, signers []int) ([]network.Message, error) {
+	messages, err := transport.Receive(round, len(signers))
+	if err != nil {
+		return nil, err
+	}
+	expected := make(map[int]bool, len(signers))
+	for _, signer := range signers {
+		expected[signer] = true
+	}
+	for _, msg := range messages {
+		if !expected[msg.From] {
+			return nil, fmt.Errorf("unexpected message from party %d", msg.From)
+		}
+		delete(expected, msg.From)
+	}
+	return messages, nil
+}
+
+// SignLocal runs a signing session between the combiner and the given signer nodes on an
+// in-process simulated network. The combiner has identifier 0 and signer i has identifier i.
+func SignLocal(publicParams models.PublicParameters, verificationKey models.VerificationKey, nodes []*Node, m []string, session int, timeout time.Duration) (models.Signature, error) {
+	parties := []int{0}
+	signers := make([]int, len(nodes))
+	for i, node := range nodes {
+		signers[i] = node.Index()
+		parties = append(parties, node.Index())
+	}
+	net := network.NewMemory(parties, timeout)
+
+	errs := make(chan error, len(nodes))
+	for _, node := range nodes {
+		go func(node *Node) {
+			errs <- node.HandleRequest(publicParams, net.Endpoint(node.Index()), 0, session)
+		}(node)
+	}
+	signature, err := Combine(publicParams, verificationKey, net.Endpoint(0), signers, m, session)
+	for range nodes {
+		if nodeErr := <-errs; nodeErr != nil && err == nil {
+			err = nodeErr
+		}
+	}
+	if err != nil {
+		return models.Signature{}, err
+	}
+	return signature, nil
+}
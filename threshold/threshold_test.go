@@ -0,0 +1,78 @@
+package threshold
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aniagut/msc-bbs-plus-plus/keygen"
+	"github.com/aniagut/msc-bbs-plus-plus/models"
+	"github.com/aniagut/msc-bbs-plus-plus/verify"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupNodes deals a fresh key among n nodes with threshold t and preprocesses triples.
+func setupNodes(t *testing.T, n, threshold, triples int) (models.KeyGenResult, []*Node) {
+	result, err := keygen.KeyGen(3)
+	assert.NoError(t, err, "KeyGen should not return an error")
+
+	shares, err := Deal(result.SigningKey, n, threshold)
+	assert.NoError(t, err, "Deal should not return an error")
+	pool, err := Preprocess(n, threshold, triples)
+	assert.NoError(t, err, "Preprocess should not return an error")
+
+	nodes := make([]*Node, n)
+	for i, share := range shares {
+		nodes[i] = NewNode(share, threshold, pool[share.Index])
+	}
+	return result, nodes
+}
+
+// TestThresholdSign tests that any t nodes produce a signature accepted by verify.Verify.
+func TestThresholdSign(t *testing.T) {
+	result, nodes := setupNodes(t, 5, 3, 3)
+	messages := []string{"message1", "message2", "message3"}
+
+	for session, signers := range [][]*Node{
+		{nodes[0], nodes[1], nodes[2]},
+		{nodes[4], nodes[2], nodes[0]},
+		{nodes[1], nodes[3], nodes[4]},
+	} {
+		signature, err := SignLocal(result.PublicParameters, result.VerificationKey, signers, messages, session, time.Second)
+		assert.NoError(t, err, "SignLocal should not return an error")
+
+		isValid, err := verify.Verify(result.PublicParameters, result.VerificationKey, messages, signature)
+		assert.NoError(t, err, "Verify should not return an error")
+		assert.True(t, isValid, "Threshold signature should be valid under the unchanged verification key")
+	}
+}
+
+// TestThresholdTooFewSigners tests that fewer than t signers cannot sign.
+func TestThresholdTooFewSigners(t *testing.T) {
+	result, nodes := setupNodes(t, 5, 3, 1)
+	messages := []string{"message1", "message2", "message3"}
+
+	_, err := SignLocal(result.PublicParameters, result.VerificationKey, nodes[:2], messages, 0, 200*time.Millisecond)
+	assert.Error(t, err, "Signing with fewer than t nodes should fail")
+}
+
+// TestThresholdTripleReuse tests that a preprocessed triple cannot be used twice.
+func TestThresholdTripleReuse(t *testing.T) {
+	result, nodes := setupNodes(t, 3, 2, 1)
+	messages := []string{"message1", "message2", "message3"}
+
+	_, err := SignLocal(result.PublicParameters, result.VerificationKey, nodes[:2], messages, 0, time.Second)
+	assert.NoError(t, err, "First session should succeed")
+
+	_, err = SignLocal(result.PublicParameters, result.VerificationKey, nodes[:2], messages, 0, 200*time.Millisecond)
+	assert.Error(t, err, "Reusing a triple should fail")
+}
+
+// TestThresholdWrongShare tests that a corrupted key share is detected by the combiner.
+func TestThresholdWrongShare(t *testing.T) {
+	result, nodes := setupNodes(t, 3, 2, 1)
+	messages := []string{"message1", "message2", "message3"}
+	nodes[1].share.X.SetUint64(1)
+
+	_, err := SignLocal(result.PublicParameters, result.VerificationKey, nodes[:2], messages, 0, time.Second)
+	assert.Error(t, err, "A corrupted share should not produce a signature")
+}
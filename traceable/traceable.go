@@ -0,0 +1,498 @@
+package traceable
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/aniagut/msc-bbs-plus-plus/keygen"
+	"github.com/aniagut/msc-bbs-plus-plus/models"
+	"github.com/aniagut/msc-bbs-plus-plus/proof"
+	"github.com/aniagut/msc-bbs-plus-plus/sign"
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	"github.com/aniagut/msc-bbs-plus-plus/verify"
+	e "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+const (
+	// signDomain separates traceable signature challenges from other hashes.
+	signDomain = "BBS++-TRACEABLE-SIGNATURE-V1"
+	// claimDomain separates claim challenges from other hashes.
+	claimDomain = "BBS++-TRACEABLE-CLAIM-V1"
+	// joinDomain separates the challenges of join requests from other hashes.
+	joinDomain = "BBS++-TRACEABLE-JOIN-V1"
+	// baseDomain is the domain separation tag used to derive fresh tracing bases.
+	baseDomain = "BBS++-TRACEABLE-TAG-BASE-V1"
+	// secretIndex is the position of the member's tracing secret in the credential.
+	secretIndex = 0
+	// claimIndex is the position of the member's claim secret in the credential.
+	claimIndex = 1
+)
+
+// ErrUnknownSigner is returned by Open when the signer is not a registered member.
+var ErrUnknownSigner = errors.New("signer is not a registered group member")
+
+// PublicKey is the group public key: the BBS++ parameters and verification key of the group
+// manager and the ElGamal key Y = g1^y used to open signatures.
+type PublicKey struct {
+	PublicParameters models.PublicParameters
+	VerificationKey  models.VerificationKey
+	Y                *e.G1
+}
+
+// MemberKey is a member's BBS++ credential on its tracing secret t and its claim secret x′.
+// The group manager chooses t and learns it; x′ is chosen by the member and never leaves it,
+// so that only the member can claim its signatures.
+type MemberKey struct {
+	Index       int
+	Secret      string
+	ClaimSecret string
+	Signature   models.Signature
+}
+
+// JoinRequest is the commitment Q = h₁[1]^{x′} to a joining member's claim secret with a proof
+// of knowledge of x′ bound to the manager's nonce.
+type JoinRequest struct {
+	Q         *e.G1
+	Challenge *e.Scalar
+	Response  *e.Scalar
+}
+
+// JoinResponse is the manager's answer to a join request: the member's index, its tracing
+// secret and the credential on both secrets.
+type JoinResponse struct {
+	Index     int
+	Secret    string
+	Signature models.Signature
+}
+
+// PendingJoin is the member's state between the join request and the manager's response.
+type PendingJoin struct {
+	Request     JoinRequest
+	claimSecret string
+}
+
+// Trapdoor is the member-specific tracing trapdoor t released by the group manager. Anyone
+// holding it can recognize the member's signatures without being able to open any other or to
+// claim the member's signatures.
+type Trapdoor struct {
+	Index int
+	T     *e.Scalar
+}
+
+// Signature is a traceable group signature: a proof of knowledge of a credential whose hidden
+// tracing secret t and claim secret x′ also satisfy
+//   - Tag = Base^t for a fresh random Base (tested with a trapdoor),
+//   - ClaimTag = ClaimBase^{x′} for a fresh random ClaimBase (used to claim), and
+//   - (E1, E2) = (g1^k, g1^t · Y^k), an encryption of g1^t for the group manager (used to open).
+type Signature struct {
+	Proof     models.Proof
+	Base      *e.G1
+	Tag       *e.G1
+	ClaimBase *e.G1
+	ClaimTag  *e.G1
+	E1        *e.G1
+	E2        *e.G1
+	KHat      *e.Scalar
+}
+
+// Claim proves that the holder of a claim secret produced a given signature.
+type Claim struct {
+	Challenge *e.Scalar
+	Response  *e.Scalar
+}
+
+// Manager is the group manager: it issues credentials, opens signatures and releases trapdoors.
+// It never learns the members' claim secrets.
+type Manager struct {
+	publicKey  PublicKey
+	signingKey models.SigningKey
+	y          *e.Scalar
+
+	mu      sync.Mutex
+	secrets map[int]*e.Scalar
+	openers map[string]int
+}
+
+// Setup generates the group public key and the group manager.
+//
+// Returns:
+//   - PublicKey: The group public key.
+//   - *Manager: The group manager holding the issuing and opening keys.
+//   - error: An error if the key generation fails.
+func Setup() (PublicKey, *Manager, error) {
+	// 1. Generate the BBS++ keys for credentials on the tracing secret and the claim secret
+	result, err := keygen.KeyGen(2)
+	if err != nil {
+		return PublicKey{}, nil, err
+	}
+
+	// 2. Generate the opening key y and Y = g1^y
+	y, err := utils.RandomScalar()
+	if err != nil {
+		return PublicKey{}, nil, err
+	}
+	Y := new(e.G1)
+	Y.ScalarMult(&y, result.PublicParameters.G1)
+
+	publicKey := PublicKey{
+		PublicParameters: result.PublicParameters,
+		VerificationKey:  result.VerificationKey,
+		Y:                Y,
+	}
+	return publicKey, &Manager{
+		publicKey:  publicKey,
+		signingKey: result.SigningKey,
+		y:          &y,
+		secrets:    make(map[int]*e.Scalar),
+		openers:    make(map[string]int),
+	}, nil
+}
+
+// RequestJoin creates a join request: it chooses the member's claim secret x′ and commits to it.
+//
+// Parameters:
+//   - publicKey: The group public key.
+//   - nonce: The nonce chosen by the group manager.
+//
+// Returns:
+//   - *PendingJoin: The member's state, which contains the request to be sent.
+//   - error: An error if randomness generation fails.
+func RequestJoin(publicKey PublicKey, nonce []byte) (*PendingJoin, error) {
+	claimSecret, err := randomSecret()
+	if err != nil {
+		return nil, err
+	}
+	h := &publicKey.PublicParameters.H1[claimIndex]
+	x := utils.MessageToScalar(claimSecret)
+	r, err := utils.RandomScalar()
+	if err != nil {
+		return nil, err
+	}
+	Q := new(e.G1)
+	Q.ScalarMult(x, h)
+	R := new(e.G1)
+	R.ScalarMult(&r, h)
+	challenge := joinChallenge(Q, R, nonce)
+	return &PendingJoin{
+		Request:     JoinRequest{Q: Q, Challenge: challenge, Response: proof.Response(&r, challenge, x)},
+		claimSecret: claimSecret,
+	}, nil
+}
+
+// Join admits a new member: it checks the join request, chooses the member's tracing secret and
+// issues a credential on it and on the committed claim secret.
+//
+// Parameters:
+//   - request: The join request of the member.
+//   - nonce: The nonce the request was bound to.
+//
+// Returns:
+//   - JoinResponse: The member's index, tracing secret and credential.
+//   - error: An error if the request is invalid or the credential cannot be issued.
+func (m *Manager) Join(request JoinRequest, nonce []byte) (JoinResponse, error) {
+	if request.Q == nil || request.Challenge == nil || request.Response == nil || request.Q.IsIdentity() {
+		return JoinResponse{}, errors.New("join request is missing components")
+	}
+	publicParams := m.publicKey.PublicParameters
+
+	// Step 1: Recompute R = h₁[1]^s · Q^{-c} and check the challenge
+	R := utils.MultiExpG1([]*e.G1{&publicParams.H1[claimIndex], request.Q}, []*e.Scalar{request.Response, utils.Neg(request.Challenge)})
+	if joinChallenge(request.Q, R, nonce).IsEqual(request.Challenge) != 1 {
+		return JoinResponse{}, errors.New("invalid proof of knowledge of the claim secret")
+	}
+
+	// Step 2: Choose the tracing secret t and sign C = g1 · h₁[0]^t · Q
+	secret, err := randomSecret()
+	if err != nil {
+		return JoinResponse{}, err
+	}
+	t := utils.MessageToScalar(secret)
+	c := utils.MultiExpG1([]*e.G1{publicParams.G1, &publicParams.H1[secretIndex], request.Q}, []*e.Scalar{utils.One(), t, utils.One()})
+	signature, err := sign.SignCommitment(m.signingKey, c)
+	if err != nil {
+		return JoinResponse{}, err
+	}
+
+	opener := new(e.G1)
+	opener.ScalarMult(t, publicParams.G1)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	index := len(m.secrets) + 1
+	m.secrets[index] = t
+	m.openers[string(opener.BytesCompressed())] = index
+	return JoinResponse{Index: index, Secret: secret, Signature: signature}, nil
+}
+
+// Finalize checks the credential received from the group manager and returns the member key.
+func (p *PendingJoin) Finalize(publicKey PublicKey, response JoinResponse) (MemberKey, error) {
+	memberKey := MemberKey{Index: response.Index, Secret: response.Secret, ClaimSecret: p.claimSecret, Signature: response.Signature}
+	ok, err := verify.Verify(publicKey.PublicParameters, publicKey.VerificationKey, []string{memberKey.Secret, memberKey.ClaimSecret}, memberKey.Signature)
+	if err != nil {
+		return MemberKey{}, err
+	}
+	if !ok {
+		return MemberKey{}, errors.New("member credential is invalid")
+	}
+	return memberKey, nil
+}
+
+// Trapdoor releases the tracing trapdoor of a member.
+func (m *Manager) Trapdoor(index int) (Trapdoor, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t, ok := m.secrets[index]
+	if !ok {
+		return Trapdoor{}, fmt.Errorf("member %d is not registered", index)
+	}
+	return Trapdoor{Index: index, T: t}, nil
+}
+
+// Open identifies the signer of a valid signature by decrypting g1^t = E2 · E1^{-y}.
+//
+// Returns:
+//   - int: The index of the signer.
+//   - error: ErrUnknownSigner if the signer is not registered, or an error if the signature is invalid.
+func (m *Manager) Open(message string, signature Signature) (int, error) {
+	isValid, err := Verify(m.publicKey, message, signature)
+	if err != nil {
+		return 0, err
+	}
+	if !isValid {
+		return 0, errors.New("cannot open an invalid signature")
+	}
+	opener := utils.MultiExpG1([]*e.G1{signature.E2, signature.E1}, []*e.Scalar{utils.One(), utils.Neg(m.y)})
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	index, ok := m.openers[string(opener.BytesCompressed())]
+	if !ok {
+		return 0, ErrUnknownSigner
+	}
+	return index, nil
+}
+
+// Sign produces a traceable group signature on a message.
+//
+// Parameters:
+//   - publicKey: The group public key.
+//   - memberKey: The credential of the signing member.
+//   - message: The message to be signed.
+//
+// Returns:
+//   - Signature: The traceable signature.
+//   - error: An error if the signing process fails.
+func Sign(publicKey PublicKey, memberKey MemberKey, message string) (Signature, error) {
+	publicParams := publicKey.PublicParameters
+
+	// Step 1: Commitment phase of the credential proof, hiding both secrets
+	prover, err := proof.NewProver(publicParams, memberKey.Signature, []string{memberKey.Secret, memberKey.ClaimSecret}, nil, nil)
+	if err != nil {
+		return Signature{}, err
+	}
+	t, tTilde := prover.Message(secretIndex), prover.Blinding(secretIndex)
+	x, xTilde := prover.Message(claimIndex), prover.Blinding(claimIndex)
+
+	// Step 2: Compute Tag = Base^t and ClaimTag = ClaimBase^{x′} for fresh bases and the
+	// encryption (g1^k, g1^t · Y^k)
+	base, err := randomBase()
+	if err != nil {
+		return Signature{}, err
+	}
+	claimBase, err := randomBase()
+	if err != nil {
+		return Signature{}, err
+	}
+	random, err := utils.RandomScalars(2)
+	if err != nil {
+		return Signature{}, err
+	}
+	k, kTilde := random[0], random[1]
+	signature := Signature{Base: base, Tag: new(e.G1), ClaimBase: claimBase, ClaimTag: new(e.G1), E1: new(e.G1)}
+	signature.Tag.ScalarMult(t, signature.Base)
+	signature.ClaimTag.ScalarMult(x, signature.ClaimBase)
+	signature.E1.ScalarMult(k, publicParams.G1)
+	signature.E2 = utils.MultiExpG1([]*e.G1{publicParams.G1, publicKey.Y}, []*e.Scalar{t, k})
+
+	// Step 3: Commit to the same t̃ and x̃′ as the credential proof
+	w1 := new(e.G1)
+	w1.ScalarMult(tTilde, signature.Base)
+	w2 := new(e.G1)
+	w2.ScalarMult(kTilde, publicParams.G1)
+	w3 := utils.MultiExpG1([]*e.G1{publicParams.G1, publicKey.Y}, []*e.Scalar{tTilde, kTilde})
+	w4 := new(e.G1)
+	w4.ScalarMult(xTilde, signature.ClaimBase)
+
+	// Step 4: Derive the challenge and compute the responses
+	challenge := signChallenge(prover.Bytes(), signature, w1, w2, w3, w4, message)
+	signature.Proof = prover.Respond(challenge)
+	signature.KHat = proof.Response(kTilde, challenge, k)
+	return signature, nil
+}
+
+// Verify checks a traceable group signature on a message.
+//
+// Parameters:
+//   - publicKey: The group public key.
+//   - message: The signed message.
+//   - signature: The traceable signature.
+//
+// Returns:
+//   - boolean: True if the signature is valid, false otherwise.
+//   - error: An error if the signature is malformed.
+func Verify(publicKey PublicKey, message string, signature Signature) (bool, error) {
+	publicParams := publicKey.PublicParameters
+	tHat, ok := signature.Proof.MHat[secretIndex]
+	xHat, claimOK := signature.Proof.MHat[claimIndex]
+	if !ok || !claimOK || tHat == nil || xHat == nil || len(signature.Proof.MHat) != 2 {
+		return false, errors.New("member secrets are not hidden in the signature")
+	}
+	if signature.Base == nil || signature.Tag == nil || signature.ClaimBase == nil || signature.ClaimTag == nil ||
+		signature.E1 == nil || signature.E2 == nil || signature.KHat == nil {
+		return false, errors.New("signature is missing components")
+	}
+	if signature.Base.IsIdentity() || signature.ClaimBase.IsIdentity() {
+		return false, nil
+	}
+
+	// Step 1: Recompute the credential proof transcript and check the pairing equation
+	transcript, err := proof.TranscriptBytes(publicParams, signature.Proof, nil)
+	if err != nil {
+		return false, err
+	}
+	if !proof.CheckPairing(publicParams, publicKey.VerificationKey, signature.Proof) {
+		return false, nil
+	}
+	challenge := signature.Proof.Challenge
+	negC := utils.Neg(challenge)
+
+	// Step 2: Recompute W1 = Base^{t̂}·Tag^{-c}, W2 = g1^{k̂}·E1^{-c}, W3 = g1^{t̂}·Y^{k̂}·E2^{-c}
+	// and W4 = ClaimBase^{x̂′}·ClaimTag^{-c}
+	w1 := utils.MultiExpG1([]*e.G1{signature.Base, signature.Tag}, []*e.Scalar{tHat, negC})
+	w2 := utils.MultiExpG1([]*e.G1{publicParams.G1, signature.E1}, []*e.Scalar{signature.KHat, negC})
+	w3 := utils.MultiExpG1([]*e.G1{publicParams.G1, publicKey.Y, signature.E2}, []*e.Scalar{tHat, signature.KHat, negC})
+	w4 := utils.MultiExpG1([]*e.G1{signature.ClaimBase, signature.ClaimTag}, []*e.Scalar{xHat, negC})
+
+	// Step 3: Check the challenge
+	expected := signChallenge(transcript, signature, w1, w2, w3, w4, message)
+	return expected.IsEqual(challenge) == 1, nil
+}
+
+// Trace tests whether a signature was produced by the member of the trapdoor: Tag = Base^t.
+func Trace(trapdoor Trapdoor, signature Signature) bool {
+	if signature.Base == nil || signature.Tag == nil {
+		return false
+	}
+	tag := new(e.G1)
+	tag.ScalarMult(trapdoor.T, signature.Base)
+	return tag.IsEqual(signature.Tag)
+}
+
+// TraceAll returns the positions of the signatures produced by the member of the trapdoor.
+func TraceAll(trapdoor Trapdoor, signatures []Signature) []int {
+	var matches []int
+	for i, signature := range signatures {
+		if Trace(trapdoor, signature) {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+// ClaimSignature proves that the member produced the signature by proving knowledge of x′
+// with ClaimTag = ClaimBase^{x′}, bound to the signature and a verifier-supplied nonce. The
+// tracing trapdoor t does not help to produce a claim.
+//
+// Returns:
+//   - Claim: The claim proof.
+//   - error: An error if the member did not produce the signature.
+func ClaimSignature(memberKey MemberKey, signature Signature, nonce []byte) (Claim, error) {
+	if signature.ClaimBase == nil || signature.ClaimTag == nil {
+		return Claim{}, errors.New("signature is missing components")
+	}
+	x := utils.MessageToScalar(memberKey.ClaimSecret)
+	tag := new(e.G1)
+	tag.ScalarMult(x, signature.ClaimBase)
+	if !tag.IsEqual(signature.ClaimTag) {
+		return Claim{}, errors.New("signature was not produced by this member")
+	}
+	r, err := utils.RandomScalar()
+	if err != nil {
+		return Claim{}, err
+	}
+	w := new(e.G1)
+	w.ScalarMult(&r, signature.ClaimBase)
+	challenge := claimChallenge(signature, w, nonce)
+	return Claim{Challenge: challenge, Response: proof.Response(&r, challenge, x)}, nil
+}
+
+// VerifyClaim checks a claim of authorship of a signature.
+func VerifyClaim(signature Signature, claim Claim, nonce []byte) (bool, error) {
+	if claim.Challenge == nil || claim.Response == nil {
+		return false, errors.New("claim is missing components")
+	}
+	if signature.ClaimBase == nil || signature.ClaimTag == nil || signature.Proof.Challenge == nil || signature.ClaimBase.IsIdentity() {
+		return false, errors.New("signature is missing components")
+	}
+	w := utils.MultiExpG1([]*e.G1{signature.ClaimBase, signature.ClaimTag}, []*e.Scalar{claim.Response, utils.Neg(claim.Challenge)})
+	expected := claimChallenge(signature, w, nonce)
+	return expected.IsEqual(claim.Challenge) == 1, nil
+}
+
+// signChallenge hashes the credential proof transcript, the tracing, claiming and opening values
+// and the message.
+func signChallenge(transcript []byte, signature Signature, w1, w2, w3, w4 *e.G1, message string) *e.Scalar {
+	return utils.HashToScalar(
+		[]byte(signDomain),
+		transcript,
+		signature.Base.BytesCompressed(),
+		signature.Tag.BytesCompressed(),
+		signature.ClaimBase.BytesCompressed(),
+		signature.ClaimTag.BytesCompressed(),
+		signature.E1.BytesCompressed(),
+		signature.E2.BytesCompressed(),
+		w1.BytesCompressed(),
+		w2.BytesCompressed(),
+		w3.BytesCompressed(),
+		w4.BytesCompressed(),
+		[]byte(message),
+	)
+}
+
+// claimChallenge hashes the claimed signature, the claim commitment and the nonce.
+func claimChallenge(signature Signature, w *e.G1, nonce []byte) *e.Scalar {
+	return utils.HashToScalar(
+		[]byte(claimDomain),
+		utils.ScalarToBytes(signature.Proof.Challenge),
+		signature.ClaimBase.BytesCompressed(),
+		signature.ClaimTag.BytesCompressed(),
+		w.BytesCompressed(),
+		nonce,
+	)
+}
+
+// joinChallenge hashes the commitment to the claim secret, the proof commitment and the nonce.
+func joinChallenge(Q, R *e.G1, nonce []byte) *e.Scalar {
+	return utils.HashToScalar([]byte(joinDomain), Q.BytesCompressed(), R.BytesCompressed(), nonce)
+}
+
+// randomSecret returns a fresh 256-bit secret encoded as a message.
+func randomSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", errors.New("failed to generate member secret")
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// randomBase derives a fresh base from a random salt, so that nobody knows its discrete logarithm.
+func randomBase() (*e.G1, error) {
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, errors.New("failed to generate random base")
+	}
+	return utils.HashToG1(salt, baseDomain), nil
+}
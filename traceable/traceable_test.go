@@ -0,0 +1,149 @@
+package traceable
+
+import (
+	"testing"
+
+	"github.com/aniagut/msc-bbs-plus-plus/proof"
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	e "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupGroup creates a group with the given number of members.
+func setupGroup(t *testing.T, members int) (PublicKey, *Manager, []MemberKey) {
+	publicKey, manager, err := Setup()
+	assert.NoError(t, err, "Setup should not return an error")
+	keys := make([]MemberKey, members)
+	nonce := []byte("nonce")
+	for i := range keys {
+		pending, err := RequestJoin(publicKey, nonce)
+		assert.NoError(t, err, "RequestJoin should not return an error")
+		response, err := manager.Join(pending.Request, nonce)
+		assert.NoError(t, err, "Join should not return an error")
+		keys[i], err = pending.Finalize(publicKey, response)
+		assert.NoError(t, err, "Finalize should not return an error")
+	}
+	return publicKey, manager, keys
+}
+
+// TestJoin tests that the manager rejects join requests it cannot trust.
+func TestJoin(t *testing.T) {
+	publicKey, manager, err := Setup()
+	assert.NoError(t, err, "Setup should not return an error")
+	pending, err := RequestJoin(publicKey, []byte("nonce"))
+	assert.NoError(t, err, "RequestJoin should not return an error")
+
+	_, err = manager.Join(pending.Request, []byte("other nonce"))
+	assert.Error(t, err, "Join should reject a request bound to another nonce")
+
+	response, err := manager.Join(pending.Request, []byte("nonce"))
+	assert.NoError(t, err, "Join should not return an error")
+	response.Secret = "other secret"
+	_, err = pending.Finalize(publicKey, response)
+	assert.Error(t, err, "Finalize should reject an invalid credential")
+}
+
+// TestSignVerifyOpen tests that traceable signatures verify, are bound to the message and open to the signer.
+func TestSignVerifyOpen(t *testing.T) {
+	publicKey, manager, keys := setupGroup(t, 2)
+
+	signature, err := Sign(publicKey, keys[1], "message")
+	assert.NoError(t, err, "Sign should not return an error")
+
+	isValid, err := Verify(publicKey, "message", signature)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.True(t, isValid, "Verify should accept a valid signature")
+
+	isValid, err = Verify(publicKey, "other message", signature)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.False(t, isValid, "Verify should reject a signature on another message")
+
+	index, err := manager.Open("message", signature)
+	assert.NoError(t, err, "Open should not return an error")
+	assert.Equal(t, keys[1].Index, index, "Open should identify the signer")
+}
+
+// TestTrace tests that a trapdoor finds exactly the signatures of its member.
+func TestTrace(t *testing.T) {
+	publicKey, manager, keys := setupGroup(t, 3)
+
+	var signatures []Signature
+	signers := []int{0, 1, 0, 2, 0}
+	for _, signer := range signers {
+		signature, err := Sign(publicKey, keys[signer], "message")
+		assert.NoError(t, err, "Sign should not return an error")
+		signatures = append(signatures, signature)
+	}
+
+	trapdoor, err := manager.Trapdoor(keys[0].Index)
+	assert.NoError(t, err, "Trapdoor should not return an error")
+	assert.Equal(t, []int{0, 2, 4}, TraceAll(trapdoor, signatures), "TraceAll should find all signatures of the member")
+
+	_, err = manager.Trapdoor(99)
+	assert.Error(t, err, "Trapdoor should reject an unknown member")
+}
+
+// TestTamperedTag tests that the tracing tag cannot be replaced to evade tracing.
+func TestTamperedTag(t *testing.T) {
+	publicKey, _, keys := setupGroup(t, 2)
+	signature, err := Sign(publicKey, keys[0], "message")
+	assert.NoError(t, err, "Sign should not return an error")
+	other, err := Sign(publicKey, keys[1], "message")
+	assert.NoError(t, err, "Sign should not return an error")
+
+	signature.Base, signature.Tag = other.Base, other.Tag
+	isValid, err := Verify(publicKey, "message", signature)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.False(t, isValid, "Verify should reject a replaced tracing tag")
+}
+
+// TestClaim tests that only the signer can claim a signature.
+func TestClaim(t *testing.T) {
+	publicKey, _, keys := setupGroup(t, 2)
+	signature, err := Sign(publicKey, keys[0], "message")
+	assert.NoError(t, err, "Sign should not return an error")
+	nonce := []byte("nonce")
+
+	claim, err := ClaimSignature(keys[0], signature, nonce)
+	assert.NoError(t, err, "ClaimSignature should not return an error")
+	isValid, err := VerifyClaim(signature, claim, nonce)
+	assert.NoError(t, err, "VerifyClaim should not return an error")
+	assert.True(t, isValid, "VerifyClaim should accept the signer's claim")
+
+	isValid, err = VerifyClaim(signature, claim, []byte("other nonce"))
+	assert.NoError(t, err, "VerifyClaim should not return an error")
+	assert.False(t, isValid, "VerifyClaim should reject a replayed claim")
+
+	_, err = ClaimSignature(keys[1], signature, nonce)
+	assert.Error(t, err, "ClaimSignature should refuse a signature of another member")
+}
+
+// TestTrapdoorCannotClaim tests that the holder of a tracing trapdoor, such as the group manager,
+// cannot claim the member's signatures.
+func TestTrapdoorCannotClaim(t *testing.T) {
+	publicKey, manager, keys := setupGroup(t, 1)
+	signature, err := Sign(publicKey, keys[0], "message")
+	assert.NoError(t, err, "Sign should not return an error")
+	nonce := []byte("nonce")
+
+	trapdoor, err := manager.Trapdoor(keys[0].Index)
+	assert.NoError(t, err, "Trapdoor should not return an error")
+	assert.True(t, Trace(trapdoor, signature), "The trapdoor should trace the signature")
+
+	// Prove knowledge of t for either base, the best a trapdoor holder can do
+	for _, base := range []*e.G1{signature.ClaimBase, signature.Base} {
+		r, err := utils.RandomScalar()
+		assert.NoError(t, err, "RandomScalar should not return an error")
+		w := new(e.G1)
+		w.ScalarMult(&r, base)
+		challenge := claimChallenge(signature, w, nonce)
+		forged := Claim{Challenge: challenge, Response: proof.Response(&r, challenge, trapdoor.T)}
+		isValid, err := VerifyClaim(signature, forged, nonce)
+		assert.NoError(t, err, "VerifyClaim should not return an error")
+		assert.False(t, isValid, "VerifyClaim should reject a claim made with the trapdoor")
+	}
+
+	// The tracing secret alone does not make a member key that claims
+	_, err = ClaimSignature(MemberKey{Index: keys[0].Index, Secret: keys[0].Secret}, signature, nonce)
+	assert.Error(t, err, "ClaimSignature should refuse without the claim secret")
+}
@@ -0,0 +1,257 @@
+package update
+
+import (
+	"encoding/binary"
+	"errors"
+	"sort"
+
+	"github.com/aniagut/msc-bbs-plus-plus/models"
+	"github.com/aniagut/msc-bbs-plus-plus/proof"
+	"github.com/aniagut/msc-bbs-plus-plus/sign"
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	"github.com/aniagut/msc-bbs-plus-plus/verify"
+	e "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+// updateDomain separates the challenges of update requests from other hashes.
+const updateDomain = "BBS++-CREDENTIAL-UPDATE-V1"
+
+// Request asks the issuer to re-issue a credential. Proof is a proof of knowledge of the old
+// signature that reveals the old disclosed messages; Commitment is H = h₁[b]^r · ∏_{kept} h₁[j]^{m_j}
+// for the Blinding position b and a fresh random message r, and the proof shows that H contains
+// the kept hidden messages of the old signature. BlindingResponse is the response for r.
+//
+// The fresh r hides the kept messages in H, so the issuer can neither test guesses of them, such
+// as a low-entropy revocation ID, nor link repeated updates of the same credential.
+type Request struct {
+	Proof            models.Proof
+	Revealed         map[int]string
+	Blinding         int
+	Commitment       *e.G1
+	BlindingResponse *e.Scalar
+}
+
+// Pending is the holder's state between the request and the new signature.
+type Pending struct {
+	Request Request
+	hidden  map[int]string
+}
+
+// NewRequest creates an update request for a credential.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - signature: The old signature on the messages.
+//   - m: The old message vector.
+//   - hidden: The indexes of the messages that stay hidden from the issuer and are carried over.
+//   - blinding: The index of the blinding message; it stays hidden and gets a fresh random value.
+//   - nonce: An issuer-supplied nonce bound to the request.
+//
+// Returns:
+//   - *Pending: The holder's state, which contains the request to be sent.
+//   - error: An error if the inputs are inconsistent or the proof cannot be generated.
+func NewRequest(publicParams models.PublicParameters, signature models.Signature, m []string, hidden []int, blinding int, nonce []byte) (*Pending, error) {
+	if len(m) != len(publicParams.H1) {
+		return nil, errors.New("message vector length does not match h1 length")
+	}
+	if blinding < 0 || blinding >= len(m) {
+		return nil, errors.New("blinding index out of range")
+	}
+	isHidden := make(map[int]bool, len(hidden))
+	for _, j := range hidden {
+		if j < 0 || j >= len(m) {
+			return nil, errors.New("hidden index out of range")
+		}
+		if j == blinding {
+			return nil, errors.New("blinding position cannot be carried over")
+		}
+		isHidden[j] = true
+	}
+	var disclosed []int
+	for i := range m {
+		if !isHidden[i] && i != blinding {
+			disclosed = append(disclosed, i)
+		}
+	}
+
+	// Step 1: Commitment phase of the proof of the old signature
+	prover, err := proof.NewProver(publicParams, signature, m, disclosed, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: Choose the new blinding message r and its proof blinding r̃
+	random, err := utils.RandomMessage()
+	if err != nil {
+		return nil, err
+	}
+	r := utils.MessageToScalar(random)
+	rTilde, err := utils.RandomScalar()
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 3: Commit H = h₁[b]^r · ∏_{kept} h₁[j]^{m_j} and T = h₁[b]^{r̃} · ∏_{kept} h₁[j]^{m̃_j}
+	indexes := sortedHidden(isHidden)
+	points := []*e.G1{&publicParams.H1[blinding]}
+	values := []*e.Scalar{r}
+	blindings := []*e.Scalar{&rTilde}
+	kept := map[int]string{blinding: random}
+	for _, j := range indexes {
+		points = append(points, &publicParams.H1[j])
+		values = append(values, prover.Message(j))
+		blindings = append(blindings, prover.Blinding(j))
+		kept[j] = m[j]
+	}
+	commitment := utils.MultiExpG1(points, values)
+	t := utils.MultiExpG1(points, blindings)
+
+	// Step 4: Derive the challenge and respond
+	challenge := updateChallenge(prover.Bytes(), blinding, commitment, t, nonce)
+	revealed := make(map[int]string, len(disclosed))
+	for _, i := range disclosed {
+		revealed[i] = m[i]
+	}
+	return &Pending{
+		Request: Request{
+			Proof:            prover.Respond(challenge),
+			Revealed:         revealed,
+			Blinding:         blinding,
+			Commitment:       commitment,
+			BlindingResponse: proof.Response(&rTilde, challenge, r),
+		},
+		hidden: kept,
+	}, nil
+}
+
+// Issue checks an update request and signs the hidden messages of the old credential together
+// with the updated disclosed messages.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - signingKey: The signing key of the issuer.
+//   - verificationKey: The verification key of the issuer, under which the old signature is checked.
+//   - request: The holder's request; the issuer should check request.Revealed against its records.
+//   - updated: The new disclosed messages, for the same positions as request.Revealed.
+//   - nonce: The nonce the request was bound to.
+//
+// Returns:
+//   - models.Signature: The fresh signature.
+//   - error: An error if the request is malformed or its proof is invalid.
+func Issue(publicParams models.PublicParameters, signingKey models.SigningKey, verificationKey models.VerificationKey, request Request, updated map[int]string, nonce []byte) (models.Signature, error) {
+	if request.Commitment == nil || request.BlindingResponse == nil {
+		return models.Signature{}, errors.New("request is missing the commitment")
+	}
+	if _, ok := request.Proof.MHat[request.Blinding]; !ok {
+		return models.Signature{}, errors.New("blinding position is not hidden")
+	}
+	if len(updated) != len(request.Revealed) {
+		return models.Signature{}, errors.New("updated messages do not match the disclosed positions")
+	}
+	for i := range updated {
+		if _, ok := request.Revealed[i]; !ok {
+			return models.Signature{}, errors.New("updated messages do not match the disclosed positions")
+		}
+	}
+
+	// Step 1: Check the proof of the old signature
+	transcript, err := proof.TranscriptBytes(publicParams, request.Proof, request.Revealed)
+	if err != nil {
+		return models.Signature{}, err
+	}
+	if !proof.CheckPairing(publicParams, verificationKey, request.Proof) {
+		return models.Signature{}, errors.New("request proof is invalid")
+	}
+
+	// Step 2: Recompute T = h₁[b]^{r̂} · ∏_{kept} h₁[j]^{m̂_j} · H^{-c} and check the challenge
+	kept := make(map[int]bool, len(request.Proof.MHat))
+	for j := range request.Proof.MHat {
+		if j != request.Blinding {
+			kept[j] = true
+		}
+	}
+	points := []*e.G1{request.Commitment, &publicParams.H1[request.Blinding]}
+	scalars := []*e.Scalar{utils.Neg(request.Proof.Challenge), request.BlindingResponse}
+	for _, j := range sortedHidden(kept) {
+		points = append(points, &publicParams.H1[j])
+		scalars = append(scalars, request.Proof.MHat[j])
+	}
+	t := utils.MultiExpG1(points, scalars)
+	if updateChallenge(transcript, request.Blinding, request.Commitment, t, nonce).IsEqual(request.Proof.Challenge) != 1 {
+		return models.Signature{}, errors.New("request proof is invalid")
+	}
+
+	// Step 3: Sign C = g1 · H · ∏_{updated} h₁[i]^{m'_i}
+	c := new(e.G1)
+	c.Add(publicParams.G1, request.Commitment)
+	for i, message := range updated {
+		term := new(e.G1)
+		term.ScalarMult(utils.MessageToScalar(message), &publicParams.H1[i])
+		c.Add(c, term)
+	}
+	elem, err := sign.RandomE(signingKey)
+	if err != nil {
+		return models.Signature{}, err
+	}
+	return models.Signature{A: sign.ComputeA(signingKey.X, elem, c), E: elem}, nil
+}
+
+// Finalize verifies the fresh signature and returns the updated message vector.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - verificationKey: The verification key of the issuer.
+//   - updated: The new disclosed messages.
+//   - signature: The signature returned by Issue.
+//
+// Returns:
+//   - []string: The updated message vector.
+//   - error: An error if the signature does not verify.
+func (p *Pending) Finalize(publicParams models.PublicParameters, verificationKey models.VerificationKey, updated map[int]string, signature models.Signature) ([]string, error) {
+	if len(updated)+len(p.hidden) != len(publicParams.H1) {
+		return nil, errors.New("updated messages do not match the disclosed positions")
+	}
+	m := make([]string, len(publicParams.H1))
+	for i, message := range updated {
+		if _, ok := p.Request.Revealed[i]; !ok {
+			return nil, errors.New("updated messages do not match the disclosed positions")
+		}
+		m[i] = message
+	}
+	for j, message := range p.hidden {
+		m[j] = message
+	}
+	ok, err := verify.Verify(publicParams, verificationKey, m, signature)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("updated signature is invalid")
+	}
+	return m, nil
+}
+
+// sortedHidden returns the hidden indexes in increasing order.
+func sortedHidden(isHidden map[int]bool) []int {
+	indexes := make([]int, 0, len(isHidden))
+	for j := range isHidden {
+		indexes = append(indexes, j)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// updateChallenge hashes the proof transcript, the blinding position, the commitment H, the
+// commitment T and the nonce.
+func updateChallenge(transcript []byte, blinding int, commitment, t *e.G1, nonce []byte) *e.Scalar {
+	var position [8]byte
+	binary.BigEndian.PutUint64(position[:], uint64(blinding))
+	return utils.HashToScalar(
+		[]byte(updateDomain),
+		transcript,
+		position[:],
+		commitment.BytesCompressed(),
+		t.BytesCompressed(),
+		nonce,
+	)
+}
@@ -0,0 +1,111 @@
+package update
+
+import (
+	"testing"
+
+	"github.com/aniagut/msc-bbs-plus-plus/keygen"
+	"github.com/aniagut/msc-bbs-plus-plus/proof"
+	"github.com/aniagut/msc-bbs-plus-plus/sign"
+	"github.com/aniagut/msc-bbs-plus-plus/utils"
+	e "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestUpdate tests re-issuing a credential with a new address, the same hidden messages and a
+// fresh blinding message.
+func TestUpdate(t *testing.T) {
+	result, err := keygen.KeyGen(5)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	publicParams := result.PublicParameters
+	messages := []string{"holder-secret", "revocation-17", "old-blinding", "alice", "Old Street 1"}
+	signature, err := sign.Sign(publicParams, result.SigningKey, messages)
+	assert.NoError(t, err, "Sign should not return an error")
+	nonce := []byte("nonce")
+
+	pending, err := NewRequest(publicParams, signature, messages, []int{0, 1}, 2, nonce)
+	assert.NoError(t, err, "NewRequest should not return an error")
+	assert.Equal(t, map[int]string{3: "alice", 4: "Old Street 1"}, pending.Request.Revealed, "The request should reveal only the disclosed messages")
+
+	updated := map[int]string{3: "alice", 4: "New Street 2"}
+	fresh, err := Issue(publicParams, result.SigningKey, result.VerificationKey, pending.Request, updated, nonce)
+	assert.NoError(t, err, "Issue should not return an error")
+
+	m, err := pending.Finalize(publicParams, result.VerificationKey, updated, fresh)
+	assert.NoError(t, err, "Finalize should not return an error")
+	assert.Equal(t, []string{"holder-secret", "revocation-17"}, m[:2], "Finalize should keep the hidden messages")
+	assert.Equal(t, []string{"alice", "New Street 2"}, m[3:], "Finalize should use the updated messages")
+	assert.NotEqual(t, "old-blinding", m[2], "Finalize should use the fresh blinding message")
+
+	// The fresh credential can be presented like any other
+	p, err := proof.Prove(publicParams, fresh, m, []int{4}, []byte("presentation"))
+	assert.NoError(t, err, "Prove should not return an error")
+	isValid, err := proof.Verify(publicParams, result.VerificationKey, p, map[int]string{4: "New Street 2"}, []byte("presentation"))
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.True(t, isValid, "Verify should accept a presentation of the updated credential")
+}
+
+// TestUpdateHidesMessages tests that the issuer cannot test guesses of the hidden messages
+// against the commitment or link two updates of the same credential.
+func TestUpdateHidesMessages(t *testing.T) {
+	result, err := keygen.KeyGen(4)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	publicParams := result.PublicParameters
+	messages := []string{"holder-secret", "revocation-17", "old-blinding", "alice"}
+	signature, err := sign.Sign(publicParams, result.SigningKey, messages)
+	assert.NoError(t, err, "Sign should not return an error")
+
+	first, err := NewRequest(publicParams, signature, messages, []int{0, 1}, 2, []byte("nonce-1"))
+	assert.NoError(t, err, "NewRequest should not return an error")
+	second, err := NewRequest(publicParams, signature, messages, []int{0, 1}, 2, []byte("nonce-2"))
+	assert.NoError(t, err, "NewRequest should not return an error")
+	assert.False(t, first.Request.Commitment.IsEqual(second.Request.Commitment), "Two updates should have unlinkable commitments")
+
+	// A correct guess of all kept messages does not match the commitment
+	guess := utils.MultiExpG1(
+		[]*e.G1{&publicParams.H1[0], &publicParams.H1[1]},
+		[]*e.Scalar{utils.MessageToScalar("holder-secret"), utils.MessageToScalar("revocation-17")},
+	)
+	assert.False(t, guess.IsEqual(first.Request.Commitment), "The commitment should not be the unblinded product")
+}
+
+// TestInvalidUpdate tests that the issuer rejects requests it cannot trust.
+func TestInvalidUpdate(t *testing.T) {
+	result, err := keygen.KeyGen(4)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	publicParams := result.PublicParameters
+	messages := []string{"holder-secret", "old-blinding", "alice", "Old Street 1"}
+	signature, err := sign.Sign(publicParams, result.SigningKey, messages)
+	assert.NoError(t, err, "Sign should not return an error")
+	nonce := []byte("nonce")
+	updated := map[int]string{2: "alice", 3: "New Street 2"}
+
+	_, err = NewRequest(publicParams, signature, messages, []int{0, 1}, 1, nonce)
+	assert.Error(t, err, "NewRequest should reject carrying over the blinding message")
+
+	pending, err := NewRequest(publicParams, signature, messages, []int{0}, 1, nonce)
+	assert.NoError(t, err, "NewRequest should not return an error")
+
+	_, err = Issue(publicParams, result.SigningKey, result.VerificationKey, pending.Request, updated, []byte("other"))
+	assert.Error(t, err, "Issue should reject a request bound to another nonce")
+
+	_, err = Issue(publicParams, result.SigningKey, result.VerificationKey, pending.Request, map[int]string{0: "x", 3: "y"}, nonce)
+	assert.Error(t, err, "Issue should reject updates of hidden positions")
+
+	// Another hidden value cannot be smuggled into the new credential
+	forged := pending.Request
+	forged.Commitment = new(e.G1)
+	forged.Commitment.ScalarMult(utils.MessageToScalar("other-secret"), &publicParams.H1[0])
+	_, err = Issue(publicParams, result.SigningKey, result.VerificationKey, forged, updated, nonce)
+	assert.Error(t, err, "Issue should reject a commitment to other hidden messages")
+
+	// The blinding position must be one of the hidden messages of the proof
+	moved := pending.Request
+	moved.Blinding = 2
+	_, err = Issue(publicParams, result.SigningKey, result.VerificationKey, moved, updated, nonce)
+	assert.Error(t, err, "Issue should reject a blinding position that was disclosed")
+
+	other, err := keygen.KeyGen(4)
+	assert.NoError(t, err, "KeyGen should not return an error")
+	_, err = Issue(publicParams, other.SigningKey, other.VerificationKey, pending.Request, updated, nonce)
+	assert.Error(t, err, "Issue should reject a credential of another issuer")
+}
@@ -2,8 +2,13 @@ package utils
 
 import (
     "crypto/rand"
+    "crypto/sha256"
+    "crypto/sha512"
+    "encoding/binary"
+    "encoding/hex"
     "errors"
     "math/big"
+    "github.com/aniagut/msc-bbs-plus-plus/models"
     e "github.com/cloudflare/circl/ecc/bls12381"
 )
 
@@ -52,6 +57,17 @@ func RandomScalar() (e.Scalar, error) {
     return scalar, nil
 }
 
+// RandomMessage generates a random 256-bit message, hex encoded, for hidden attributes that
+// only carry entropy, such as blinding attributes and holder secrets.
+func RandomMessage() (string, error) {
+    randomBytes := make([]byte, 32)
+    _, err := rand.Read(randomBytes)
+    if err != nil {
+        return "", errors.New("failed to generate random message")
+    }
+    return hex.EncodeToString(randomBytes), nil
+}
+
 // OrderAsBigInt returns the order of the elliptic curve as a big.Int.
 func OrderAsBigInt() *big.Int {
     return new(big.Int).SetBytes(e.Order())
@@ -75,8 +91,7 @@ func ComputeCommitment(m []string, h1 []e.G1, g1 *e.G1) (*e.G1, error) {
 
     for i, message := range m {
         // Convert message to a scalar
-        mScalar := new(e.Scalar)
-        mScalar.SetBytes(SerializeString(message))
+        mScalar := MessageToScalar(message)
 
         // Compute h1[i]^m[i]
         h1Exp := new(e.G1)
@@ -87,4 +102,102 @@ func ComputeCommitment(m []string, h1 []e.G1, g1 *e.G1) (*e.G1, error) {
     }
 
     return C, nil
-}
\ No newline at end of file
+}
+
+// MessageToScalar converts a message to the scalar used for it in commitments.
+func MessageToScalar(message string) *e.Scalar {
+    mScalar := new(e.Scalar)
+    mScalar.SetBytes(SerializeString(message))
+    return mScalar
+}
+
+// HashToScalar hashes a list of byte strings to a scalar in Z_p.
+// Every input is length-prefixed, so that different splits of the same bytes hash differently.
+func HashToScalar(inputs ...[]byte) *e.Scalar {
+    h := sha512.New()
+    var length [8]byte
+    for _, input := range inputs {
+        binary.BigEndian.PutUint64(length[:], uint64(len(input)))
+        h.Write(length[:])
+        h.Write(input)
+    }
+
+    // Reduce the 512-bit digest modulo the group order
+    scalar := new(e.Scalar)
+    scalar.SetBytes(h.Sum(nil))
+    return scalar
+}
+
+// HashToG1 hashes a message to an element of G1 using the given domain separation tag.
+func HashToG1(message []byte, dst string) *e.G1 {
+    h := new(e.G1)
+    h.Hash(message, []byte(dst))
+    return h
+}
+
+// HashToG2 hashes a message to an element of G2 using the given domain separation tag.
+func HashToG2(message []byte, dst string) *e.G2 {
+    h := new(e.G2)
+    h.Hash(message, []byte(dst))
+    return h
+}
+
+// ScalarToBytes serializes a scalar to its 32-byte big-endian encoding.
+func ScalarToBytes(s *e.Scalar) []byte {
+    b, _ := s.MarshalBinary()
+    return b
+}
+
+// MultiExpG1 computes ∏_i points[i]^scalars[i] in G1.
+func MultiExpG1(points []*e.G1, scalars []*e.Scalar) *e.G1 {
+    result := new(e.G1)
+    result.SetIdentity()
+    term := new(e.G1)
+    for i := range points {
+        term.ScalarMult(scalars[i], points[i])
+        result.Add(result, term)
+    }
+    return result
+}
+
+// Neg returns -s without modifying s.
+func Neg(s *e.Scalar) *e.Scalar {
+    result := new(e.Scalar)
+    result.Set(s)
+    result.Neg()
+    return result
+}
+
+// One returns the scalar 1.
+func One() *e.Scalar {
+    result := new(e.Scalar)
+    result.SetOne()
+    return result
+}
+
+// RandomScalars generates n random scalars in Z_p*.
+func RandomScalars(n int) ([]*e.Scalar, error) {
+    scalars := make([]*e.Scalar, n)
+    for i := range scalars {
+        s, err := RandomScalar()
+        if err != nil {
+            return nil, err
+        }
+        scalars[i] = &s
+    }
+    return scalars, nil
+}
+
+// ParametersID computes an identifier of the public parameters (SHA-256 over all generators).
+func ParametersID(publicParams models.PublicParameters) []byte {
+    h := sha256.New()
+    h.Write(publicParams.G1.BytesCompressed())
+    h.Write(publicParams.G2.BytesCompressed())
+    for i := range publicParams.H1 {
+        h.Write(publicParams.H1[i].BytesCompressed())
+    }
+    if publicParams.H0 != nil {
+        h.Write(publicParams.H0.BytesCompressed())
+    }
+    return h.Sum(nil)
+}
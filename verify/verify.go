@@ -1,6 +1,8 @@
 package verify
 
 import (
+    "errors"
+
     e "github.com/cloudflare/circl/ecc/bls12381"
     "github.com/aniagut/msc-bbs-plus-plus/models"
     "github.com/aniagut/msc-bbs-plus-plus/utils"
@@ -24,6 +26,21 @@ func Verify(publicParams models.PublicParameters, verificationKey models.Verific
         return false, err
     }
 
+    return VerifyCommitment(publicParams, verificationKey, c, signature), nil
+}
+
+// VerifyCommitment checks a BBS++ signature on a precomputed commitment c = g1 * ∏_i h₁[i]^m[i],
+// as produced by blind issuance.
+//
+// Parameters:
+//   - publicParams: The public key of the system.
+//   - verificationKey: The key used for verifying the signature.
+//   - c: The commitment to the messages.
+//   - signature: The signature to be verified.
+//
+// Returns:
+//   - boolean: True if the signature is valid, false otherwise.
+func VerifyCommitment(publicParams models.PublicParameters, verificationKey models.VerificationKey, c *e.G1, signature models.Signature) bool {
     // Step 2: Check pairing e(a, g2^e · vk) ?= e(c, g2)
     // If equal, return true
     g2e := new(e.G2)
@@ -34,8 +51,85 @@ func Verify(publicParams models.PublicParameters, verificationKey models.Verific
     e1 = e.Pair(signature.A, g2e)
     e2 := new(e.Gt)
     e2 = e.Pair(c, publicParams.G2)
-    if !e1.IsEqual(e2) {
-        return false, nil
+    return e1.IsEqual(e2)
+}
+
+// BatchVerify checks many BBS++ signatures under one verification key with two pairings,
+// using a random linear combination of the verification equations.
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - verificationKey: The verification key of the system.
+//   - m: The messages, one vector per signature.
+//   - signatures: The signatures to be verified.
+//
+// Returns:
+//   - boolean: True if all signatures are valid (up to a negligible probability), false otherwise.
+//   - error: An error if the inputs are inconsistent.
+func BatchVerify(publicParams models.PublicParameters, verificationKey models.VerificationKey, m [][]string, signatures []models.Signature) (bool, error) {
+    if len(m) != len(signatures) {
+        return false, errors.New("number of messages does not match number of signatures")
+    }
+    commitments := make([]*e.G1, len(m))
+    for k := range m {
+        c, err := utils.ComputeCommitment(m[k], publicParams.H1, publicParams.G1)
+        if err != nil {
+            return false, err
+        }
+        commitments[k] = c
     }
-    return true, nil
-}
\ No newline at end of file
+    return BatchVerifyCommitments(publicParams, verificationKey, commitments, signatures)
+}
+
+// BatchVerifyCommitments checks many BBS++ signatures on precomputed commitments.
+// Every equation e(A_k, X₂ · g₂^{e_k}) = e(c_k, g₂) is raised to a random δ_k and the
+// equations are multiplied, which gives e(Σ δ_k A_k, X₂) = e(Σ δ_k (c_k - e_k A_k), g₂).
+//
+// Parameters:
+//   - publicParams: The public parameters of the system.
+//   - verificationKey: The verification key of the system.
+//   - commitments: The commitments, one per signature.
+//   - signatures: The signatures to be verified.
+//
+// Returns:
+//   - boolean: True if all signatures are valid (up to a negligible probability), false otherwise.
+//   - error: An error if the inputs are inconsistent or randomness generation fails.
+func BatchVerifyCommitments(publicParams models.PublicParameters, verificationKey models.VerificationKey, commitments []*e.G1, signatures []models.Signature) (bool, error) {
+    if len(commitments) != len(signatures) {
+        return false, errors.New("number of commitments does not match number of signatures")
+    }
+    if len(signatures) == 0 {
+        return true, nil
+    }
+    deltas, err := utils.RandomScalars(len(signatures))
+    if err != nil {
+        return false, err
+    }
+
+    // Step 1: Accumulate Σ δ_k A_k and Σ δ_k (c_k - e_k A_k)
+    left := new(e.G1)
+    left.SetIdentity()
+    right := new(e.G1)
+    right.SetIdentity()
+    for k, signature := range signatures {
+        if signature.A == nil || signature.E == nil || commitments[k] == nil || signature.A.IsIdentity() {
+            return false, nil
+        }
+        term := new(e.G1)
+        term.ScalarMult(deltas[k], signature.A)
+        left.Add(left, term)
+
+        deltaE := new(e.Scalar)
+        deltaE.Mul(deltas[k], signature.E)
+        deltaE.Neg()
+        term.ScalarMult(deltaE, signature.A)
+        right.Add(right, term)
+        term.ScalarMult(deltas[k], commitments[k])
+        right.Add(right, term)
+    }
+
+    // Step 2: Check e(Σ δ_k A_k, X₂) ?= e(Σ δ_k (c_k - e_k A_k), g₂)
+    e1 := e.Pair(left, verificationKey.X2)
+    e2 := e.Pair(right, publicParams.G2)
+    return e1.IsEqual(e2), nil
+}
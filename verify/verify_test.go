@@ -79,6 +79,71 @@ func TestVerifyInvalidSignature(t *testing.T) {
     assert.False(t, isValid, "Verify should return false for an invalid signature")
 }
 
+// TestVerifyCommitment tests that a signature is verified against its precomputed commitment only.
+func TestVerifyCommitment(t *testing.T) {
+    publicParams := models.PublicParameters{
+        G1: e.G1Generator(),
+        G2: e.G2Generator(),
+        H1: GenerateMockH1(2),
+    }
+    signingKey := models.SigningKey{
+        X: GenerateMockScalar(12345),
+    }
+    verificationKey := models.VerificationKey{
+        X2: new(e.G2),
+    }
+    verificationKey.X2.ScalarMult(signingKey.X, publicParams.G2)
+
+    messages := []string{"message1", "message2"}
+    signature, err := GenerateValidSignature(publicParams, signingKey, messages)
+    assert.NoError(t, err, "Signature generation should not return an error")
+
+    c, err := utils.ComputeCommitment(messages, publicParams.H1, publicParams.G1)
+    assert.NoError(t, err, "ComputeCommitment should not return an error")
+    assert.True(t, VerifyCommitment(publicParams, verificationKey, c, signature), "VerifyCommitment should accept the signed commitment")
+
+    other, err := utils.ComputeCommitment([]string{"message1", "other"}, publicParams.H1, publicParams.G1)
+    assert.NoError(t, err, "ComputeCommitment should not return an error")
+    assert.False(t, VerifyCommitment(publicParams, verificationKey, other, signature), "VerifyCommitment should reject another commitment")
+}
+
+// TestBatchVerify tests that a batch is accepted only if every signature in it is valid.
+func TestBatchVerify(t *testing.T) {
+    publicParams := models.PublicParameters{
+        G1: e.G1Generator(),
+        G2: e.G2Generator(),
+        H1: GenerateMockH1(2),
+    }
+    signingKey := models.SigningKey{
+        X: GenerateMockScalar(12345),
+    }
+    verificationKey := models.VerificationKey{
+        X2: new(e.G2),
+    }
+    verificationKey.X2.ScalarMult(signingKey.X, publicParams.G2)
+
+    messages := [][]string{{"a", "1"}, {"b", "2"}, {"c", "3"}}
+    signatures := make([]models.Signature, len(messages))
+    for k := range messages {
+        signature, err := GenerateValidSignature(publicParams, signingKey, messages[k])
+        assert.NoError(t, err, "Signature generation should not return an error")
+        signatures[k] = signature
+    }
+
+    isValid, err := BatchVerify(publicParams, verificationKey, messages, signatures)
+    assert.NoError(t, err, "BatchVerify should not return an error")
+    assert.True(t, isValid, "BatchVerify should accept valid signatures")
+
+    // Swapping two signatures makes both invalid
+    signatures[0], signatures[1] = signatures[1], signatures[0]
+    isValid, err = BatchVerify(publicParams, verificationKey, messages, signatures)
+    assert.NoError(t, err, "BatchVerify should not return an error")
+    assert.False(t, isValid, "BatchVerify should reject a batch with invalid signatures")
+
+    _, err = BatchVerify(publicParams, verificationKey, messages[:2], signatures)
+    assert.Error(t, err, "BatchVerify should reject a length mismatch")
+}
+
 // GenerateValidSignature generates a valid signature for testing.
 func GenerateValidSignature(publicParams models.PublicParameters, signingKey models.SigningKey, messages []string) (models.Signature, error) {
     // Compute commitment c